@@ -3,7 +3,8 @@ package display
 import "github.com/wailsapp/wails/v3/pkg/application"
 
 // ActionOpenWindow is an IPC message used to request a new window. It contains
-// the options for the new window.
+// the options for the new window and, optionally, the name of a window
+// template to layer them on.
 //
 // example:
 //
@@ -17,4 +18,121 @@ import "github.com/wailsapp/wails/v3/pkg/application"
 //	}
 type ActionOpenWindow struct {
 	application.WebviewWindowOptions
+	Template string `json:"template,omitempty"`
 }
+
+// EventNotificationAction is the name of the event emitted on the action bus
+// when the user clicks an action button on a desktop notification. The event
+// data is an `ActionNotificationAction`.
+const EventNotificationAction = "display:notification:action"
+
+// ActionNotificationAction is an IPC message describing a click on one of a
+// notification's action buttons.
+//
+// example:
+//
+//	app.Event.On(display.EventNotificationAction, func(e *application.CustomEvent) {
+//		action := e.Data.(display.ActionNotificationAction)
+//		log.Printf("notification %d: %s", action.ID, action.Action)
+//	})
+type ActionNotificationAction struct {
+	ID     uint32 `json:"id"`
+	Action string `json:"action"`
+}
+
+// EventShortcut is the name of the event emitted on the action bus when a
+// keyboard shortcut or its menu item is triggered. The event data is an
+// `ActionShortcut`.
+const EventShortcut = "display:shortcut"
+
+// EventShortcutsChanged is the name of the event emitted when the keymap
+// changes. The event data is the full keymap as a `[]Shortcut`.
+const EventShortcutsChanged = "display:shortcuts:changed"
+
+// ActionShortcut is an IPC message describing a triggered shortcut.
+type ActionShortcut struct {
+	Action string `json:"action"`
+	Window string `json:"window,omitempty"`
+}
+
+// EventSecondInstance is the name of the event emitted on the action bus when
+// a second launch forwards its arguments to the running instance. The event
+// data is an `ActionSecondInstance`.
+const EventSecondInstance = "display:instance:launched"
+
+// ActionSecondInstance is an IPC message carrying the command line and
+// working directory of a launch that was forwarded to the running instance.
+type ActionSecondInstance struct {
+	Args       []string `json:"args"`
+	WorkingDir string   `json:"workingDir"`
+}
+
+// EventDeepLink is the name of the event emitted on the action bus when a
+// custom URL scheme link is handled. The event data is a `DeepLink`.
+const EventDeepLink = "display:deeplink"
+
+// EventReady is the name of the event the "main" window emits once it has
+// finished loading. It closes the splash window, see `SplashOptions`.
+const EventReady = "display:ready"
+
+// EventNavigationRequest is the name of the event the in-page navigation
+// guard emits when a page in a window with a `NavigationPolicy` tries to load
+// another page. The event data carries the target as "url".
+const EventNavigationRequest = "display:navigation:request"
+
+// EventNavigationBlocked is the name of the event emitted on the action bus
+// when a navigation is blocked by a window's `NavigationPolicy`. The event
+// data is an `ActionNavigationBlocked`.
+const EventNavigationBlocked = "display:navigation:blocked"
+
+// ActionNavigationBlocked is an IPC message describing a blocked navigation.
+type ActionNavigationBlocked struct {
+	Window string `json:"window"`
+	URL    string `json:"url"`
+}
+
+// EventActionDenied is the name of the event sent to a window when it
+// invokes an action it lacks the capability for. Other windows do not receive
+// it. The event data is an `ActionDenied`.
+const EventActionDenied = "display:action:denied"
+
+// ActionDenied is an IPC message describing a refused action.
+type ActionDenied struct {
+	Window     string     `json:"window"`
+	Action     string     `json:"action"`
+	Capability Capability `json:"capability"`
+}
+
+// EventClipboardChanged is the name of the event sent to windows granted
+// `CapClipboardRead` when the clipboard changes. The event data is a
+// `ClipboardChange`.
+const EventClipboardChanged = "display:clipboard:changed"
+
+// EventFileDrop is the name of the event sent to a window when files are
+// dropped onto it. The event data is a `FileDrop` listing the accepted and
+// rejected files.
+const EventFileDrop = "display:filedrop"
+
+// EventContextMenuClick is the name of the event sent to a window when an
+// item of a context menu it opened is clicked. The event data is a
+// `ContextMenuClick`.
+const EventContextMenuClick = "display:contextmenu:click"
+
+// EventLocaleChanged is the name of the event emitted on the action bus when
+// the display locale changes. The event data is an `ActionLocaleChanged`.
+const EventLocaleChanged = "display:locale:changed"
+
+// ActionLocaleChanged is an IPC message carrying the new locale.
+type ActionLocaleChanged struct {
+	Locale string `json:"locale"`
+}
+
+// EventRecentChanged is the name of the event sent to windows granted
+// `CapRecent` when the recent items change. The event data is the
+// `[]RecentItem` list, pinned items first.
+const EventRecentChanged = "display:recent:changed"
+
+// EventRecentOpen is the name of the event sent to windows granted
+// `CapRecent` when a recent item is chosen from a menu. The event data is
+// the `RecentItem`.
+const EventRecentOpen = "display:recent:open"
@@ -0,0 +1,563 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// ErrUnknownMenuItem is returned when a menu item ID is not in the menu.
+var ErrUnknownMenuItem = errors.New("display: unknown menu item")
+
+// ErrDuplicateMenuItem is returned when a menu item is added with an ID that
+// is already in the menu.
+var ErrDuplicateMenuItem = errors.New("display: duplicate menu item")
+
+// Menu item IDs of the default application menu.
+const (
+	MenuIDWorkspace     = "workspace"
+	MenuIDWorkspaceNew  = "workspace.new"
+	MenuIDWorkspaceList = "workspace.list"
+)
+
+// AppMenuItem describes an item of the application menu. Labels are English
+// messages, translated into the current locale unless the item is
+// Untranslated.
+//
+// example:
+//
+//	item := display.AppMenuItem{
+//		ID:       "view.sidebar",
+//		Label:    "Show Sidebar",
+//		Checkbox: true,
+//		Checked:  true,
+//		OnClick:  toggleSidebar,
+//	}
+type AppMenuItem struct {
+	// ID addresses the item in `AppMenu` calls. Items without an ID cannot
+	// be changed once added.
+	ID    string
+	Label string
+	// Untranslated shows Label as it is, for labels such as file names that
+	// are not messages.
+	Untranslated bool
+	// Role makes the item one of the platform's standard menus or items.
+	Role application.Role
+	// Action, if set, runs the named shortcut action when the item is
+	// clicked and shows the action's accelerator.
+	Action string
+	// OnClick is called when the item is clicked.
+	OnClick  func()
+	Checkbox bool
+	Checked  bool
+	Disabled bool
+	// Separator makes the item a separator; its other fields but ID are
+	// ignored.
+	Separator bool
+	// Submenu makes the item a submenu, even while Items is empty.
+	Submenu bool
+	Items   []AppMenuItem
+}
+
+// appMenuNode is an item of the application menu and, once rendered, its
+// Wails menu item.
+type appMenuNode struct {
+	spec     AppMenuItem
+	parent   *appMenuNode
+	children []*appMenuNode
+	item     *application.MenuItem
+}
+
+// isSubmenu reports whether the node holds other items.
+func (n *appMenuNode) isSubmenu() bool {
+	return n.spec.Submenu || n.parent == nil
+}
+
+// AppMenu is the application menu, addressed by item ID. It is safe for
+// concurrent use. Get it from `Service.Menu`.
+type AppMenu struct {
+	s *Service
+
+	mu   sync.Mutex
+	root *appMenuNode
+	ids  map[string]*appMenuNode
+	// menu is the rendered Wails menu, once the app has started.
+	menu *application.Menu
+	// windows are the windows showing the menu on Windows, where each
+	// window holds its own copy of it.
+	windows map[uint]application.Window
+}
+
+// newAppMenu creates the default application menu.
+func newAppMenu(s *Service) *AppMenu {
+	m := &AppMenu{s: s, root: &appMenuNode{}, ids: map[string]*appMenuNode{}, windows: map[uint]application.Window{}}
+	var items []AppMenuItem
+	if runtime.GOOS == "darwin" {
+		items = append(items, AppMenuItem{Role: application.AppMenu})
+	}
+	items = append(items,
+		AppMenuItem{Role: application.FileMenu},
+		AppMenuItem{Role: application.ViewMenu},
+		AppMenuItem{Role: application.EditMenu},
+		AppMenuItem{ID: MenuIDWorkspace, Label: "Workspace", Items: []AppMenuItem{
+			{ID: MenuIDWorkspaceNew, Label: "New", Action: "workspace.new"},
+			{ID: MenuIDWorkspaceList, Label: "List", Action: "workspace.list"},
+			{Separator: true},
+			{ID: MenuIDOpenRecent, Label: "Open Recent", Submenu: true},
+		}},
+		AppMenuItem{Role: application.WindowMenu},
+		AppMenuItem{Role: application.HelpMenu},
+	)
+	_ = m.Update(func(b *MenuBatch) error { return b.Append("", items...) })
+	return m
+}
+
+// Menu returns the application menu.
+//
+// example:
+//
+//	menu := displayService.Menu()
+//	menu.SetEnabled(display.MenuIDWorkspaceNew, false)
+//	defer menu.SetEnabled(display.MenuIDWorkspaceNew, true)
+func (s *Service) Menu() *AppMenu {
+	return s.appMenu
+}
+
+// MenuBatch changes the application menu within `AppMenu.Update`.
+type MenuBatch struct {
+	m       *AppMenu
+	rebuild bool
+	// removed are the Wails items of removed nodes.
+	removed []*application.MenuItem
+	// changed are nodes whose Wails items must show their new state.
+	changed []*appMenuNode
+}
+
+// Update applies several changes to the menu, rebuilding it at most once.
+// Changes made before fn returns an error are kept.
+//
+// example:
+//
+//	err := displayService.Menu().Update(func(b *display.MenuBatch) error {
+//		if err := b.Remove("file.recent.1"); err != nil {
+//			return err
+//		}
+//		return b.InsertAfter(display.MenuIDWorkspaceList,
+//			display.AppMenuItem{Separator: true},
+//			display.AppMenuItem{ID: "workspace.close", Label: "Close", OnClick: closeWorkspace})
+//	})
+func (m *AppMenu) Update(fn func(b *MenuBatch) error) error {
+	m.mu.Lock()
+	b := &MenuBatch{m: m}
+	err := fn(b)
+	rebuild := b.rebuild && m.menu != nil
+	m.mu.Unlock()
+	removed := b.removed
+	switch {
+	case rebuild:
+		removed = append(removed, m.rebuild()...)
+	case len(b.changed) > 0:
+		m.refresh(b.changed)
+	}
+	m.s.forgetMenuItems(removed)
+	return err
+}
+
+// rebuild renders the menu again and shows the result. Native menus may only
+// be changed on the main thread, so the menu is rendered there. It returns
+// the Wails items it discarded.
+func (m *AppMenu) rebuild() []*application.MenuItem {
+	var removed []*application.MenuItem
+	var windows []application.Window
+	m.s.onMainThread(func() {
+		m.mu.Lock()
+		removed = m.render()
+		for _, window := range m.windows {
+			windows = append(windows, window)
+		}
+		m.mu.Unlock()
+		if m.s.app != nil {
+			m.menu.Update()
+		}
+	})
+	// Windows built their menu bars from the old items. SetMenu switches to
+	// the main thread itself, so it is called from here.
+	for _, window := range windows {
+		window.SetMenu(m.menu)
+	}
+	return removed
+}
+
+// refresh shows the current state of nodes on their Wails items, on the main
+// thread. Nodes removed or re-rendered since have no item, or a new one that
+// already shows it.
+func (m *AppMenu) refresh(nodes []*appMenuNode) {
+	m.s.onMainThread(func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for _, n := range nodes {
+			if n.item == nil || n.spec.Separator || n.spec.Role != application.NoRole {
+				continue
+			}
+			n.item.SetEnabled(!n.spec.Disabled)
+			if n.spec.Checkbox {
+				n.item.SetChecked(n.spec.Checked)
+			}
+			m.label(n.item, n.spec)
+		}
+	})
+}
+
+// showIn adds the menu bar to a window's options on Windows, where windows
+// do not show the application menu unless given it. Frameless windows have
+// no menu bar.
+func (m *AppMenu) showIn(config *WindowConfig, options *application.WebviewWindowOptions) {
+	if runtime.GOOS != "windows" || config.Frameless {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	options.Windows.Menu = m.menu
+}
+
+// track keeps the menu bar of a window opened with `showIn` up to date until
+// it closes.
+func (m *AppMenu) track(window application.Window, options application.WebviewWindowOptions) {
+	if options.Windows.Menu == nil {
+		return
+	}
+	m.mu.Lock()
+	m.windows[window.ID()] = window
+	m.mu.Unlock()
+	window.OnWindowEvent(events.Common.WindowClosing, func(*application.WindowEvent) {
+		m.mu.Lock()
+		delete(m.windows, window.ID())
+		m.mu.Unlock()
+	})
+}
+
+// SetEnabled enables or greys out an item.
+func (m *AppMenu) SetEnabled(id string, enabled bool) error {
+	return m.Update(func(b *MenuBatch) error { return b.SetEnabled(id, enabled) })
+}
+
+// SetChecked checks or unchecks a checkbox item.
+func (m *AppMenu) SetChecked(id string, checked bool) error {
+	return m.Update(func(b *MenuBatch) error { return b.SetChecked(id, checked) })
+}
+
+// SetLabel changes an item's label.
+func (m *AppMenu) SetLabel(id, label string) error {
+	return m.Update(func(b *MenuBatch) error { return b.SetLabel(id, label) })
+}
+
+// InsertAfter adds items after the item with the given ID.
+func (m *AppMenu) InsertAfter(id string, items ...AppMenuItem) error {
+	return m.Update(func(b *MenuBatch) error { return b.InsertAfter(id, items...) })
+}
+
+// Append adds items to the end of a submenu, or of the menu bar if id is
+// empty.
+func (m *AppMenu) Append(id string, items ...AppMenuItem) error {
+	return m.Update(func(b *MenuBatch) error { return b.Append(id, items...) })
+}
+
+// Remove removes an item and, for a submenu, everything in it.
+func (m *AppMenu) Remove(id string) error {
+	return m.Update(func(b *MenuBatch) error { return b.Remove(id) })
+}
+
+// Clear removes every item of a submenu.
+func (m *AppMenu) Clear(id string) error {
+	return m.Update(func(b *MenuBatch) error { return b.Clear(id) })
+}
+
+// Has reports whether the menu has an item with the given ID.
+func (m *AppMenu) Has(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.ids[id]
+	return ok
+}
+
+// Item returns the current state of an item. Its Items are not filled in.
+func (m *AppMenu) Item(id string) (AppMenuItem, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.ids[id]
+	if !ok {
+		return AppMenuItem{}, false
+	}
+	return n.spec, true
+}
+
+// node returns the item with the given ID.
+func (b *MenuBatch) node(id string) (*appMenuNode, error) {
+	n, ok := b.m.ids[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMenuItem, id)
+	}
+	return n, nil
+}
+
+// SetEnabled enables or greys out an item.
+func (b *MenuBatch) SetEnabled(id string, enabled bool) error {
+	n, err := b.node(id)
+	if err != nil {
+		return err
+	}
+	n.spec.Disabled = !enabled
+	b.changed = append(b.changed, n)
+	return nil
+}
+
+// SetChecked checks or unchecks a checkbox item.
+func (b *MenuBatch) SetChecked(id string, checked bool) error {
+	n, err := b.node(id)
+	if err != nil {
+		return err
+	}
+	n.spec.Checked = checked
+	b.changed = append(b.changed, n)
+	return nil
+}
+
+// SetLabel changes an item's label.
+func (b *MenuBatch) SetLabel(id, label string) error {
+	n, err := b.node(id)
+	if err != nil {
+		return err
+	}
+	n.spec.Label = label
+	b.changed = append(b.changed, n)
+	return nil
+}
+
+// InsertAfter adds items after the item with the given ID.
+func (b *MenuBatch) InsertAfter(id string, items ...AppMenuItem) error {
+	n, err := b.node(id)
+	if err != nil {
+		return err
+	}
+	at := slices.Index(n.parent.children, n) + 1
+	return b.insert(n.parent, at, items)
+}
+
+// Append adds items to the end of a submenu, or of the menu bar if id is
+// empty.
+func (b *MenuBatch) Append(id string, items ...AppMenuItem) error {
+	parent := b.m.root
+	if id != "" {
+		n, err := b.node(id)
+		if err != nil {
+			return err
+		}
+		if !n.isSubmenu() {
+			return fmt.Errorf("%w: %s is not a submenu", ErrUnknownMenuItem, id)
+		}
+		parent = n
+	}
+	return b.insert(parent, len(parent.children), items)
+}
+
+// Remove removes an item and, for a submenu, everything in it.
+func (b *MenuBatch) Remove(id string) error {
+	n, err := b.node(id)
+	if err != nil {
+		return err
+	}
+	n.parent.children = slices.DeleteFunc(n.parent.children, func(c *appMenuNode) bool { return c == n })
+	b.m.forget(n)
+	b.removed = append(b.removed, detach([]*appMenuNode{n})...)
+	b.rebuild = true
+	return nil
+}
+
+// Clear removes every item of a submenu.
+func (b *MenuBatch) Clear(id string) error {
+	n, err := b.node(id)
+	if err != nil {
+		return err
+	}
+	if !n.isSubmenu() {
+		return fmt.Errorf("%w: %s is not a submenu", ErrUnknownMenuItem, id)
+	}
+	for _, child := range n.children {
+		b.m.forget(child)
+	}
+	b.removed = append(b.removed, detach(n.children)...)
+	n.children = nil
+	b.rebuild = true
+	return nil
+}
+
+// insert adds items to parent at index at, after checking that none of their
+// IDs are taken.
+func (b *MenuBatch) insert(parent *appMenuNode, at int, items []AppMenuItem) error {
+	seen := map[string]bool{}
+	var check func(items []AppMenuItem) error
+	check = func(items []AppMenuItem) error {
+		for _, item := range items {
+			if item.ID != "" {
+				if _, taken := b.m.ids[item.ID]; taken || seen[item.ID] {
+					return fmt.Errorf("%w: %s", ErrDuplicateMenuItem, item.ID)
+				}
+				seen[item.ID] = true
+			}
+			if err := check(item.Items); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(items); err != nil {
+		return err
+	}
+	nodes := make([]*appMenuNode, len(items))
+	for i, item := range items {
+		nodes[i] = b.m.add(parent, item)
+	}
+	parent.children = slices.Insert(parent.children, at, nodes...)
+	b.rebuild = true
+	return nil
+}
+
+// add creates the node of an item and its children, indexing their IDs.
+func (m *AppMenu) add(parent *appMenuNode, item AppMenuItem) *appMenuNode {
+	n := &appMenuNode{spec: item, parent: parent}
+	n.spec.Submenu = item.Submenu || len(item.Items) > 0
+	n.spec.Items = nil
+	for _, child := range item.Items {
+		n.children = append(n.children, m.add(n, child))
+	}
+	if item.ID != "" {
+		m.ids[item.ID] = n
+	}
+	return n
+}
+
+// forget removes the IDs of a node and its children from the index.
+func (m *AppMenu) forget(n *appMenuNode) {
+	if n.spec.ID != "" {
+		delete(m.ids, n.spec.ID)
+	}
+	for _, child := range n.children {
+		m.forget(child)
+	}
+}
+
+// attach renders the menu into menu, which becomes the app's menu.
+func (m *AppMenu) attach(menu *application.Menu) {
+	m.mu.Lock()
+	m.menu = menu
+	removed := m.render()
+	m.mu.Unlock()
+	m.s.forgetMenuItems(removed)
+}
+
+// render replaces the content of the Wails menu with the current items. It
+// returns the Wails items it discarded.
+func (m *AppMenu) render() []*application.MenuItem {
+	removed := detach(m.root.children)
+	m.menu.Destroy()
+	m.populate(m.menu, m.root.children)
+	return removed
+}
+
+// detach unlinks nodes and their children from their Wails items, returning
+// the items.
+func detach(nodes []*appMenuNode) []*application.MenuItem {
+	var items []*application.MenuItem
+	for _, n := range nodes {
+		if n.item != nil {
+			items = append(items, n.item)
+			n.item = nil
+		}
+		items = append(items, detach(n.children)...)
+	}
+	return items
+}
+
+// label sets the label of an item's Wails item, translating it unless the
+// item is untranslated.
+func (m *AppMenu) label(item *application.MenuItem, spec AppMenuItem) {
+	if spec.Untranslated {
+		item.SetLabel(spec.Label)
+		return
+	}
+	m.s.localise(item, spec.Label)
+}
+
+// populate adds nodes to a Wails menu.
+func (m *AppMenu) populate(menu *application.Menu, nodes []*appMenuNode) {
+	count := 0
+	for _, n := range nodes {
+		spec := n.spec
+		switch {
+		case spec.Separator:
+			menu.AddSeparator()
+		case spec.Role != application.NoRole:
+			menu.AddRole(spec.Role)
+		case spec.Submenu:
+			submenu := menu.AddSubmenu(spec.Label)
+			m.populate(submenu, n.children)
+		case spec.Action != "":
+			m.s.addMenuAction(menu, spec.Label, spec.Action)
+		case spec.Checkbox:
+			menu.AddCheckbox(spec.Label, spec.Checked)
+		default:
+			menu.Add(spec.Label)
+		}
+		item := menu.ItemAt(count)
+		if item == nil {
+			// A role the platform does not have.
+			continue
+		}
+		count++
+		n.item = item
+		if spec.Separator || spec.Role != application.NoRole {
+			continue
+		}
+		m.label(item, spec)
+		if spec.Disabled {
+			item.SetEnabled(false)
+		}
+		if spec.Action == "" && !spec.Submenu {
+			item.OnClick(func(*application.Context) { m.click(n) })
+		}
+	}
+}
+
+// click records a checkbox's new state and calls the item's handler.
+func (m *AppMenu) click(n *appMenuNode) {
+	m.mu.Lock()
+	if n.spec.Checkbox && n.item != nil {
+		n.spec.Checked = n.item.Checked()
+	}
+	onClick := n.spec.OnClick
+	m.mu.Unlock()
+	if onClick != nil {
+		onClick()
+	}
+}
+
+// forgetMenuItems stops translating and updating the accelerators of menu
+// items that have been discarded.
+func (s *Service) forgetMenuItems(items []*application.MenuItem) {
+	if len(items) == 0 {
+		return
+	}
+	s.unlocalise(items...)
+	s.menuItemsMu.Lock()
+	defer s.menuItemsMu.Unlock()
+	for action, tracked := range s.menuItems {
+		s.menuItems[action] = slices.DeleteFunc(tracked, func(item *application.MenuItem) bool {
+			return slices.Contains(items, item)
+		})
+	}
+}
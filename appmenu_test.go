@@ -0,0 +1,196 @@
+package display
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// newTestAppMenu renders the application menu into a menu without an app.
+func newTestAppMenu(t *testing.T) (*Service, *application.Menu) {
+	t.Helper()
+	s, _ := New()
+	menu := application.NewMenu()
+	s.Menu().attach(menu)
+	return s, menu
+}
+
+// labels returns the labels of a menu's items.
+func labels(menu *application.Menu) []string {
+	var got []string
+	for i := 0; menu.ItemAt(i) != nil; i++ {
+		got = append(got, menu.ItemAt(i).Label())
+	}
+	return got
+}
+
+func TestAppMenuSetters(t *testing.T) {
+	s, menu := newTestAppMenu(t)
+	m := s.Menu()
+	if err := m.SetEnabled(MenuIDWorkspaceNew, false); err != nil {
+		t.Fatal(err)
+	}
+	newItem := menu.FindByLabel("New")
+	if newItem == nil || newItem.Enabled() {
+		t.Fatalf("Workspace > New = %+v, want it disabled", newItem)
+	}
+	if err := m.SetLabel(MenuIDWorkspaceNew, "New Workspace"); err != nil {
+		t.Fatal(err)
+	}
+	if newItem.Label() != "New Workspace" {
+		t.Errorf("label = %q", newItem.Label())
+	}
+	s.AddCatalogue("de", map[string]string{"New Workspace": "Neuer Arbeitsbereich"})
+	s.SetLocale("de")
+	if newItem.Label() != "Neuer Arbeitsbereich" {
+		t.Errorf("label after SetLocale = %q, want the new label translated", newItem.Label())
+	}
+	if err := m.SetChecked("missing", true); !errors.Is(err, ErrUnknownMenuItem) {
+		t.Errorf("SetChecked(missing) error = %v, want ErrUnknownMenuItem", err)
+	}
+}
+
+func TestAppMenuStructure(t *testing.T) {
+	s, menu := newTestAppMenu(t)
+	m := s.Menu()
+	clicked := 0
+	err := m.Update(func(b *MenuBatch) error {
+		if err := b.InsertAfter(MenuIDWorkspaceNew, AppMenuItem{ID: "workspace.open", Label: "Open", OnClick: func() { clicked++ }}); err != nil {
+			return err
+		}
+		if err := b.Append(MenuIDWorkspace, AppMenuItem{Separator: true}, AppMenuItem{ID: "workspace.lock", Label: "Locked", Checkbox: true}); err != nil {
+			return err
+		}
+		return b.Remove(MenuIDWorkspaceList)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspace := menu.FindByLabel("Workspace").GetSubmenu()
+	got := labels(workspace)
+	want := []string{"New", "Open", "", "Open Recent", "", "Locked"}
+	if len(got) != len(want) {
+		t.Fatalf("Workspace items = %q, want %q", got, want)
+	}
+	for i := range want {
+		if want[i] != "" && got[i] != want[i] {
+			t.Fatalf("Workspace items = %q, want %q", got, want)
+		}
+	}
+	if len(s.menuItems["workspace.list"]) != 0 {
+		t.Errorf("removed item still tracked for its accelerator")
+	}
+
+	m.click(m.ids["workspace.open"])
+	if clicked != 1 {
+		t.Errorf("OnClick called %d times", clicked)
+	}
+	lock := workspace.ItemAt(5)
+	lock.SetChecked(true)
+	m.click(m.ids["workspace.lock"])
+	if item, _ := m.Item("workspace.lock"); !item.Checked {
+		t.Errorf("checkbox state not recorded after a click")
+	}
+
+	// A rebuild keeps checkbox state and the disabled state.
+	m.SetEnabled("workspace.open", false)
+	m.Append("", AppMenuItem{ID: "tools", Label: "Tools", Submenu: true})
+	workspace = menu.FindByLabel("Workspace").GetSubmenu()
+	if !workspace.ItemAt(5).Checked() || workspace.ItemAt(1).Enabled() {
+		t.Errorf("rebuild lost item state")
+	}
+
+	if err := m.InsertAfter(MenuIDWorkspaceNew, AppMenuItem{ID: "workspace.open"}); !errors.Is(err, ErrDuplicateMenuItem) {
+		t.Errorf("duplicate ID error = %v", err)
+	}
+	if err := m.Append(MenuIDWorkspaceNew, AppMenuItem{Label: "x"}); !errors.Is(err, ErrUnknownMenuItem) {
+		t.Errorf("Append to a plain item error = %v", err)
+	}
+	if m.Has(MenuIDWorkspaceList) || !m.Has("tools") {
+		t.Errorf("Has() does not reflect the changes")
+	}
+}
+
+func TestAppMenuConcurrent(t *testing.T) {
+	s, _ := newTestAppMenu(t)
+	m := s.Menu()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				m.SetEnabled(MenuIDWorkspaceNew, j%2 == 0)
+				m.Append(MenuIDWorkspace, AppMenuItem{Label: "Recent"})
+			}
+		}()
+	}
+	wg.Wait()
+	if item, ok := m.Item(MenuIDWorkspaceNew); !ok || item.Label != "New" {
+		t.Errorf("Item() = %+v, %v", item, ok)
+	}
+}
+
+// fakeMainThread runs functions one at a time on a goroutine of its own, as
+// the Wails main thread does, until the test ends.
+func fakeMainThread(t *testing.T) func(fn func()) {
+	calls, done := make(chan func()), make(chan struct{})
+	go func() {
+		for {
+			select {
+			case fn := <-calls:
+				fn()
+			case <-done:
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() { close(done) })
+	return func(fn func()) {
+		finished := make(chan struct{})
+		calls <- func() {
+			defer close(finished)
+			fn()
+		}
+		<-finished
+	}
+}
+
+func TestAppMenuSettersOnMainThread(t *testing.T) {
+	s, menu := newTestAppMenu(t)
+	s.mainThread = fakeMainThread(t)
+	m := s.Menu()
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					m.SetEnabled(MenuIDWorkspaceNew, j%2 == 1)
+					m.SetLabel(MenuIDWorkspaceList, "List")
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					m.Append(MenuIDWorkspace, AppMenuItem{Label: "Recent"})
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("menu changes deadlocked")
+	}
+	if item := menu.FindByLabel("New"); item == nil || !item.Enabled() {
+		t.Errorf("Workspace > New = %+v, want it enabled", item)
+	}
+}
@@ -0,0 +1,110 @@
+package display
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ErrAutostartUnsupported is returned by `Service.SetAutostart` on platforms
+// other than Linux, Windows and macOS, where launching at login is managed by
+// the installer instead.
+var ErrAutostartUnsupported = errors.New("display: autostart is not supported on this platform")
+
+// BackgroundArg is passed to the app when it is launched at login by the
+// tray's "Start at Login" item with `Options.RunInBackground` set. The app
+// then starts with its windows hidden, in the system tray.
+const BackgroundArg = "--background"
+
+// SetAutostart makes the running executable launch with args when the user
+// logs in, or stops it from doing so. On Linux this writes or removes an XDG
+// autostart .desktop file, and an app running from an AppImage launches the
+// AppImage itself. On Windows it sets the app's value under the current
+// user's Run registry key, and on macOS it writes a LaunchAgent to
+// ~/Library/LaunchAgents.
+//
+// example:
+//
+//	err := displayService.SetAutostart(true, []string{display.BackgroundArg})
+func (s *Service) SetAutostart(enabled bool, args []string) error {
+	if !enabled {
+		return removeAutostart(s.autostartName())
+	}
+	exe, err := autostartExecutable()
+	if err != nil {
+		return err
+	}
+	return writeAutostart(s.autostartName(), s.appName(), exe, args)
+}
+
+// autostartExecutable returns the file to launch at login. An AppImage runs
+// from a mount that changes on every launch, so the image's own path, which
+// its runtime puts in $APPIMAGE, is used instead of the running executable.
+func autostartExecutable() (string, error) {
+	if image := os.Getenv("APPIMAGE"); image != "" {
+		return image, nil
+	}
+	return os.Executable()
+}
+
+// IsAutostartEnabled reports whether the app launches when the user logs in.
+func (s *Service) IsAutostartEnabled() bool {
+	return autostartEnabled(s.autostartName())
+}
+
+// autostartName returns the file name, without extension, of the app's
+// autostart entry.
+func (s *Service) autostartName() string {
+	name := unsafeInstanceChars.ReplaceAllString(strings.ToLower(s.instanceID()), "-")
+	if name == "" {
+		name = "core"
+	}
+	return name
+}
+
+// autostartArgs returns the arguments the tray's "Start at Login" item
+// launches the app with.
+func (s *Service) autostartArgs() []string {
+	if s.config.RunInBackground {
+		return []string{BackgroundArg}
+	}
+	return nil
+}
+
+// startInBackground reports whether the app was launched to start in the
+// system tray, see `BackgroundArg`.
+func (s *Service) startInBackground() bool {
+	return s.config.RunInBackground && slices.Contains(os.Args[1:], BackgroundArg)
+}
+
+// addAutostartItem adds the "Start at Login" checkbox to the tray menu on
+// platforms that support it.
+func (s *Service) addAutostartItem(menu *application.Menu) {
+	if !autostartSupported {
+		return
+	}
+	item := menu.AddCheckbox("Start at Login", s.IsAutostartEnabled())
+	s.localise(item, "Start at Login").OnClick(func(*application.Context) {
+		if err := s.SetAutostart(item.Checked(), s.autostartArgs()); err != nil {
+			s.app.Logger.Warn("Failed to change autostart", "error", err)
+			item.SetChecked(s.IsAutostartEnabled())
+		}
+	})
+}
+
+// autostartDesktopEntry returns the contents of an XDG autostart .desktop
+// file that launches exec with args.
+func autostartDesktopEntry(name, exec string, args []string) string {
+	command := desktopExecQuote(exec)
+	for _, arg := range args {
+		command += " " + desktopExecQuote(arg)
+	}
+	return desktopEntry{
+		Name:  name,
+		Exec:  command,
+		Extra: [][2]string{{"X-GNOME-Autostart-enabled", "true"}},
+	}.String()
+}
@@ -0,0 +1,89 @@
+//go:build darwin
+
+package display
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// autostartSupported reports whether this platform implements autostart.
+const autostartSupported = true
+
+// writeAutostart writes a launchd LaunchAgent for the app to
+// ~/Library/LaunchAgents, which launchd loads when the user logs in.
+func writeAutostart(file, name, executable string, args []string) error {
+	path, err := autostartPath(file)
+	if err != nil {
+		return fmt.Errorf("display: enabling autostart: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("display: enabling autostart: %w", err)
+	}
+	agent := autostartLaunchAgent(file, executable, args)
+	if err := os.WriteFile(path, []byte(agent), 0o644); err != nil {
+		return fmt.Errorf("display: enabling autostart: %w", err)
+	}
+	return nil
+}
+
+// removeAutostart removes the app's LaunchAgent. A missing file is not an
+// error.
+func removeAutostart(file string) error {
+	path, err := autostartPath(file)
+	if err != nil {
+		return fmt.Errorf("display: disabling autostart: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("display: disabling autostart: %w", err)
+	}
+	return nil
+}
+
+// autostartEnabled reports whether the app's LaunchAgent exists.
+func autostartEnabled(file string) bool {
+	path, err := autostartPath(file)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// autostartPath returns the path of the app's LaunchAgent.
+func autostartPath(file string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", file+".plist"), nil
+}
+
+// autostartLaunchAgent returns a launchd property list that runs executable
+// with args once, when the user logs in.
+func autostartLaunchAgent(label, executable string, args []string) string {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	b.WriteString("\t<key>Label</key>\n\t<string>" + plistEscape(label) + "</string>\n")
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{executable}, args...) {
+		b.WriteString("\t\t<string>" + plistEscape(arg) + "</string>\n")
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// plistEscape escapes s for a property list <string>.
+func plistEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,59 @@
+//go:build darwin
+
+package display
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAutostart(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	s, _ := NewWithOptions(Options{InstanceID: "Core Hub", RunInBackground: true})
+	path := filepath.Join(home, "Library", "LaunchAgents", "core-hub.plist")
+
+	if s.IsAutostartEnabled() {
+		t.Fatal("autostart enabled before SetAutostart")
+	}
+	if err := s.SetAutostart(true, s.autostartArgs()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exe, _ := os.Executable()
+	want := "\t\t<string>" + plistEscape(exe) + "</string>\n\t\t<string>--background</string>\n"
+	if !strings.Contains(string(data), want) || !strings.Contains(string(data), "<key>RunAtLoad</key>\n\t<true/>") {
+		t.Errorf("launch agent:\n%s\nwant %q", data, want)
+	}
+	if !s.IsAutostartEnabled() {
+		t.Error("IsAutostartEnabled() = false after enabling")
+	}
+
+	if err := s.SetAutostart(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("launch agent not removed: %v", err)
+	}
+	if err := s.SetAutostart(false, nil); err != nil {
+		t.Errorf("disabling twice = %v", err)
+	}
+}
+
+func TestAutostartLaunchAgent(t *testing.T) {
+	agent := autostartLaunchAgent("core", "/Applications/Core & Hub.app/Contents/MacOS/core", []string{"--profile=<work>"})
+	for _, want := range []string{
+		"<key>Label</key>\n\t<string>core</string>\n",
+		"<string>/Applications/Core &amp; Hub.app/Contents/MacOS/core</string>\n",
+		"<string>--profile=&lt;work&gt;</string>\n",
+	} {
+		if !strings.Contains(agent, want) {
+			t.Errorf("launch agent:\n%s\nwant %q", agent, want)
+		}
+	}
+}
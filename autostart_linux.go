@@ -0,0 +1,70 @@
+//go:build linux
+
+package display
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// autostartSupported reports whether this platform implements autostart.
+const autostartSupported = true
+
+// writeAutostart writes an XDG autostart .desktop file for the app.
+func writeAutostart(file, name, executable string, args []string) error {
+	path := autostartPath(file)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("display: enabling autostart: %w", err)
+	}
+	entry := autostartDesktopEntry(name, executable, args)
+	if err := os.WriteFile(path, []byte(entry), 0o644); err != nil {
+		return fmt.Errorf("display: enabling autostart: %w", err)
+	}
+	return nil
+}
+
+// removeAutostart removes the app's XDG autostart .desktop file. A missing
+// file is not an error.
+func removeAutostart(file string) error {
+	err := os.Remove(autostartPath(file))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("display: disabling autostart: %w", err)
+	}
+	return nil
+}
+
+// autostartEnabled reports whether the app's autostart file exists and has
+// not been switched off, as desktop session settings do with Hidden=true.
+func autostartEnabled(file string) bool {
+	f, err := os.Open(autostartPath(file))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		switch strings.TrimSpace(scanner.Text()) {
+		case "Hidden=true", "X-GNOME-Autostart-enabled=false":
+			return false
+		}
+	}
+	return true
+}
+
+// autostartPath returns the path of the app's autostart file.
+func autostartPath(file string) string {
+	return filepath.Join(xdgConfigHome(), "autostart", file+".desktop")
+}
+
+// xdgConfigHome returns $XDG_CONFIG_HOME, defaulting to ~/.config.
+func xdgConfigHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config")
+}
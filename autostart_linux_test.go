@@ -0,0 +1,77 @@
+//go:build linux
+
+package display
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAutostart(t *testing.T) {
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("APPIMAGE", "")
+	s, _ := NewWithOptions(Options{InstanceID: "Core Hub", RunInBackground: true})
+	path := filepath.Join(config, "autostart", "core-hub.desktop")
+
+	if s.IsAutostartEnabled() {
+		t.Fatal("autostart enabled before SetAutostart")
+	}
+	if err := s.SetAutostart(true, s.autostartArgs()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exe, _ := os.Executable()
+	if want := "Exec=" + desktopExecQuote(exe) + " --background\n"; !strings.Contains(string(data), want) {
+		t.Errorf("autostart entry:\n%s\nwant %q", data, want)
+	}
+	if !s.IsAutostartEnabled() {
+		t.Error("IsAutostartEnabled() = false after enabling")
+	}
+
+	// The desktop session's settings switch entries off with Hidden=true.
+	os.WriteFile(path, append(data, "Hidden=true\n"...), 0o644)
+	if s.IsAutostartEnabled() {
+		t.Error("IsAutostartEnabled() = true for a hidden entry")
+	}
+
+	if err := s.SetAutostart(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("autostart file not removed: %v", err)
+	}
+	if err := s.SetAutostart(false, nil); err != nil {
+		t.Errorf("disabling twice = %v", err)
+	}
+}
+
+func TestAutostartDesktopEntry(t *testing.T) {
+	entry := autostartDesktopEntry("Core", "/opt/Core Hub/core", []string{"--background", "--profile=work 2"})
+	want := "Exec=\"/opt/Core Hub/core\" --background \"--profile=work 2\"\n"
+	if !strings.Contains(entry, want) || !strings.Contains(entry, "X-GNOME-Autostart-enabled=true\n") {
+		t.Errorf("entry:\n%s\nwant %q", entry, want)
+	}
+}
+
+func TestAutostartAppImage(t *testing.T) {
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("APPIMAGE", "/home/user/Apps/Core Hub.AppImage")
+	s, _ := NewWithOptions(Options{InstanceID: "Core Hub"})
+	if err := s.SetAutostart(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(config, "autostart", "core-hub.desktop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Exec=\"/home/user/Apps/Core Hub.AppImage\"\n"; !strings.Contains(string(data), want) {
+		t.Errorf("autostart entry:\n%s\nwant %q", data, want)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !windows && !darwin
+
+package display
+
+// autostartSupported reports whether this platform implements autostart.
+const autostartSupported = false
+
+// writeAutostart is not implemented on this platform; launching at login is
+// set up by the installer instead.
+func writeAutostart(file, name, executable string, args []string) error {
+	return ErrAutostartUnsupported
+}
+
+// removeAutostart is not implemented on this platform.
+func removeAutostart(file string) error {
+	return ErrAutostartUnsupported
+}
+
+// autostartEnabled always reports false on this platform.
+func autostartEnabled(file string) bool {
+	return false
+}
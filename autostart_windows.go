@@ -0,0 +1,84 @@
+//go:build windows
+
+package display
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// autostartSupported reports whether this platform implements autostart.
+const autostartSupported = true
+
+const (
+	// autostartRunKey holds the commands Windows runs when the user logs in.
+	autostartRunKey = `Software\Microsoft\Windows\CurrentVersion\Run`
+	// autostartApprovedKey holds the switches Task Manager's Startup apps
+	// page keeps for the entries under autostartRunKey.
+	autostartApprovedKey = `Software\Microsoft\Windows\CurrentVersion\Explorer\StartupApproved\Run`
+)
+
+// writeAutostart sets the app's value under the current user's Run key.
+func writeAutostart(file, name, executable string, args []string) error {
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, autostartRunKey, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("display: enabling autostart: %w", err)
+	}
+	defer key.Close()
+	if err := key.SetStringValue(file, autostartCommand(executable, args)); err != nil {
+		return fmt.Errorf("display: enabling autostart: %w", err)
+	}
+	return nil
+}
+
+// removeAutostart deletes the app's Run value. A missing value is not an
+// error.
+func removeAutostart(file string) error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, autostartRunKey, registry.SET_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("display: disabling autostart: %w", err)
+	}
+	defer key.Close()
+	if err := key.DeleteValue(file); err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return fmt.Errorf("display: disabling autostart: %w", err)
+	}
+	return nil
+}
+
+// autostartEnabled reports whether the app's Run value exists and has not
+// been switched off in Task Manager, which marks it with an odd first byte
+// under the StartupApproved key.
+func autostartEnabled(file string) bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, autostartRunKey, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+	if _, _, err := key.GetStringValue(file); err != nil {
+		return false
+	}
+	approved, err := registry.OpenKey(registry.CURRENT_USER, autostartApprovedKey, registry.QUERY_VALUE)
+	if err != nil {
+		return true
+	}
+	defer approved.Close()
+	state, _, err := approved.GetBinaryValue(file)
+	return err != nil || len(state) == 0 || state[0]&1 == 0
+}
+
+// autostartCommand returns the command line Windows runs at login: the
+// quoted executable followed by args, escaped for CommandLineToArgvW.
+func autostartCommand(executable string, args []string) string {
+	command := []string{`"` + executable + `"`}
+	for _, arg := range args {
+		command = append(command, syscall.EscapeArg(arg))
+	}
+	return strings.Join(command, " ")
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package display
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+func TestAutostart(t *testing.T) {
+	s, _ := NewWithOptions(Options{InstanceID: fmt.Sprintf("display-test-%d", os.Getpid()), RunInBackground: true})
+	name := s.autostartName()
+	t.Cleanup(func() { removeAutostart(name) })
+
+	if s.IsAutostartEnabled() {
+		t.Fatal("autostart enabled before SetAutostart")
+	}
+	if err := s.SetAutostart(true, s.autostartArgs()); err != nil {
+		t.Fatal(err)
+	}
+	key, err := registry.OpenKey(registry.CURRENT_USER, autostartRunKey, registry.QUERY_VALUE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer key.Close()
+	command, _, err := key.GetStringValue(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exe, _ := os.Executable()
+	if want := `"` + exe + `" --background`; command != want {
+		t.Errorf("Run value = %q, want %q", command, want)
+	}
+	if !s.IsAutostartEnabled() {
+		t.Error("IsAutostartEnabled() = false after enabling")
+	}
+
+	if err := s.SetAutostart(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := key.GetStringValue(name); err != registry.ErrNotExist {
+		t.Errorf("Run value not removed: %v", err)
+	}
+	if err := s.SetAutostart(false, nil); err != nil {
+		t.Errorf("disabling twice = %v", err)
+	}
+}
+
+func TestAutostartCommand(t *testing.T) {
+	got := autostartCommand(`C:\Program Files\Core\core.exe`, []string{"--background", `--profile=work 2`})
+	if want := `"C:\Program Files\Core\core.exe" --background "--profile=work 2"`; got != want {
+		t.Errorf("autostartCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,183 @@
+package display
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// backgroundState remembers which windows were hidden to the system tray, in
+// stacking order, and which of them had focus.
+type backgroundState struct {
+	mu sync.Mutex
+	// focusOrder lists windows from least to most recently focused, which
+	// stands in for their stacking order.
+	focusOrder []string
+	hidden     []string
+	focused    string
+	quitting   bool
+}
+
+// focus moves a window to the top of the stacking order.
+func (b *backgroundState) focus(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.focusOrder = append(deleteString(b.focusOrder, name), name)
+}
+
+// forget removes a closed window.
+func (b *backgroundState) forget(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.focusOrder = deleteString(b.focusOrder, name)
+	b.hidden = deleteString(b.hidden, name)
+	if b.focused == name {
+		b.focused = ""
+	}
+}
+
+// stackOrder sorts windows from bottom to top: windows that have never had
+// focus first, in the order given, then the rest by when they last had it.
+func (b *backgroundState) stackOrder(names []string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	order := slices.Clone(names)
+	slices.SortStableFunc(order, func(x, y string) int {
+		return slices.Index(b.focusOrder, x) - slices.Index(b.focusOrder, y)
+	})
+	return order
+}
+
+// record remembers the windows hidden to the tray. Hiding when no window is
+// visible keeps the earlier record, so a second "Close Desktop" does not
+// forget what to restore.
+func (b *backgroundState) record(hidden []string, focused string) {
+	if len(hidden) == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hidden, b.focused = hidden, focused
+}
+
+// take returns and clears the windows hidden to the tray.
+func (b *backgroundState) take() ([]string, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	hidden, focused := b.hidden, b.focused
+	b.hidden, b.focused = nil, ""
+	return hidden, focused
+}
+
+// quit stops closing windows from hiding them, so the app can shut down.
+func (b *backgroundState) quit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.quitting = true
+}
+
+// isQuitting reports whether the app is shutting down.
+func (b *backgroundState) isQuitting() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.quitting
+}
+
+// HideToTray hides every visible window opened by the service, leaving the
+// app running in the system tray. `RestoreFromTray` shows them again.
+// Windows that were already hidden, and helper windows such as the tray's
+// own, are left alone.
+//
+// example:
+//
+//	displayService.HideToTray()
+func (s *Service) HideToTray() {
+	var hidden []string
+	focused := ""
+	for _, name := range s.background.stackOrder(s.windows.names()) {
+		window, ok := s.app.Window.GetByName(name)
+		if !ok || !window.IsVisible() {
+			continue
+		}
+		hidden = append(hidden, name)
+		if window.IsFocused() {
+			focused = name
+		}
+	}
+	if focused == "" && len(hidden) > 0 {
+		focused = hidden[len(hidden)-1]
+	}
+	s.background.record(hidden, focused)
+	for _, name := range hidden {
+		if window, ok := s.app.Window.GetByName(name); ok {
+			window.Hide()
+		}
+	}
+}
+
+// RestoreFromTray shows the windows hidden by `HideToTray` in their earlier
+// stacking order and focuses the one that had focus. If no windows were
+// hidden, such as when the app started in the background, "main" is shown.
+//
+// example:
+//
+//	displayService.RestoreFromTray()
+func (s *Service) RestoreFromTray() {
+	hidden, focused := s.background.take()
+	if len(hidden) == 0 {
+		hidden, focused = []string{"main"}, "main"
+	}
+	for _, name := range hidden {
+		if window, ok := s.app.Window.GetByName(name); ok {
+			window.Show()
+		}
+	}
+	if window, ok := s.app.Window.GetByName(focused); ok {
+		window.Focus()
+	}
+}
+
+// installBackgroundMode lets windows close during shutdown, which
+// `Options.RunInBackground` otherwise turns into hiding the last one.
+func (s *Service) installBackgroundMode() {
+	if s.config.RunInBackground {
+		s.app.OnShutdown(s.background.quit)
+	}
+}
+
+// watchBackground tracks a window's place in the stacking order and, with
+// `Options.RunInBackground`, hides the app to the tray instead of closing
+// its last visible window.
+func (s *Service) watchBackground(window application.Window, config *WindowConfig) {
+	name := config.Name
+	window.OnWindowEvent(events.Common.WindowFocus, func(*application.WindowEvent) {
+		s.background.focus(name)
+	})
+	if !s.config.RunInBackground {
+		return
+	}
+	window.RegisterHook(events.Common.WindowClosing, func(event *application.WindowEvent) {
+		if s.background.isQuitting() || s.othersVisible(name) {
+			return
+		}
+		event.Cancel()
+		s.HideToTray()
+	})
+}
+
+// othersVisible reports whether a window other than name and its
+// descendants, which close with it, is visible.
+func (s *Service) othersVisible(name string) bool {
+	closing := append(s.windows.descendants(name), name)
+	for _, other := range s.windows.names() {
+		if slices.Contains(closing, other) {
+			continue
+		}
+		if window, ok := s.app.Window.GetByName(other); ok && window.IsVisible() {
+			return true
+		}
+	}
+	return false
+}
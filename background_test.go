@@ -0,0 +1,43 @@
+package display
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBackgroundStackOrder(t *testing.T) {
+	b := &backgroundState{}
+	b.focus("main")
+	b.focus("settings")
+	b.focus("main")
+	got := b.stackOrder([]string{"main", "settings", "about"})
+	if want := []string{"about", "settings", "main"}; !slices.Equal(got, want) {
+		t.Errorf("stackOrder() = %q, want %q", got, want)
+	}
+	b.forget("main")
+	if got := b.stackOrder([]string{"settings", "about"}); !slices.Equal(got, []string{"about", "settings"}) {
+		t.Errorf("stackOrder() after forget = %q", got)
+	}
+}
+
+func TestBackgroundRecord(t *testing.T) {
+	b := &backgroundState{}
+	b.record([]string{"settings", "main"}, "main")
+	// Hiding again with nothing visible keeps what to restore.
+	b.record(nil, "")
+	b.forget("settings")
+	hidden, focused := b.take()
+	if !slices.Equal(hidden, []string{"main"}) || focused != "main" {
+		t.Errorf("take() = %q, %q", hidden, focused)
+	}
+	if hidden, _ := b.take(); hidden != nil {
+		t.Errorf("second take() = %q, want nothing", hidden)
+	}
+	if b.isQuitting() {
+		t.Fatal("quitting before quit()")
+	}
+	b.quit()
+	if !b.isQuitting() {
+		t.Error("not quitting after quit()")
+	}
+}
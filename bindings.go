@@ -0,0 +1,277 @@
+package display
+
+//go:generate go run ./cmd/display-bindgen -ts ui/src/app/display.generated.ts -schema schema/display.schema.json -openapi schema/openapi.json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// TypeScriptBindings returns TypeScript interfaces for every built-in action,
+// event and protocol type, with a typed `DisplayClient` for calling actions
+// and listening to events. It is written to ui/ by `go generate`.
+//
+// example:
+//
+//	ts, err := display.TypeScriptBindings()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	os.WriteFile("display.generated.ts", ts, 0o644)
+func TypeScriptBindings() ([]byte, error) {
+	doc := protocolSchema()
+	var b bytes.Buffer
+	b.WriteString("// Code generated by display-bindgen. DO NOT EDIT.\n")
+	b.WriteString("// Regenerate with `go generate` in the display module.\n")
+
+	for _, name := range slices.Sorted(maps.Keys(doc.Defs)) {
+		b.WriteString("\n")
+		writeTypeScriptDef(&b, name, doc.Defs[name])
+	}
+
+	actions := slices.Sorted(maps.Keys(doc.Actions))
+	b.WriteString("\n/** Names of the built-in actions. */\nexport const Actions = {\n")
+	for _, name := range actions {
+		fmt.Fprintf(&b, "  %s: %s,\n", doc.Actions[name].Key, tsString(name))
+	}
+	b.WriteString("} as const;\n\n/** Payloads of the built-in actions, by name. */\nexport interface ActionPayloads {\n")
+	for _, name := range actions {
+		action := doc.Actions[name]
+		if action.Capability == "" {
+			fmt.Fprintf(&b, "  /** %s */\n", action.Description)
+		} else {
+			fmt.Fprintf(&b, "  /** %s Requires %s. */\n", action.Description, tsString(string(action.Capability)))
+		}
+		fmt.Fprintf(&b, "  %s: %s;\n", tsString(name), tsType(action.Payload))
+	}
+	b.WriteString("}\n\n/** Results of the built-in actions, by name. */\nexport interface ActionResults {\n")
+	for _, name := range actions {
+		fmt.Fprintf(&b, "  %s: %s;\n", tsString(name), tsType(doc.Actions[name].Result))
+	}
+	b.WriteString("}\n\nexport type ActionName = keyof ActionPayloads;\n")
+
+	events := slices.Sorted(maps.Keys(doc.Events))
+	b.WriteString("\n/** Names of the events on the action bus. */\nexport const Events = {\n")
+	for _, name := range events {
+		fmt.Fprintf(&b, "  %s: %s,\n", doc.Events[name].Key, tsString(name))
+	}
+	b.WriteString("} as const;\n\n/** Data carried by each event, by name. */\nexport interface EventData {\n")
+	for _, name := range events {
+		event := doc.Events[name]
+		fmt.Fprintf(&b, "  /** %s */\n", event.Description)
+		fmt.Fprintf(&b, "  %s: %s;\n", tsString(name), tsType(event.Data))
+	}
+	b.WriteString("}\n\nexport type EventName = keyof EventData;\n")
+	b.WriteString(typeScriptClient)
+	return b.Bytes(), nil
+}
+
+// writeTypeScriptDef writes a named type.
+func writeTypeScriptDef(b *bytes.Buffer, name string, s *jsonSchema) {
+	enum := s
+	if len(s.AnyOf) > 0 {
+		enum = s.AnyOf[0]
+	}
+	if len(enum.Enum) > 0 {
+		values := make([]string, len(enum.Enum))
+		for i, v := range enum.Enum {
+			values[i] = tsLiteral(v)
+		}
+		union := strings.Join(values, " | ")
+		if len(s.AnyOf) > 0 {
+			union += " | (string & {})"
+		}
+		fmt.Fprintf(b, "export type %s = %s;\n\nexport const %s = {\n", name, union, name)
+		for i, v := range enum.Enum {
+			fmt.Fprintf(b, "  %s: %s,\n", enum.EnumNames[i], tsLiteral(v))
+		}
+		b.WriteString("} as const;\n")
+		return
+	}
+	fmt.Fprintf(b, "export interface %s {\n", name)
+	for _, prop := range s.order {
+		optional := "?"
+		if slices.Contains(s.Required, prop) {
+			optional = ""
+		}
+		fmt.Fprintf(b, "  %s%s: %s;\n", tsProperty(prop), optional, tsType(s.Properties[prop]))
+	}
+	b.WriteString("}\n")
+}
+
+// tsType returns the TypeScript type of a schema.
+func tsType(s *jsonSchema) string {
+	switch {
+	case s == nil:
+		return "null"
+	case s.Ref != "":
+		return strings.TrimPrefix(s.Ref, "#/$defs/")
+	}
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return tsType(s.Items) + "[]"
+	case "object":
+		if value, ok := s.AdditionalProperties.(*jsonSchema); ok {
+			return "Record<string, " + tsType(value) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// tsLiteral returns a value as a TypeScript literal.
+func tsLiteral(v any) string {
+	data, _ := json.Marshal(v)
+	var str string
+	if json.Unmarshal(data, &str) == nil {
+		return tsString(str)
+	}
+	return string(data)
+}
+
+// tsString returns a single-quoted TypeScript string.
+func tsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// tsProperty returns a property name, quoted if it is not an identifier.
+func tsProperty(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return tsString(name)
+		}
+	}
+	return name
+}
+
+// typeScriptClient is the hand-written part of the bindings: a client that
+// sends `ActionRequest` envelopes through the `Bridge` binding.
+const typeScriptClient = `
+/** Carries calls and events between the client and the display service. */
+export interface DisplayTransport {
+  invoke(request: ActionRequest): Promise<ActionReply>;
+  cancel(id: string): Promise<unknown>;
+  on(name: string, callback: (data: unknown) => void): () => void;
+}
+
+/** Uses the Wails runtime's bindings and events, from ` + "`window.wails`" + `. */
+export function wailsTransport(): DisplayTransport {
+  const wails = (globalThis as any).wails;
+  if (!wails?.Call || !wails?.Events) {
+    throw new Error('display: the Wails runtime is not loaded');
+  }
+  return {
+    invoke: (request) => wails.Call.ByName('github.com/Snider/display.Bridge.Invoke', request),
+    cancel: (id) => wails.Call.ByName('github.com/Snider/display.Bridge.CancelInvoke', id),
+    on: (name, callback) => wails.Events.On(name, (event: { data: unknown }) => callback(event.data)),
+  };
+}
+
+/** An action that failed, with the error code from its ` + "`ActionReply`" + `. */
+export class DisplayActionError extends Error {
+  constructor(
+    readonly action: string,
+    readonly code: string,
+    message: string,
+  ) {
+    super(message);
+    this.name = 'DisplayActionError';
+  }
+}
+
+export interface CallOptions {
+  /** Overrides the action's timeout on the service. */
+  timeoutMs?: number;
+  /** Cancels the action when aborted. */
+  signal?: AbortSignal;
+}
+
+/** A typed client for the display service's actions and events. */
+export class DisplayClient {
+  private readonly pending = new Map<
+    string,
+    { resolve: (result: any) => void; reject: (error: Error) => void }
+  >();
+  private nextID = 0;
+
+  constructor(private readonly transport: DisplayTransport = wailsTransport()) {}
+
+  /** Invokes an action and resolves with its result. */
+  call<A extends ActionName>(
+    action: A,
+    payload: ActionPayloads[A],
+    options: CallOptions = {},
+  ): Promise<ActionResults[A]> {
+    const id = String(++this.nextID);
+    return new Promise((resolve, reject) => {
+      if (options.signal?.aborted) {
+        reject(new DisplayActionError(action, 'cancelled', 'display: action cancelled'));
+        return;
+      }
+      this.pending.set(id, { resolve, reject });
+      options.signal?.addEventListener('abort', () => this.cancel(id), { once: true });
+      this.transport
+        .invoke({
+          id,
+          action,
+          payload: payload as unknown as Record<string, unknown>,
+          timeoutMs: options.timeoutMs,
+        } satisfies ActionRequest)
+        .then(
+          (reply) => this.settle(reply),
+          (error) => this.fail(id, error instanceof Error ? error : new Error(String(error))),
+        );
+    });
+  }
+
+  /** Listens for an event until the returned function is called. */
+  on<E extends EventName>(event: E, callback: (data: EventData[E]) => void): () => void {
+    return this.transport.on(event, (data) => callback(data as EventData[E]));
+  }
+
+  /** Cancels and rejects every pending call. */
+  close(): void {
+    for (const id of [...this.pending.keys()]) {
+      this.cancel(id);
+      this.fail(id, new Error('display: client closed'));
+    }
+  }
+
+  private cancel(id: string): void {
+    if (this.pending.has(id)) {
+      void this.transport.cancel(id).catch(() => {});
+    }
+  }
+
+  private fail(id: string, error: Error): void {
+    const call = this.pending.get(id);
+    if (call) {
+      this.pending.delete(id);
+      call.reject(error);
+    }
+  }
+
+  private settle(reply: ActionReply): void {
+    const call = this.pending.get(reply.id);
+    if (!call) {
+      return;
+    }
+    this.pending.delete(reply.id);
+    if (reply.error) {
+      call.reject(new DisplayActionError(reply.action, reply.error.code, reply.error.message));
+    } else {
+      call.resolve(reply.result ?? null);
+    }
+  }
+}
+`
@@ -0,0 +1,78 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestGeneratedFilesUpToDate fails when the protocol changes without
+// regenerating the bindings.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	generated := map[string]func() ([]byte, error){
+		"ui/src/app/display.generated.ts": TypeScriptBindings,
+		"schema/display.schema.json":      JSONSchema,
+		"schema/openapi.json":             OpenAPI,
+	}
+	for path, generate := range generated {
+		want, err := generate()
+		if err != nil {
+			t.Fatalf("generating %s: %v", path, err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("reading %s: %v", path, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is stale; run `go generate` in the display module", path)
+		}
+	}
+}
+
+func TestJSONSchemaDescribesProtocol(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Defs    map[string]json.RawMessage `json:"$defs"`
+		Actions map[string]struct {
+			Capability string `json:"capability"`
+		} `json:"x-actions"`
+		Events map[string]json.RawMessage `json:"x-events"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"WindowConfig", "ButtonState", "OpenWindowRequest", "ActionReply"} {
+		if _, ok := doc.Defs[name]; !ok {
+			t.Errorf("$defs is missing %s", name)
+		}
+	}
+	if got := doc.Actions[ActionNameWindowOpen].Capability; got != string(CapWindowOpen) {
+		t.Errorf("window.open capability = %q", got)
+	}
+	if len(doc.Events) != len(ProtocolEvents()) {
+		t.Errorf("x-events has %d events, want %d", len(doc.Events), len(ProtocolEvents()))
+	}
+}
+
+func TestTypeScriptBindings(t *testing.T) {
+	data, err := TypeScriptBindings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := string(data)
+	for _, want := range []string{
+		"export type ButtonState = 0 | 1 | 2;",
+		"  MinimiseButtonState?: ButtonState;",
+		"  'window.open': OpenWindowRequest;",
+		"export class DisplayClient {",
+	} {
+		if !strings.Contains(ts, want) {
+			t.Errorf("bindings are missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package display
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// Bridge holds the methods pages may call through the Wails bindings. Bind
+// it, and never the `Service` itself: every exported method of a bound type
+// can be called by every page, and the Service's methods are for Go code and
+// do not check the calling window's capabilities. Each Bridge method acts for
+// the window Wails passes in the call's context, so a page cannot claim to be
+// another window.
+//
+// example:
+//
+//	app := application.New(application.Options{
+//		Services: []application.Service{
+//			application.NewService(displayService.Bridge()),
+//		},
+//	})
+type Bridge struct {
+	s *Service
+}
+
+// Bridge returns the methods to bind for pages.
+func (s *Service) Bridge() *Bridge {
+	return &Bridge{s: s}
+}
+
+// callingWindow returns the window a binding call came from.
+func callingWindow(ctx context.Context) (application.Window, error) {
+	window, ok := ctx.Value(application.WindowKey).(application.Window)
+	if !ok {
+		return nil, fmt.Errorf("%w: the call has no window", ErrUnknownWindow)
+	}
+	return window, nil
+}
+
+// Invoke runs an action for the calling window, with its capabilities, and
+// returns the reply.
+//
+// example:
+//
+//	// From a frontend:
+//	const reply = await Call.ByName('github.com/Snider/display.Bridge.Invoke',
+//		{id: '1', action: 'notify', payload: {Title: 'Saved'}})
+func (b *Bridge) Invoke(ctx context.Context, req ActionRequest) ActionReply {
+	window, err := callingWindow(ctx)
+	if err != nil {
+		return ActionReply{ID: req.ID, Action: req.Action, Error: newActionError(err)}
+	}
+	return b.s.call(ctx, windowCall(window), req)
+}
+
+// CancelInvoke cancels a pending `Invoke` request made by the calling window.
+// It reports whether a request with that ID was still running.
+//
+// example:
+//
+//	// From a frontend:
+//	await Call.ByName('github.com/Snider/display.Bridge.CancelInvoke', '1')
+func (b *Bridge) CancelInvoke(ctx context.Context, id string) bool {
+	window, err := callingWindow(ctx)
+	if err != nil {
+		return false
+	}
+	return b.s.cancelAction(windowCall(window).caller(), id)
+}
+
+// UnlockKiosk checks the admin PIN entered in the kiosk unlock window, see
+// `Service.UnlockKiosk`. Other windows are refused.
+//
+// example:
+//
+//	// From the unlock page:
+//	await Call.ByName('github.com/Snider/display.Bridge.UnlockKiosk', pin)
+func (b *Bridge) UnlockKiosk(ctx context.Context, pin string) error {
+	window, err := callingWindow(ctx)
+	if err != nil {
+		return err
+	}
+	if window.Name() != kioskUnlockWindow {
+		return fmt.Errorf("%w: window %q cannot unlock kiosk mode", ErrPermissionDenied, window.Name())
+	}
+	return b.s.UnlockKiosk(pin)
+}
+
+// Ready signals that the "main" window has finished loading, see
+// `Service.Ready`. Calls from other windows are ignored.
+//
+// example:
+//
+//	// From the main window's page:
+//	await Call.ByName('github.com/Snider/display.Bridge.Ready')
+func (b *Bridge) Ready(ctx context.Context) {
+	if window, err := callingWindow(ctx); err == nil && window.Name() == "main" {
+		b.s.Ready()
+	}
+}
@@ -0,0 +1,37 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestBridgeActsForTheCallingWindow(t *testing.T) {
+	s, _ := New()
+	viewer := application.NewWindow(application.WebviewWindowOptions{Name: "viewer"})
+	s.grantCapabilities("main", viewer.ID()+1, nil)
+	s.grantCapabilities("viewer", viewer.ID(), []Capability{})
+	s.RegisterAction("test.echo", CapNotify, func(_ context.Context, call ActionCall) (any, error) {
+		return call.Window, nil
+	})
+	bridge := s.Bridge()
+
+	// The viewer holds no capabilities, whatever the request says.
+	ctx := context.WithValue(context.Background(), application.WindowKey, viewer)
+	if reply := bridge.Invoke(ctx, ActionRequest{Action: "test.echo"}); reply.Error == nil || reply.Error.Code != ActionErrorPermissionDenied {
+		t.Errorf("Invoke() from viewer = %+v, want permission denied", reply)
+	}
+	s.grantCapabilities("viewer", viewer.ID(), []Capability{CapNotify})
+	if reply := bridge.Invoke(ctx, ActionRequest{Action: "test.echo"}); reply.Error != nil || reply.Result != "viewer" {
+		t.Errorf("Invoke() from viewer = %+v, want it run for viewer", reply)
+	}
+	if reply := bridge.Invoke(context.Background(), ActionRequest{Action: "test.echo"}); reply.Error == nil {
+		t.Error("Invoke() without a calling window succeeded")
+	}
+
+	if err := bridge.UnlockKiosk(ctx, "1234"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("UnlockKiosk() from viewer error = %v, want ErrPermissionDenied", err)
+	}
+}
@@ -0,0 +1,181 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ErrPermissionDenied is matched by every `PermissionError`.
+var ErrPermissionDenied = errors.New("display: permission denied")
+
+// Capability is a right a window must be granted before it can invoke the
+// actions that require it.
+type Capability string
+
+const (
+	// CapWindowOpen lets a window open other windows.
+	CapWindowOpen Capability = "window:open"
+	// CapDialogFile lets a window show file open and save dialogs.
+	CapDialogFile Capability = "dialog:file"
+	// CapNotify lets a window show desktop notifications.
+	CapNotify Capability = "notify"
+	// CapTrayUpdate lets a window change the system tray tooltip and label.
+	CapTrayUpdate Capability = "tray:update"
+	// CapClipboardRead lets a window read the clipboard and be told when it
+	// changes.
+	CapClipboardRead Capability = "clipboard:read"
+	// CapClipboardWrite lets a window copy to and clear the clipboard.
+	CapClipboardWrite Capability = "clipboard:write"
+	// CapRecent lets a window list, add, pin and remove recently opened
+	// items, and be told when they change.
+	CapRecent Capability = "recent"
+)
+
+// DefaultCapabilities are granted to windows opened without
+// `WithCapabilities`. Using the clipboard and recent items must be granted
+// explicitly.
+var DefaultCapabilities = []Capability{CapWindowOpen, CapDialogFile, CapNotify, CapTrayUpdate}
+
+// trayCapabilities are the only rights of the hidden "system-tray" window.
+var trayCapabilities = []Capability{CapTrayUpdate}
+
+// PermissionError is returned when a window invokes an action it has not been
+// granted the capability for.
+type PermissionError struct {
+	Window     string
+	Action     string
+	Capability Capability
+}
+
+// Error describes the denied action.
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: window %q lacks %q for action %q", ErrPermissionDenied, e.Window, e.Capability, e.Action)
+}
+
+// Is makes `errors.Is(err, ErrPermissionDenied)` match.
+func (e *PermissionError) Is(target error) bool {
+	return target == ErrPermissionDenied
+}
+
+// WithCapabilities sets the capabilities granted to the window, replacing
+// `DefaultCapabilities`. Calling it with no capabilities grants none.
+//
+// example:
+//
+//	err := displayService.OpenWindow(
+//		display.WithName("viewer"),
+//		display.WithCapabilities(display.CapNotify),
+//	)
+func WithCapabilities(caps ...Capability) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.Capabilities = append([]Capability{}, caps...)
+	})
+}
+
+// narrowCapabilities returns the requested capabilities that are also in
+// granted, so that a window can never hand out more rights than it holds. A
+// nil request inherits everything granted.
+func narrowCapabilities(granted, requested []Capability) []Capability {
+	if requested == nil {
+		return slices.Clone(granted)
+	}
+	narrowed := []Capability{}
+	for _, c := range requested {
+		if slices.Contains(granted, c) && !slices.Contains(narrowed, c) {
+			narrowed = append(narrowed, c)
+		}
+	}
+	return narrowed
+}
+
+// grantCapabilities records the capabilities of the window with the given
+// name and Wails ID, falling back to `DefaultCapabilities` when caps is nil.
+// Grants belong to that window instance: another window opened later under
+// the same name starts with its own.
+func (s *Service) grantCapabilities(name string, id uint, caps []Capability) {
+	if caps == nil {
+		caps = DefaultCapabilities
+	}
+	s.grantsMu.Lock()
+	defer s.grantsMu.Unlock()
+	s.grants[id] = slices.Clone(caps)
+	s.windowIDs[name] = id
+}
+
+// revokeCapabilities drops the grants of a closed window.
+func (s *Service) revokeCapabilities(name string, id uint) {
+	s.grantsMu.Lock()
+	defer s.grantsMu.Unlock()
+	delete(s.grants, id)
+	if s.windowIDs[name] == id {
+		delete(s.windowIDs, name)
+	}
+}
+
+// windowID returns the Wails ID of the window with granted capabilities that
+// is open under name.
+func (s *Service) windowID(name string) (uint, bool) {
+	s.grantsMu.Lock()
+	defer s.grantsMu.Unlock()
+	id, ok := s.windowIDs[name]
+	return id, ok
+}
+
+// Capabilities returns the capabilities granted to a window. Windows not
+// opened by the service have none.
+//
+// example:
+//
+//	caps := displayService.Capabilities("main")
+func (s *Service) Capabilities(window string) []Capability {
+	id, ok := s.windowID(window)
+	if !ok {
+		return nil
+	}
+	return s.capabilities(id)
+}
+
+// HasCapability reports whether a window has been granted a capability.
+func (s *Service) HasCapability(window string, capability Capability) bool {
+	id, ok := s.windowID(window)
+	return ok && s.hasCapability(id, capability)
+}
+
+// capabilities returns the capabilities granted to the window with a Wails
+// ID.
+func (s *Service) capabilities(id uint) []Capability {
+	s.grantsMu.Lock()
+	defer s.grantsMu.Unlock()
+	return slices.Clone(s.grants[id])
+}
+
+// hasCapability reports whether the window with a Wails ID has been granted
+// a capability.
+func (s *Service) hasCapability(id uint, capability Capability) bool {
+	s.grantsMu.Lock()
+	defer s.grantsMu.Unlock()
+	return slices.Contains(s.grants[id], capability)
+}
+
+// denyAction audit-logs a refused action and tells the window that asked
+// for it, so that other windows do not learn what it tried.
+func (s *Service) denyAction(windowID uint, err *PermissionError) {
+	if s.app == nil {
+		return
+	}
+	s.app.Logger.Warn("Action denied", "window", err.Window, "action", err.Action, "capability", err.Capability)
+	if windowID == 0 {
+		return
+	}
+	window, ok := s.app.Window.GetByID(windowID)
+	if !ok {
+		return
+	}
+	window.DispatchWailsEvent(&application.CustomEvent{
+		Name: EventActionDenied,
+		Data: ActionDenied{Window: err.Window, Action: err.Action, Capability: err.Capability},
+	})
+}
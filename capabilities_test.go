@@ -0,0 +1,101 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDispatchChecksCapabilities(t *testing.T) {
+	s, _ := New()
+	s.grantCapabilities("main", 1, nil)
+	s.grantCapabilities("system-tray", 2, trayCapabilities)
+	s.RegisterAction("test.echo", CapNotify, func(_ context.Context, call ActionCall) (any, error) {
+		return call.Payload["text"], nil
+	})
+	ctx := context.Background()
+
+	got, err := s.Dispatch(ctx, "main", "test.echo", map[string]any{"text": "hi"})
+	if err != nil || got != "hi" {
+		t.Fatalf("Dispatch() from main = %v, %v", got, err)
+	}
+
+	for _, window := range []string{"system-tray", "unknown"} {
+		_, err := s.Dispatch(ctx, window, "test.echo", nil)
+		var denied *PermissionError
+		if !errors.As(err, &denied) || !errors.Is(err, ErrPermissionDenied) {
+			t.Fatalf("Dispatch() from %s error = %v, want a PermissionError", window, err)
+		}
+		if denied.Window != window || denied.Action != "test.echo" || denied.Capability != CapNotify {
+			t.Errorf("PermissionError = %+v", denied)
+		}
+	}
+
+	if _, err := s.Dispatch(ctx, "main", "test.missing", nil); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("Dispatch() unknown action error = %v, want ErrUnknownAction", err)
+	}
+}
+
+func TestTrayCapabilitiesAreMinimal(t *testing.T) {
+	s, _ := New()
+	s.grantCapabilities("system-tray", 2, trayCapabilities)
+	for _, c := range []Capability{CapWindowOpen, CapDialogFile, CapNotify} {
+		if s.HasCapability("system-tray", c) {
+			t.Errorf("system-tray has %q", c)
+		}
+	}
+	if !s.HasCapability("system-tray", CapTrayUpdate) {
+		t.Error("system-tray cannot update the tray")
+	}
+}
+
+func TestNarrowCapabilities(t *testing.T) {
+	granted := []Capability{CapWindowOpen, CapNotify}
+	tests := []struct {
+		name      string
+		requested []Capability
+		want      []Capability
+	}{
+		{name: "Inherit", requested: nil, want: granted},
+		{name: "Subset", requested: []Capability{CapNotify}, want: []Capability{CapNotify}},
+		{name: "No escalation", requested: []Capability{CapDialogFile, CapNotify}, want: []Capability{CapNotify}},
+		{name: "None", requested: []Capability{}, want: []Capability{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := narrowCapabilities(granted, tt.requested); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("narrowCapabilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCapabilities(t *testing.T) {
+	if config := buildWindowConfig(); config.Capabilities != nil {
+		t.Errorf("default Capabilities = %v, want nil", config.Capabilities)
+	}
+	config := buildWindowConfig(WithCapabilities())
+	if config.Capabilities == nil || len(config.Capabilities) != 0 {
+		t.Errorf("WithCapabilities() = %#v, want an empty grant", config.Capabilities)
+	}
+}
+
+func TestGrantsFollowWindowInstances(t *testing.T) {
+	s, _ := New()
+	s.grantCapabilities("settings", 1, []Capability{CapNotify})
+	// A new window with the same name replaces the old one's grants, and
+	// closing the old window must not revoke the new one's.
+	s.grantCapabilities("settings", 2, []Capability{CapWindowOpen})
+	s.revokeCapabilities("settings", 1)
+	if got := s.Capabilities("settings"); !reflect.DeepEqual(got, []Capability{CapWindowOpen}) {
+		t.Errorf("Capabilities(settings) = %v, want [%s]", got, CapWindowOpen)
+	}
+	if s.hasCapability(1, CapNotify) {
+		t.Error("a closed window kept its capabilities")
+	}
+	s.revokeCapabilities("settings", 2)
+	if s.HasCapability("settings", CapWindowOpen) {
+		t.Error("settings kept its capabilities after closing")
+	}
+}
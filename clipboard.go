@@ -0,0 +1,519 @@
+package display
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"golang.org/x/net/html"
+)
+
+// ErrClipboardUnavailable is returned when there is no system clipboard to
+// use, such as before the application has started.
+var ErrClipboardUnavailable = errors.New("display: clipboard is not available")
+
+// ErrClipboardFormatUnsupported is returned when the platform's clipboard
+// cannot hold a format.
+var ErrClipboardFormatUnsupported = errors.New("display: clipboard format is not supported on this platform")
+
+// ErrNotPNG is returned when image data written to the clipboard is not a
+// PNG image.
+var ErrNotPNG = errors.New("display: clipboard image is not a PNG")
+
+// pngSignature starts every PNG file.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// ClipboardFormat is the MIME type of a clipboard format.
+type ClipboardFormat string
+
+const (
+	// ClipboardText is UTF-8 plain text.
+	ClipboardText ClipboardFormat = "text/plain"
+	// ClipboardHTML is an HTML fragment.
+	ClipboardHTML ClipboardFormat = "text/html"
+	// ClipboardPNG is a PNG image.
+	ClipboardPNG ClipboardFormat = "image/png"
+)
+
+// clipboardFormats are the supported formats, richest first. Where the
+// platform can only hold one format, the first one set is written.
+var clipboardFormats = []ClipboardFormat{ClipboardHTML, ClipboardPNG, ClipboardText}
+
+// ClipboardContent is the content of the clipboard in each supported format.
+// Formats that are empty are absent.
+type ClipboardContent struct {
+	Text string `json:"text,omitempty"`
+	HTML string `json:"html,omitempty"`
+	// PNG is encoded as base64 in JSON.
+	PNG []byte `json:"png,omitempty"`
+}
+
+// get returns the content in one format, or nil if it is absent.
+func (c ClipboardContent) get(format ClipboardFormat) []byte {
+	switch format {
+	case ClipboardText:
+		if c.Text != "" {
+			return []byte(c.Text)
+		}
+	case ClipboardHTML:
+		if c.HTML != "" {
+			return []byte(c.HTML)
+		}
+	case ClipboardPNG:
+		if len(c.PNG) > 0 {
+			return c.PNG
+		}
+	}
+	return nil
+}
+
+// primaryFormat returns the richest format that is set, or text if none
+// are.
+func (c ClipboardContent) primaryFormat() ClipboardFormat {
+	for _, format := range clipboardFormats {
+		if c.get(format) != nil {
+			return format
+		}
+	}
+	return ClipboardText
+}
+
+// withTextFallback returns the content with plain text taken from its HTML
+// when only HTML is set, so that apps that cannot paste HTML still get the
+// text.
+func (c ClipboardContent) withTextFallback() ClipboardContent {
+	if c.Text == "" && c.HTML != "" {
+		c.Text = htmlText(c.HTML)
+	}
+	return c
+}
+
+// htmlText returns the text of an HTML fragment, with a line break after
+// each block element.
+func htmlText(fragment string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	skip := 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(z.Text())
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			tt := z.Token()
+			switch tt.Data {
+			case "script", "style":
+				if tt.Type == html.StartTagToken {
+					skip++
+				} else if tt.Type == html.EndTagToken && skip > 0 {
+					skip--
+				}
+			case "br":
+				b.WriteByte('\n')
+			case "p", "div", "li", "tr", "h1", "h2", "h3", "h4", "h5", "h6", "pre", "blockquote":
+				if tt.Type == html.EndTagToken {
+					b.WriteByte('\n')
+				}
+			}
+		}
+	}
+}
+
+// set stores the content in one format.
+func (c *ClipboardContent) set(format ClipboardFormat, data []byte) {
+	switch format {
+	case ClipboardText:
+		c.Text = string(data)
+	case ClipboardHTML:
+		c.HTML = string(data)
+	case ClipboardPNG:
+		c.PNG = data
+	}
+}
+
+// ClipboardChange is the data of an `EventClipboardChanged`. It lists the
+// formats now on the clipboard but never their content.
+type ClipboardChange struct {
+	Formats []ClipboardFormat `json:"formats"`
+}
+
+// clipboardBackend is implemented by each platform's clipboard.
+type clipboardBackend interface {
+	// formats lists the supported formats on the clipboard.
+	formats() ([]ClipboardFormat, error)
+	// read returns the clipboard content in a format it holds.
+	read(format ClipboardFormat) ([]byte, error)
+	// write replaces the clipboard content.
+	write(content ClipboardContent) error
+	// clear empties the clipboard.
+	clear() error
+}
+
+// Clipboard reads and writes the system clipboard as text, HTML and PNG
+// images. Get it from `Service.Clipboard`. On Linux, HTML and images need
+// wl-clipboard or xclip; without them, as on platforms other than Linux,
+// macOS and Windows, it holds plain text only and HTML and images fail with
+// `ErrClipboardFormatUnsupported`.
+//
+// In secure mode, or after `WriteSecret`, what was written is cleared from
+// the clipboard after a delay, unless something else has been copied since.
+type Clipboard struct {
+	service *Service
+	backend clipboardBackend
+
+	mu          sync.Mutex
+	secureFor   time.Duration
+	secretType  ClipboardFormat
+	secret      [sha256.Size]byte
+	secretTimer *time.Timer
+	fingerprint [sha256.Size]byte
+	handlers    map[int]func(ClipboardChange)
+	nextHandler int
+}
+
+// newClipboard creates a clipboard on a backend.
+func newClipboard(s *Service, backend clipboardBackend) *Clipboard {
+	return &Clipboard{service: s, backend: backend, handlers: map[int]func(ClipboardChange){}}
+}
+
+// Clipboard returns the system clipboard. Frontends use it through the
+// "clipboard.read", "clipboard.write" and "clipboard.clear" actions, which
+// require `CapClipboardRead` and `CapClipboardWrite`. Neither is granted by
+// default.
+//
+// example:
+//
+//	clip := displayService.Clipboard()
+//	err := clip.WriteSecret(address, 30*time.Second)
+func (s *Service) Clipboard() *Clipboard {
+	s.clipboardMu.Lock()
+	defer s.clipboardMu.Unlock()
+	if s.clipboard == nil {
+		s.clipboard = newClipboard(s, newClipboardBackend(s.app))
+	}
+	return s.clipboard
+}
+
+// Formats lists the supported formats on the clipboard.
+func (c *Clipboard) Formats() ([]ClipboardFormat, error) {
+	return c.backend.formats()
+}
+
+// Read returns the clipboard content in the given formats, or in every
+// supported format if none are given. Formats the clipboard does not hold are
+// left empty.
+//
+// example:
+//
+//	content, err := displayService.Clipboard().Read(display.ClipboardHTML, display.ClipboardText)
+func (c *Clipboard) Read(formats ...ClipboardFormat) (ClipboardContent, error) {
+	var content ClipboardContent
+	available, err := c.backend.formats()
+	if err != nil {
+		return content, err
+	}
+	if len(formats) == 0 {
+		formats = clipboardFormats
+	}
+	for _, format := range formats {
+		if !slices.Contains(available, format) {
+			continue
+		}
+		data, err := c.backend.read(format)
+		if err != nil {
+			return content, err
+		}
+		content.set(format, data)
+	}
+	return content, nil
+}
+
+// Text returns the clipboard's plain text.
+func (c *Clipboard) Text() (string, error) {
+	content, err := c.Read(ClipboardText)
+	return content.Text, err
+}
+
+// HTML returns the clipboard's HTML.
+func (c *Clipboard) HTML() (string, error) {
+	content, err := c.Read(ClipboardHTML)
+	return content.HTML, err
+}
+
+// PNG returns the clipboard's image, encoded as PNG.
+func (c *Clipboard) PNG() ([]byte, error) {
+	content, err := c.Read(ClipboardPNG)
+	return content.PNG, err
+}
+
+// SetText replaces the clipboard content with plain text.
+func (c *Clipboard) SetText(text string) error {
+	return c.Write(ClipboardContent{Text: text})
+}
+
+// SetHTML replaces the clipboard content with HTML.
+func (c *Clipboard) SetHTML(html string) error {
+	return c.Write(ClipboardContent{HTML: html})
+}
+
+// SetPNG replaces the clipboard content with a PNG image.
+func (c *Clipboard) SetPNG(png []byte) error {
+	return c.Write(ClipboardContent{PNG: png})
+}
+
+// Write replaces the clipboard content, offering every format that is set.
+// HTML without text is offered as plain text too. Where the platform can only
+// hold one format, HTML is preferred over an image, and an image over text.
+// Where it holds text only, HTML and images fail with
+// `ErrClipboardFormatUnsupported`. In secure mode the content is cleared
+// again after the secure delay.
+//
+// example:
+//
+//	err := displayService.Clipboard().Write(display.ClipboardContent{
+//		Text: "Hello",
+//		HTML: "<b>Hello</b>",
+//	})
+func (c *Clipboard) Write(content ClipboardContent) error {
+	c.mu.Lock()
+	clearAfter := c.secureFor
+	c.mu.Unlock()
+	return c.write(content, clearAfter)
+}
+
+// WriteSecret copies text, such as a wallet address or a password, and
+// clears it from the clipboard after clearAfter unless something else has
+// been copied since.
+//
+// example:
+//
+//	err := displayService.Clipboard().WriteSecret(seed, 20*time.Second)
+func (c *Clipboard) WriteSecret(text string, clearAfter time.Duration) error {
+	return c.write(ClipboardContent{Text: text}, clearAfter)
+}
+
+// SetSecureMode clears everything written through the clipboard after
+// clearAfter. Zero or less turns secure mode off; secrets already written
+// are still cleared.
+//
+// example:
+//
+//	displayService.Clipboard().SetSecureMode(30 * time.Second)
+func (c *Clipboard) SetSecureMode(clearAfter time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.secureFor = max(clearAfter, 0)
+}
+
+// SecureMode returns the delay after which written content is cleared, or
+// zero if secure mode is off.
+func (c *Clipboard) SecureMode() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.secureFor
+}
+
+// write replaces the clipboard content and, if clearAfter is positive,
+// schedules it to be cleared.
+func (c *Clipboard) write(content ClipboardContent, clearAfter time.Duration) error {
+	if len(content.PNG) > 0 && !bytes.HasPrefix(content.PNG, pngSignature) {
+		return ErrNotPNG
+	}
+	content = content.withTextFallback()
+	if err := c.backend.write(content); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.secretTimer != nil {
+		c.secretTimer.Stop()
+		c.secretTimer = nil
+	}
+	if clearAfter > 0 {
+		// Only the richest format is compared, as it is the one every
+		// platform keeps.
+		c.secretType = content.primaryFormat()
+		c.secret = sha256.Sum256(content.get(c.secretType))
+		c.secretTimer = time.AfterFunc(clearAfter, c.clearSecret)
+	}
+	c.mu.Unlock()
+	c.poll()
+	return nil
+}
+
+// clearSecret clears the clipboard if it still holds the secret written
+// last.
+func (c *Clipboard) clearSecret() {
+	c.mu.Lock()
+	c.secretTimer = nil
+	format, secret := c.secretType, c.secret
+	c.mu.Unlock()
+	content, err := c.Read(format)
+	if err != nil || sha256.Sum256(content.get(format)) != secret {
+		return
+	}
+	if err := c.Clear(); err != nil && c.service != nil && c.service.app != nil {
+		c.service.app.Logger.Warn("Failed to clear clipboard", "error", err)
+	}
+}
+
+// Clear empties the clipboard.
+func (c *Clipboard) Clear() error {
+	if err := c.backend.clear(); err != nil {
+		return err
+	}
+	c.poll()
+	return nil
+}
+
+// OnChange calls fn whenever the clipboard changes. The returned function
+// stops the calls. Changes made through the clipboard are noticed at once;
+// changes made by other applications only while the clipboard is watched,
+// see `Options.ClipboardPollInterval`.
+//
+// example:
+//
+//	stop := displayService.Clipboard().OnChange(func(change display.ClipboardChange) {
+//		log.Println("clipboard now holds", change.Formats)
+//	})
+//	defer stop()
+func (c *Clipboard) OnChange(fn func(ClipboardChange)) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.nextHandler
+	c.nextHandler++
+	c.handlers[id] = fn
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.handlers, id)
+	}
+}
+
+// watch polls the clipboard for changes until ctx is done.
+func (c *Clipboard) watch(ctx context.Context, interval time.Duration) {
+	c.poll()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.poll()
+		}
+	}
+}
+
+// poll compares the clipboard's formats and text with those seen last, and
+// announces a change to the `OnChange` handlers and to windows granted
+// `CapClipboardRead`. Images and HTML are not compared, so as not to read
+// them on every poll.
+func (c *Clipboard) poll() {
+	formats, err := c.backend.formats()
+	if err != nil {
+		return
+	}
+	h := sha256.New()
+	for _, format := range formats {
+		h.Write([]byte(string(format) + "\x00"))
+	}
+	if slices.Contains(formats, ClipboardText) {
+		text, err := c.backend.read(ClipboardText)
+		if err != nil {
+			return
+		}
+		h.Write(text)
+	}
+	var fingerprint [sha256.Size]byte
+	h.Sum(fingerprint[:0])
+	c.mu.Lock()
+	if fingerprint == c.fingerprint {
+		c.mu.Unlock()
+		return
+	}
+	c.fingerprint = fingerprint
+	handlers := make([]func(ClipboardChange), 0, len(c.handlers))
+	for _, id := range slices.Sorted(maps.Keys(c.handlers)) {
+		handlers = append(handlers, c.handlers[id])
+	}
+	c.mu.Unlock()
+
+	change := ClipboardChange{Formats: formats}
+	for _, fn := range handlers {
+		fn(change)
+	}
+	if c.service != nil {
+		c.service.announceClipboardChange(change)
+	}
+}
+
+// announceClipboardChange sends an `EventClipboardChanged` to every window
+// that may read the clipboard.
+func (s *Service) announceClipboardChange(change ClipboardChange) {
+	if s.app == nil {
+		return
+	}
+	for _, window := range s.app.Window.GetAll() {
+		if s.hasCapability(window.ID(), CapClipboardRead) {
+			window.DispatchWailsEvent(&application.CustomEvent{Name: EventClipboardChanged, Data: change})
+		}
+	}
+}
+
+// wailsClipboard is the clipboard of the Wails runtime, which holds plain
+// text only.
+type wailsClipboard struct {
+	app *application.App
+}
+
+// formats reports text if the clipboard holds any.
+func (w wailsClipboard) formats() ([]ClipboardFormat, error) {
+	if w.app == nil {
+		return nil, ErrClipboardUnavailable
+	}
+	if text, ok := w.app.Clipboard.Text(); ok && text != "" {
+		return []ClipboardFormat{ClipboardText}, nil
+	}
+	return []ClipboardFormat{}, nil
+}
+
+// read returns the clipboard's text.
+func (w wailsClipboard) read(format ClipboardFormat) ([]byte, error) {
+	if w.app == nil {
+		return nil, ErrClipboardUnavailable
+	}
+	if format != ClipboardText {
+		return nil, ErrClipboardFormatUnsupported
+	}
+	text, _ := w.app.Clipboard.Text()
+	return []byte(text), nil
+}
+
+// write replaces the clipboard's text. Other formats are unsupported.
+func (w wailsClipboard) write(content ClipboardContent) error {
+	if w.app == nil {
+		return ErrClipboardUnavailable
+	}
+	if content.HTML != "" || len(content.PNG) > 0 {
+		return ErrClipboardFormatUnsupported
+	}
+	if !w.app.Clipboard.SetText(content.Text) {
+		return ErrClipboardUnavailable
+	}
+	return nil
+}
+
+// clear empties the clipboard's text.
+func (w wailsClipboard) clear() error {
+	return w.write(ClipboardContent{})
+}
@@ -0,0 +1,140 @@
+//go:build darwin && cgo
+
+package display
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#import <AppKit/AppKit.h>
+#include <stdlib.h>
+
+// pasteboardType returns the pasteboard type of a format: 0 for text, 1 for
+// HTML and 2 for PNG.
+static NSPasteboardType pasteboardType(int format) {
+	switch (format) {
+	case 1:
+		return NSPasteboardTypeHTML;
+	case 2:
+		return NSPasteboardTypePNG;
+	}
+	return NSPasteboardTypeString;
+}
+
+static int pasteboardHas(int format) {
+	@autoreleasepool {
+		return [[NSPasteboard generalPasteboard] availableTypeFromArray:@[pasteboardType(format)]] != nil;
+	}
+}
+
+// pasteboardRead returns a malloc'd copy of the data in a format, or NULL if
+// the pasteboard does not hold it.
+static void *pasteboardRead(int format, size_t *length) {
+	@autoreleasepool {
+		NSData *data = [[NSPasteboard generalPasteboard] dataForType:pasteboardType(format)];
+		if (data == nil) {
+			return NULL;
+		}
+		*length = data.length;
+		void *copy = malloc(data.length > 0 ? data.length : 1);
+		memcpy(copy, data.bytes, data.length);
+		return copy;
+	}
+}
+
+static void pasteboardAdd(NSPasteboard *pasteboard, int format, const void *data, size_t length, BOOL *ok) {
+	if (data != NULL) {
+		*ok = [pasteboard setData:[NSData dataWithBytes:data length:length] forType:pasteboardType(format)] && *ok;
+	}
+}
+
+// pasteboardWrite replaces the pasteboard content with the formats whose
+// data is not NULL.
+static int pasteboardWrite(const void *text, size_t textLength, const void *html, size_t htmlLength, const void *png, size_t pngLength) {
+	@autoreleasepool {
+		NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+		[pasteboard clearContents];
+		BOOL ok = YES;
+		pasteboardAdd(pasteboard, 0, text, textLength, &ok);
+		pasteboardAdd(pasteboard, 1, html, htmlLength, &ok);
+		pasteboardAdd(pasteboard, 2, png, pngLength, &ok);
+		return ok;
+	}
+}
+
+static void pasteboardClear(void) {
+	@autoreleasepool {
+		[[NSPasteboard generalPasteboard] clearContents];
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// newClipboardBackend uses the general pasteboard, which holds text, HTML and
+// PNG images side by side.
+func newClipboardBackend(*application.App) clipboardBackend {
+	return pasteboardClipboard{}
+}
+
+// pasteboardClipboard is the macOS general pasteboard.
+type pasteboardClipboard struct{}
+
+// pasteboardFormats are the formats known to pasteboardType.
+var pasteboardFormats = map[ClipboardFormat]C.int{ClipboardText: 0, ClipboardHTML: 1, ClipboardPNG: 2}
+
+// formats lists the supported formats on the pasteboard.
+func (pasteboardClipboard) formats() ([]ClipboardFormat, error) {
+	formats := []ClipboardFormat{}
+	for _, format := range clipboardFormats {
+		if C.pasteboardHas(pasteboardFormats[format]) != 0 {
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
+
+// read returns the pasteboard content in one format.
+func (pasteboardClipboard) read(format ClipboardFormat) ([]byte, error) {
+	id, ok := pasteboardFormats[format]
+	if !ok {
+		return nil, ErrClipboardFormatUnsupported
+	}
+	var length C.size_t
+	data := C.pasteboardRead(id, &length)
+	if data == nil {
+		return nil, fmt.Errorf("display: reading clipboard: no %s on the pasteboard", format)
+	}
+	defer C.free(data)
+	return C.GoBytes(data, C.int(length)), nil
+}
+
+// write replaces the pasteboard content with every format set in content.
+func (pasteboardClipboard) write(content ClipboardContent) error {
+	text, html, png := content.get(ClipboardText), content.get(ClipboardHTML), content.get(ClipboardPNG)
+	if C.pasteboardWrite(cBytes(text), C.size_t(len(text)), cBytes(html), C.size_t(len(html)), cBytes(png), C.size_t(len(png))) == 0 {
+		return errors.New("display: writing clipboard: the pasteboard refused the content")
+	}
+	return nil
+}
+
+// clear empties the pasteboard.
+func (pasteboardClipboard) clear() error {
+	C.pasteboardClear()
+	return nil
+}
+
+// cBytes returns a pointer to data for the duration of a C call, or nil if
+// it is empty.
+func cBytes(data []byte) unsafe.Pointer {
+	if len(data) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&data[0])
+}
@@ -0,0 +1,114 @@
+//go:build linux && cgo
+
+package display
+
+/*
+#cgo pkg-config: gtk+-3.0
+#include <gtk/gtk.h>
+#include <string.h>
+
+enum { targetText, targetHTML, targetPNG, targetCount };
+
+// clipboardTargets is the content offered by the clipboard, by target.
+typedef struct {
+	guchar *data[targetCount];
+	gsize length[targetCount];
+} clipboardTargets;
+
+static void clipboardTargetsGet(GtkClipboard *clipboard, GtkSelectionData *selection, guint info, gpointer user) {
+	clipboardTargets *content = user;
+	if (info >= targetCount || content->data[info] == NULL) {
+		return;
+	}
+	if (info == targetText) {
+		gtk_selection_data_set_text(selection, (const gchar *)content->data[info], (gint)content->length[info]);
+		return;
+	}
+	gtk_selection_data_set(selection, gtk_selection_data_get_target(selection), 8, content->data[info], (gint)content->length[info]);
+}
+
+static void clipboardTargetsFree(GtkClipboard *clipboard, gpointer user) {
+	clipboardTargets *content = user;
+	for (int i = 0; i < targetCount; i++) {
+		g_free(content->data[i]);
+	}
+	g_free(content);
+}
+
+static void clipboardTargetsAdd(clipboardTargets *content, GtkTargetList *targets, int target, const void *data, gsize length) {
+	if (data == NULL) {
+		return;
+	}
+	content->data[target] = g_malloc(length);
+	memcpy(content->data[target], data, length);
+	content->length[target] = length;
+	switch (target) {
+	case targetText:
+		gtk_target_list_add_text_targets(targets, targetText);
+		break;
+	case targetHTML:
+		gtk_target_list_add(targets, gdk_atom_intern_static_string("text/html"), 0, targetHTML);
+		break;
+	case targetPNG:
+		gtk_target_list_add(targets, gdk_atom_intern_static_string("image/png"), 0, targetPNG);
+		break;
+	}
+}
+
+// clipboardTargetsSet offers the formats whose data is not NULL.
+static gboolean clipboardTargetsSet(const void *text, gsize textLength, const void *html, gsize htmlLength, const void *png, gsize pngLength) {
+	clipboardTargets *content = g_new0(clipboardTargets, 1);
+	GtkTargetList *targets = gtk_target_list_new(NULL, 0);
+	clipboardTargetsAdd(content, targets, targetText, text, textLength);
+	clipboardTargetsAdd(content, targets, targetHTML, html, htmlLength);
+	clipboardTargetsAdd(content, targets, targetPNG, png, pngLength);
+	gint count;
+	GtkTargetEntry *table = gtk_target_table_new_from_list(targets, &count);
+	gboolean ok = gtk_clipboard_set_with_data(gtk_clipboard_get(GDK_SELECTION_CLIPBOARD), table, count,
+		clipboardTargetsGet, clipboardTargetsFree, content);
+	gtk_target_table_free(table, count);
+	gtk_target_list_unref(targets);
+	if (!ok) {
+		clipboardTargetsFree(NULL, content);
+	}
+	return ok;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// errClipboardTargetsUnavailable is returned by `writeClipboardTargets` when
+// the clipboard cannot be written through GTK.
+var errClipboardTargetsUnavailable = errors.New("display: GTK clipboard is not available")
+
+// writeClipboardTargets offers every format set in content through the GTK
+// clipboard, which the app owns until something else is copied.
+func writeClipboardTargets(content ClipboardContent) error {
+	if application.Get() == nil {
+		return errClipboardTargetsUnavailable
+	}
+	text, html, png := content.get(ClipboardText), content.get(ClipboardHTML), content.get(ClipboardPNG)
+	var ok C.gboolean
+	application.InvokeSync(func() {
+		ok = C.clipboardTargetsSet(cBytes(text), C.gsize(len(text)), cBytes(html), C.gsize(len(html)), cBytes(png), C.gsize(len(png)))
+	})
+	if ok == 0 {
+		return errors.New("display: writing clipboard: GTK refused the content")
+	}
+	return nil
+}
+
+// cBytes returns a pointer to data for the duration of a C call, or nil if
+// it is empty.
+func cBytes(data []byte) unsafe.Pointer {
+	if len(data) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&data[0])
+}
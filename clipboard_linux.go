@@ -0,0 +1,145 @@
+//go:build linux
+
+package display
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// newClipboardBackend uses wl-clipboard on Wayland or xclip on X11, which
+// support rich formats, and falls back to the Wails runtime's text-only
+// clipboard when neither is installed.
+func newClipboardBackend(app *application.App) clipboardBackend {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if _, err := exec.LookPath("wl-paste"); err == nil {
+			return commandClipboard{wayland: true}
+		}
+	}
+	if os.Getenv("DISPLAY") != "" {
+		if _, err := exec.LookPath("xclip"); err == nil {
+			return commandClipboard{}
+		}
+	}
+	return wailsClipboard{app: app}
+}
+
+// commandClipboard drives the clipboard through wl-copy and wl-paste, or
+// xclip. Both hold one format at a time, so content in several formats is
+// written through GTK, which offers them all.
+type commandClipboard struct {
+	wayland bool
+}
+
+// formats lists the clipboard's targets.
+func (c commandClipboard) formats() ([]ClipboardFormat, error) {
+	var out []byte
+	var err error
+	if c.wayland {
+		out, err = exec.Command("wl-paste", "--list-types").Output()
+	} else {
+		out, err = exec.Command("xclip", "-selection", "clipboard", "-o", "-t", "TARGETS").Output()
+	}
+	if err != nil {
+		// Both tools fail when the clipboard is empty.
+		return []ClipboardFormat{}, nil
+	}
+	return parseClipboardTargets(string(out)), nil
+}
+
+// parseClipboardTargets maps MIME types and X11 targets, one per line, to
+// the supported formats.
+func parseClipboardTargets(targets string) []ClipboardFormat {
+	formats := []ClipboardFormat{}
+	add := func(f ClipboardFormat) {
+		if !slices.Contains(formats, f) {
+			formats = append(formats, f)
+		}
+	}
+	for _, target := range strings.Fields(targets) {
+		mime, _, _ := strings.Cut(target, ";")
+		switch {
+		case mime == string(ClipboardText), target == "UTF8_STRING", target == "STRING", target == "TEXT":
+			add(ClipboardText)
+		case mime == string(ClipboardHTML):
+			add(ClipboardHTML)
+		case mime == string(ClipboardPNG):
+			add(ClipboardPNG)
+		}
+	}
+	return formats
+}
+
+// read returns the clipboard content in one format.
+func (c commandClipboard) read(format ClipboardFormat) ([]byte, error) {
+	var cmd *exec.Cmd
+	if c.wayland {
+		cmd = exec.Command("wl-paste", "--no-newline", "--type", c.target(format))
+	} else {
+		cmd = exec.Command("xclip", "-selection", "clipboard", "-o", "-t", c.target(format))
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("display: reading clipboard: %w", err)
+	}
+	return out, nil
+}
+
+// write offers every format set in content. Without GTK, only the richest
+// is written.
+func (c commandClipboard) write(content ClipboardContent) error {
+	var set int
+	for _, format := range clipboardFormats {
+		if content.get(format) != nil {
+			set++
+		}
+	}
+	if set > 1 {
+		err := writeClipboardTargets(content)
+		if !errors.Is(err, errClipboardTargetsUnavailable) {
+			return err
+		}
+	}
+	format := content.primaryFormat()
+	var cmd *exec.Cmd
+	if c.wayland {
+		cmd = exec.Command("wl-copy", "--type", c.target(format))
+	} else {
+		cmd = exec.Command("xclip", "-selection", "clipboard", "-i", "-t", c.target(format))
+	}
+	cmd.Stdin = bytes.NewReader(content.get(format))
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("display: writing clipboard: %w", err)
+	}
+	return nil
+}
+
+// clear empties the clipboard. xclip cannot release the selection, so it is
+// replaced with empty text.
+func (c commandClipboard) clear() error {
+	if c.wayland {
+		if err := exec.Command("wl-copy", "--clear").Run(); err != nil {
+			return fmt.Errorf("display: clearing clipboard: %w", err)
+		}
+		return nil
+	}
+	return c.write(ClipboardContent{})
+}
+
+// target returns the tool's name for a format.
+func (c commandClipboard) target(format ClipboardFormat) string {
+	if format == ClipboardText {
+		if c.wayland {
+			return "text/plain;charset=utf-8"
+		}
+		return "UTF8_STRING"
+	}
+	return string(format)
+}
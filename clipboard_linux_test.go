@@ -0,0 +1,27 @@
+//go:build linux
+
+package display
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseClipboardTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets string
+		want    []ClipboardFormat
+	}{
+		{"Wayland", "text/html\ntext/plain;charset=utf-8\ntext/plain\nUTF8_STRING\n", []ClipboardFormat{ClipboardHTML, ClipboardText}},
+		{"X11", "TIMESTAMP\nTARGETS\nimage/png\n", []ClipboardFormat{ClipboardPNG}},
+		{"Empty", "", []ClipboardFormat{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseClipboardTargets(tt.targets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseClipboardTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+//go:build linux && !cgo
+
+package display
+
+import "errors"
+
+// errClipboardTargetsUnavailable is returned by `writeClipboardTargets` when
+// the clipboard cannot be written through GTK.
+var errClipboardTargetsUnavailable = errors.New("display: GTK clipboard is not available")
+
+// writeClipboardTargets needs cgo to reach GTK.
+func writeClipboardTargets(ClipboardContent) error {
+	return errClipboardTargetsUnavailable
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !windows && !(darwin && cgo)
+
+package display
+
+import "github.com/wailsapp/wails/v3/pkg/application"
+
+// newClipboardBackend uses the Wails runtime's clipboard, which holds plain
+// text only. Reading or writing HTML and images fails with
+// `ErrClipboardFormatUnsupported`.
+func newClipboardBackend(app *application.App) clipboardBackend {
+	return wailsClipboard{app: app}
+}
@@ -0,0 +1,178 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryClipboard is a clipboard backend that holds one format at a time,
+// like the Linux command-line tools.
+type memoryClipboard struct {
+	mu     sync.Mutex
+	format ClipboardFormat
+	data   []byte
+}
+
+func (m *memoryClipboard) formats() ([]ClipboardFormat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.format == "" {
+		return []ClipboardFormat{}, nil
+	}
+	return []ClipboardFormat{m.format}, nil
+}
+
+func (m *memoryClipboard) read(format ClipboardFormat) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if format != m.format {
+		return nil, ErrClipboardFormatUnsupported
+	}
+	return m.data, nil
+}
+
+func (m *memoryClipboard) write(content ClipboardContent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.format = content.primaryFormat()
+	m.data = content.get(m.format)
+	return nil
+}
+
+func (m *memoryClipboard) clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.format, m.data = "", nil
+	return nil
+}
+
+func newTestClipboard() (*Service, *Clipboard) {
+	s, _ := New()
+	s.clipboard = newClipboard(s, &memoryClipboard{})
+	return s, s.clipboard
+}
+
+func TestClipboardFormats(t *testing.T) {
+	_, clip := newTestClipboard()
+	if err := clip.Write(ClipboardContent{Text: "hi", HTML: "<b>hi</b>"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := clip.Read(); !reflect.DeepEqual(got, ClipboardContent{HTML: "<b>hi</b>"}) {
+		t.Errorf("Read() = %+v, want the HTML", got)
+	}
+	png := append([]byte{}, pngSignature...)
+	if err := clip.SetPNG(png); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := clip.PNG(); !reflect.DeepEqual(got, png) {
+		t.Errorf("PNG() = %v", got)
+	}
+	if err := clip.SetPNG([]byte("GIF89a")); !errors.Is(err, ErrNotPNG) {
+		t.Errorf("SetPNG(GIF) error = %v, want ErrNotPNG", err)
+	}
+	if text, err := clip.Text(); text != "" || err != nil {
+		t.Errorf("Text() of an image = %q, %v", text, err)
+	}
+}
+
+func TestClipboardTextFallback(t *testing.T) {
+	backend := &recordingClipboard{}
+	clip := newClipboard(nil, backend)
+	if err := clip.SetHTML("<p>Send <b>1 BTC</b></p><p>to<br>alice</p><script>x()</script>"); err != nil {
+		t.Fatal(err)
+	}
+	if want := (ClipboardContent{Text: "Send 1 BTC\nto\nalice", HTML: "<p>Send <b>1 BTC</b></p><p>to<br>alice</p><script>x()</script>"}); !reflect.DeepEqual(backend.written, want) {
+		t.Errorf("SetHTML() wrote %+v, want %+v", backend.written, want)
+	}
+	if err := clip.Write(ClipboardContent{Text: "plain", HTML: "<i>rich</i>"}); err != nil {
+		t.Fatal(err)
+	}
+	if backend.written.Text != "plain" {
+		t.Errorf("Write() replaced the text with %q", backend.written.Text)
+	}
+}
+
+// recordingClipboard is a clipboard backend that keeps what was written last.
+type recordingClipboard struct {
+	memoryClipboard
+	written ClipboardContent
+}
+
+func (r *recordingClipboard) write(content ClipboardContent) error {
+	r.written = content
+	return r.memoryClipboard.write(content)
+}
+
+func TestClipboardSecureClear(t *testing.T) {
+	_, clip := newTestClipboard()
+	if err := clip.WriteSecret("seed words", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := clip.Text(); text != "seed words" {
+		t.Fatalf("Text() = %q", text)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if text, _ := clip.Text(); text != "" {
+		t.Errorf("Text() after the delay = %q, want it cleared", text)
+	}
+
+	// Something copied since the secret is left alone.
+	clip.SetSecureMode(20 * time.Millisecond)
+	if err := clip.SetText("address"); err != nil {
+		t.Fatal(err)
+	}
+	clip.backend.write(ClipboardContent{Text: "copied elsewhere"})
+	time.Sleep(60 * time.Millisecond)
+	if text, _ := clip.Text(); text != "copied elsewhere" {
+		t.Errorf("Text() = %q, want another app's copy kept", text)
+	}
+}
+
+func TestClipboardOnChange(t *testing.T) {
+	_, clip := newTestClipboard()
+	var changes []ClipboardChange
+	stop := clip.OnChange(func(change ClipboardChange) { changes = append(changes, change) })
+	clip.SetText("one")
+	clip.SetText("one")
+	clip.backend.write(ClipboardContent{HTML: "<i>two</i>"})
+	clip.poll()
+	stop()
+	clip.Clear()
+	want := []ClipboardChange{{Formats: []ClipboardFormat{ClipboardText}}, {Formats: []ClipboardFormat{ClipboardHTML}}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+}
+
+func TestClipboardActions(t *testing.T) {
+	s, clip := newTestClipboard()
+	s.grantCapabilities("main", 1, nil)
+	s.grantCapabilities("wallet", 2, []Capability{CapClipboardRead, CapClipboardWrite})
+	ctx := context.Background()
+
+	// The clipboard is not among the default capabilities.
+	for _, action := range []string{ActionNameClipboardRead, ActionNameClipboardWrite, ActionNameClipboardClear} {
+		if reply := s.Call(ctx, "main", ActionRequest{Action: action}); reply.Error == nil || reply.Error.Code != ActionErrorPermissionDenied {
+			t.Errorf("%s from main = %+v, want permission denied", action, reply)
+		}
+	}
+	reply := s.Call(ctx, "wallet", ActionRequest{Action: ActionNameClipboardWrite, Payload: map[string]any{"text": "addr", "clearAfterMs": 20.0}})
+	if reply.Error != nil {
+		t.Fatalf("clipboard.write from wallet = %+v", reply.Error)
+	}
+	reply = s.Call(ctx, "wallet", ActionRequest{Action: ActionNameClipboardRead, Payload: map[string]any{"formats": []any{"text/plain"}}})
+	if got, _ := reply.Result.(ClipboardContent); got.Text != "addr" {
+		t.Errorf("clipboard.read = %+v", reply)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if text, _ := clip.Text(); text != "" {
+		t.Errorf("Text() = %q, want it cleared after clearAfterMs", text)
+	}
+	if reply := s.Call(ctx, "wallet", ActionRequest{Action: ActionNameClipboardRead, Payload: map[string]any{"formats": []any{"text/rtf"}}}); reply.Error == nil || reply.Error.Code != ActionErrorInvalidPayload {
+		t.Errorf("clipboard.read of an unknown format = %+v, want invalid payload", reply)
+	}
+}
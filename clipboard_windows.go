@@ -0,0 +1,263 @@
+//go:build windows
+
+package display
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// cfUnicodeText is the predefined clipboard format of UTF-16 text.
+	cfUnicodeText = 13
+	// gmemMoveable allocates the movable memory clipboard data must be in.
+	gmemMoveable = 0x0002
+)
+
+var (
+	user32   = windows.NewLazySystemDLL("user32.dll")
+	kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	procOpenClipboard              = user32.NewProc("OpenClipboard")
+	procCloseClipboard             = user32.NewProc("CloseClipboard")
+	procEmptyClipboard             = user32.NewProc("EmptyClipboard")
+	procGetClipboardData           = user32.NewProc("GetClipboardData")
+	procSetClipboardData           = user32.NewProc("SetClipboardData")
+	procIsClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	procRegisterClipboardFormatW   = user32.NewProc("RegisterClipboardFormatW")
+	procGlobalAlloc                = kernel32.NewProc("GlobalAlloc")
+	procGlobalFree                 = kernel32.NewProc("GlobalFree")
+	procGlobalLock                 = kernel32.NewProc("GlobalLock")
+	procGlobalUnlock               = kernel32.NewProc("GlobalUnlock")
+	procGlobalSize                 = kernel32.NewProc("GlobalSize")
+)
+
+// newClipboardBackend uses the Win32 clipboard, which holds text, HTML and
+// PNG images side by side.
+func newClipboardBackend(*application.App) clipboardBackend {
+	return win32Clipboard{}
+}
+
+// win32Clipboard is the Win32 clipboard. HTML is kept in the "HTML Format"
+// that browsers and Office use, and images in the registered "PNG" format.
+type win32Clipboard struct{}
+
+// win32ClipboardFormats returns the Win32 format of each supported format.
+// Formats that could not be registered are absent.
+var win32ClipboardFormats = sync.OnceValue(func() map[ClipboardFormat]uintptr {
+	ids := map[ClipboardFormat]uintptr{ClipboardText: cfUnicodeText}
+	for format, name := range map[ClipboardFormat]string{ClipboardHTML: "HTML Format", ClipboardPNG: "PNG"} {
+		ptr, err := windows.UTF16PtrFromString(name)
+		if err != nil {
+			continue
+		}
+		if id, _, _ := procRegisterClipboardFormatW.Call(uintptr(unsafe.Pointer(ptr))); id != 0 {
+			ids[format] = id
+		}
+	}
+	return ids
+})
+
+// withClipboard runs fn with the clipboard open. Another app may hold it
+// open for a moment, so opening it is retried briefly.
+func withClipboard(fn func() error) error {
+	// The clipboard is opened by, and must be closed from, one thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var err error
+	for range 20 {
+		var ok uintptr
+		if ok, _, err = procOpenClipboard.Call(0); ok != 0 {
+			defer procCloseClipboard.Call()
+			return fn()
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return fmt.Errorf("%w: %v", ErrClipboardUnavailable, err)
+}
+
+// formats lists the supported formats on the clipboard.
+func (win32Clipboard) formats() ([]ClipboardFormat, error) {
+	ids := win32ClipboardFormats()
+	formats := []ClipboardFormat{}
+	for _, format := range clipboardFormats {
+		if id, ok := ids[format]; ok {
+			if available, _, _ := procIsClipboardFormatAvailable.Call(id); available != 0 {
+				formats = append(formats, format)
+			}
+		}
+	}
+	return formats, nil
+}
+
+// read returns the clipboard content in one format.
+func (win32Clipboard) read(format ClipboardFormat) ([]byte, error) {
+	id, ok := win32ClipboardFormats()[format]
+	if !ok {
+		return nil, ErrClipboardFormatUnsupported
+	}
+	var data []byte
+	err := withClipboard(func() error {
+		handle, _, err := procGetClipboardData.Call(id)
+		if handle == 0 {
+			return fmt.Errorf("display: reading clipboard: %w", err)
+		}
+		ptr, _, err := procGlobalLock.Call(handle)
+		if ptr == 0 {
+			return fmt.Errorf("display: reading clipboard: %w", err)
+		}
+		defer procGlobalUnlock.Call(handle)
+		size, _, _ := procGlobalSize.Call(handle)
+		data = bytes.Clone(unsafe.Slice((*byte)(unsafe.Add(unsafe.Pointer(nil), ptr)), size))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case ClipboardText:
+		return []byte(decodeUTF16(data)), nil
+	case ClipboardHTML:
+		fragment, err := decodeCFHTML(data)
+		return []byte(fragment), err
+	}
+	return data, nil
+}
+
+// write replaces the clipboard content with every format set in content.
+func (win32Clipboard) write(content ClipboardContent) error {
+	ids := win32ClipboardFormats()
+	for _, format := range clipboardFormats {
+		if _, ok := ids[format]; !ok && content.get(format) != nil {
+			return ErrClipboardFormatUnsupported
+		}
+	}
+	return withClipboard(func() error {
+		if ok, _, err := procEmptyClipboard.Call(); ok == 0 {
+			return fmt.Errorf("display: writing clipboard: %w", err)
+		}
+		for _, format := range clipboardFormats {
+			data := content.get(format)
+			switch {
+			case data == nil:
+				continue
+			case format == ClipboardText:
+				data = encodeUTF16(content.Text)
+			case format == ClipboardHTML:
+				data = append(encodeCFHTML(content.HTML), 0)
+			}
+			if err := setClipboardData(ids[format], data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// clear empties the clipboard.
+func (win32Clipboard) clear() error {
+	return withClipboard(func() error {
+		if ok, _, err := procEmptyClipboard.Call(); ok == 0 {
+			return fmt.Errorf("display: clearing clipboard: %w", err)
+		}
+		return nil
+	})
+}
+
+// setClipboardData copies data into global memory and hands it to the open
+// clipboard, which owns it from then on.
+func setClipboardData(id uintptr, data []byte) error {
+	handle, _, err := procGlobalAlloc.Call(gmemMoveable, uintptr(len(data)))
+	if handle == 0 {
+		return fmt.Errorf("display: writing clipboard: %w", err)
+	}
+	ptr, _, err := procGlobalLock.Call(handle)
+	if ptr == 0 {
+		procGlobalFree.Call(handle)
+		return fmt.Errorf("display: writing clipboard: %w", err)
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Add(unsafe.Pointer(nil), ptr)), len(data)), data)
+	procGlobalUnlock.Call(handle)
+	if ok, _, err := procSetClipboardData.Call(id, handle); ok == 0 {
+		procGlobalFree.Call(handle)
+		return fmt.Errorf("display: writing clipboard: %w", err)
+	}
+	return nil
+}
+
+// encodeUTF16 returns text as NUL-terminated little-endian UTF-16.
+func encodeUTF16(text string) []byte {
+	units := append(utf16.Encode([]rune(text)), 0)
+	data := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		data = append(data, byte(u), byte(u>>8))
+	}
+	return data
+}
+
+// decodeUTF16 returns little-endian UTF-16 text up to its first NUL.
+func decodeUTF16(data []byte) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		u := uint16(data[i]) | uint16(data[i+1])<<8
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	return string(utf16.Decode(units))
+}
+
+// cfHTMLHeader is the header of the "HTML Format", with the byte offsets of
+// the document and of the fragment within it.
+const cfHTMLHeader = "Version:0.9\r\nStartHTML:%010d\r\nEndHTML:%010d\r\nStartFragment:%010d\r\nEndFragment:%010d\r\n"
+
+// encodeCFHTML wraps an HTML fragment in the "HTML Format".
+func encodeCFHTML(fragment string) []byte {
+	const prefix = "<html><body>\r\n<!--StartFragment-->"
+	const suffix = "<!--EndFragment-->\r\n</body></html>"
+	startHTML := len(fmt.Sprintf(cfHTMLHeader, 0, 0, 0, 0))
+	startFragment := startHTML + len(prefix)
+	endFragment := startFragment + len(fragment)
+	endHTML := endFragment + len(suffix)
+	return []byte(fmt.Sprintf(cfHTMLHeader, startHTML, endHTML, startFragment, endFragment) + prefix + fragment + suffix)
+}
+
+// decodeCFHTML returns the fragment held in the "HTML Format", or the whole
+// document if it marks no fragment.
+func decodeCFHTML(data []byte) (string, error) {
+	data = bytes.TrimRight(data, "\x00")
+	offset := func(name string) int {
+		_, rest, ok := bytes.Cut(data, []byte("\n"+name+":"))
+		if !ok {
+			return -1
+		}
+		line, _, _ := bytes.Cut(rest, []byte("\n"))
+		n, err := strconv.Atoi(strings.TrimSpace(string(line)))
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+	valid := func(start, end int) bool {
+		return start >= 0 && start <= end && end <= len(data)
+	}
+	if start, end := offset("StartFragment"), offset("EndFragment"); valid(start, end) {
+		return string(data[start:end]), nil
+	}
+	if start, end := offset("StartHTML"), offset("EndHTML"); valid(start, end) {
+		return string(data[start:end]), nil
+	}
+	return "", errors.New("display: reading clipboard: malformed HTML Format")
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package display
+
+import "testing"
+
+func TestCFHTML(t *testing.T) {
+	data := encodeCFHTML("<b>Hello</b>")
+	if got, err := decodeCFHTML(append(data, 0)); err != nil || got != "<b>Hello</b>" {
+		t.Errorf("decodeCFHTML(encodeCFHTML()) = %q, %v", got, err)
+	}
+
+	// As written by a browser, with its own markup around the fragment.
+	chrome := "Version:0.9\r\nStartHTML:0000000105\r\nEndHTML:0000000185\r\nStartFragment:0000000141\r\nEndFragment:0000000149\r\n<html>\r\n<body>\r\n<!--StartFragment--><i>x</i><!--EndFragment-->\r\n</body>\r\n</html>"
+	if got, err := decodeCFHTML([]byte(chrome)); err != nil || got != "<i>x</i>" {
+		t.Errorf("decodeCFHTML(Chrome) = %q, %v", got, err)
+	}
+	if _, err := decodeCFHTML([]byte("<b>no header</b>")); err == nil {
+		t.Error("decodeCFHTML() accepted data without a header")
+	}
+}
+
+func TestUTF16(t *testing.T) {
+	for _, text := range []string{"", "hello", "naïve ₿ 😀"} {
+		if got := decodeUTF16(encodeUTF16(text)); got != text {
+			t.Errorf("decodeUTF16(encodeUTF16(%q)) = %q", text, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/Snider/display"
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// uiDir is the built frontend served by the demo commands.
+const uiDir = "./ui/dist/display/browser"
+
+// runDemoApp runs a Wails application hosting the display service, serving
+// the built frontend. started is called once the service is up.
+func runDemoApp(options display.Options, started func(app *application.App, svc *display.Service)) error {
+	svc, err := display.NewWithOptions(options)
+	if err != nil {
+		return err
+	}
+	app := application.New(application.Options{
+		Name: "Display Demo",
+		// Pages get the display service's bridge, never the service itself.
+		Services: []application.Service{application.NewService(svc.Bridge())},
+		// Pages report their URL so that navigation policies are enforced.
+		RawMessageHandler: svc.HandleRawMessage,
+		Assets: application.AssetOptions{
+			Handler: application.AssetFileServerFS(os.DirFS(uiDir)),
+		},
+	})
+	app.Event.OnApplicationEvent(events.Common.ApplicationStarted, func(*application.ApplicationEvent) {
+		if err := svc.Startup(context.Background()); err != nil {
+			app.Logger.Error("Display service failed to start", "error", err)
+			app.Quit()
+			return
+		}
+		if started != nil {
+			started(app, svc)
+		}
+	})
+	return app.Run()
+}
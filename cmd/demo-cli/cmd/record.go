@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/Snider/display"
+	"github.com/spf13/cobra"
+)
+
+// recordCmd represents the record command
+var recordCmd = &cobra.Command{
+	Use:   "record <file.jsonl>",
+	Short: "Runs the demo app and records display actions",
+	Long: `Runs the demo app and records every display action and window event
+to a JSON lines file that can be played back with "replay".`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Recording to %s...", args[0])
+		if err := runDemoApp(display.Options{RecordPath: args[0]}, nil); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Snider/display"
+	"github.com/spf13/cobra"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+var (
+	replaySpeed    float64
+	replayHeadless bool
+	replayKeepOpen bool
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay <file.jsonl>",
+	Short: "Replays a recording made with record",
+	Long: `Replays a recording made with "record", either in the demo app or,
+with --headless, against a fake display service that prints each event.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		options := display.ReplayOptions{Speed: replaySpeed, OnEvent: printReplayed}
+		if replayHeadless {
+			if err := replayFile(args[0], display.NewFakeService(), options); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		err := runDemoApp(display.Options{}, func(app *application.App, svc *display.Service) {
+			go func() {
+				if err := replayFile(args[0], svc, options); err != nil {
+					app.Logger.Error("Replay failed", "error", err)
+				}
+				if !replayKeepOpen {
+					app.Quit()
+				}
+			}()
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// replayFile replays a recording file against target.
+func replayFile(path string, target display.ReplayTarget, options display.ReplayOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return display.Replay(context.Background(), f, target, options)
+}
+
+// printReplayed prints each replayed event and its outcome.
+func printReplayed(event display.RecordedEvent, reply *display.ActionReply, err error) {
+	switch {
+	case err != nil:
+		fmt.Printf("%s %s %s%s: %v\n", event.Time.Format("15:04:05.000"), event.Window, event.Action, event.Event, err)
+	case reply != nil && reply.Error != nil:
+		fmt.Printf("%s %s %s: %s\n", event.Time.Format("15:04:05.000"), event.Window, event.Action, reply.Error.Message)
+	default:
+		fmt.Printf("%s %s %s%s\n", event.Time.Format("15:04:05.000"), event.Window, event.Action, event.Event)
+	}
+}
+
+func init() {
+	replayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "playback speed; 0 replays without waiting")
+	replayCmd.Flags().BoolVar(&replayHeadless, "headless", false, "replay against a fake service instead of the demo app")
+	replayCmd.Flags().BoolVar(&replayKeepOpen, "keep-open", false, "keep the demo app running after the replay")
+	rootCmd.AddCommand(replayCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/Snider/display"
+	"github.com/spf13/cobra"
+)
+
+var schemaOpenAPI bool
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Prints the JSON Schema of the display protocol",
+	Long: `Prints the JSON Schema of every display action and event, or with
+--openapi the OpenAPI document of the HTTP API started by "serve".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		generate := display.JSONSchema
+		if schemaOpenAPI {
+			generate = display.OpenAPI
+		}
+		data, err := generate()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := os.Stdout.Write(data); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	schemaCmd.Flags().BoolVar(&schemaOpenAPI, "openapi", false, "print the OpenAPI document instead")
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/Snider/display"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +19,8 @@ var serveCmd = &cobra.Command{
 			fmt.Fprintf(w, "Hello, world!")
 		})
 
+		http.Handle(display.SchemaPath, display.SchemaHandler())
+
 		fs := http.FileServer(http.Dir("./ui/dist/display/browser"))
 		http.Handle("/", fs)
 
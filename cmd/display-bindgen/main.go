@@ -0,0 +1,44 @@
+// Command display-bindgen writes the TypeScript bindings, JSON Schema and
+// OpenAPI document of the display protocol. It is run by `go generate` in
+// the display module.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/Snider/display"
+)
+
+func main() {
+	tsPath := flag.String("ts", "", "write TypeScript bindings to this file")
+	schemaPath := flag.String("schema", "", "write the JSON Schema to this file")
+	openAPIPath := flag.String("openapi", "", "write the OpenAPI document to this file")
+	flag.Parse()
+
+	if *tsPath != "" {
+		write(*tsPath, display.TypeScriptBindings)
+	}
+	if *schemaPath != "" {
+		write(*schemaPath, display.JSONSchema)
+	}
+	if *openAPIPath != "" {
+		write(*openAPIPath, display.OpenAPI)
+	}
+}
+
+// write generates a file, creating its directory if needed.
+func write(path string, generate func() ([]byte, error)) {
+	data, err := generate()
+	if err != nil {
+		log.Fatalf("display-bindgen: %s: %v", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Fatalf("display-bindgen: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Fatalf("display-bindgen: %v", err)
+	}
+}
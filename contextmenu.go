@@ -0,0 +1,234 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ErrUnknownContextMenu is returned when a context menu is opened that has
+// not been registered.
+var ErrUnknownContextMenu = errors.New("display: unknown context menu")
+
+// ContextMenuContext is the context a frontend sends when it opens a context
+// menu, such as what is selected under the cursor.
+type ContextMenuContext map[string]any
+
+// Bool reports whether key is set to true.
+func (c ContextMenuContext) Bool(key string) bool {
+	v, _ := c[key].(bool)
+	return v
+}
+
+// String returns key as a string, or "" if it is not one.
+func (c ContextMenuContext) String(key string) string {
+	v, _ := c[key].(string)
+	return v
+}
+
+// ContextMenuItem is an item of a context menu defined in Go. Visible,
+// Enabled and Checked are evaluated each time the menu is opened, against
+// the context sent by the frontend.
+//
+// example:
+//
+//	display.ContextMenuItem{
+//		ID:      "copy-address",
+//		Label:   "Copy Address",
+//		Action:  "wallet.copyAddress",
+//		Visible: func(c display.ContextMenuContext) bool { return c.String("address") != "" },
+//	}
+type ContextMenuItem struct {
+	// ID identifies the item in `ContextMenuClick` events.
+	ID    string
+	Label string
+	// Separator makes the item a separator; its other fields are ignored.
+	Separator bool
+	// Action, if set, is invoked through the action bus on behalf of the
+	// window that opened the menu, so its capabilities apply.
+	Action string
+	// Payload is the action's payload. If it is nil, the menu's context is
+	// sent instead.
+	Payload map[string]any
+	// Visible hides the item when it returns false.
+	Visible func(ContextMenuContext) bool
+	// Enabled greys the item out when it returns false.
+	Enabled func(ContextMenuContext) bool
+	// Checked, if set, makes the item a checkbox.
+	Checked func(ContextMenuContext) bool
+	// Items makes the item a submenu.
+	Items []ContextMenuItem
+}
+
+// ContextMenuRequest is the payload of the "contextmenu.open" action.
+type ContextMenuRequest struct {
+	ID string `json:"id"`
+	// X and Y are the cursor position in the window, in CSS pixels.
+	X       int                `json:"x"`
+	Y       int                `json:"y"`
+	Context ContextMenuContext `json:"context,omitempty"`
+}
+
+// ContextMenuClick is the data of an `EventContextMenuClick`.
+type ContextMenuClick struct {
+	Menu    string             `json:"menu"`
+	Item    string             `json:"item"`
+	Context ContextMenuContext `json:"context,omitempty"`
+}
+
+// RegisterContextMenu defines a context menu that frontends can open by ID
+// with the "contextmenu.open" action. Registering an ID again replaces the
+// earlier menu.
+//
+// example:
+//
+//	displayService.RegisterContextMenu("wallet-row",
+//		display.ContextMenuItem{ID: "copy", Label: "Copy Address", Action: "wallet.copyAddress"},
+//		display.ContextMenuItem{Separator: true},
+//		display.ContextMenuItem{ID: "remove", Label: "Remove", Action: "wallet.remove",
+//			Enabled: func(c display.ContextMenuContext) bool { return !c.Bool("default") }},
+//	)
+func (s *Service) RegisterContextMenu(id string, items ...ContextMenuItem) {
+	s.contextMenusMu.Lock()
+	defer s.contextMenusMu.Unlock()
+	s.contextMenus[id] = items
+}
+
+// UnregisterContextMenu removes a context menu.
+func (s *Service) UnregisterContextMenu(id string) {
+	s.contextMenusMu.Lock()
+	defer s.contextMenusMu.Unlock()
+	delete(s.contextMenus, id)
+}
+
+// WithDefaultContextMenuDisabled suppresses the webview's built-in context
+// menu, with its reload and inspect items, in the window.
+func WithDefaultContextMenuDisabled(disabled bool) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.DefaultContextMenuDisabled = disabled
+	})
+}
+
+// OpenContextMenu shows a registered context menu in a window at a cursor
+// position. Item visibility and state are evaluated against menuContext.
+//
+// example:
+//
+//	err := displayService.OpenContextMenu("main", "wallet-row", 120, 48,
+//		display.ContextMenuContext{"address": "0xabc", "default": true})
+func (s *Service) OpenContextMenu(window, id string, x, y int, menuContext ContextMenuContext) error {
+	s.contextMenusMu.Lock()
+	items, ok := s.contextMenus[id]
+	s.contextMenusMu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownContextMenu, id)
+	}
+	if s.app == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownWindow, window)
+	}
+	w, ok := s.app.Window.GetByName(window)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownWindow, window)
+	}
+
+	// Each window has one native menu, rebuilt every time it is opened. It
+	// is rebuilt and shown by one call at a time.
+	name := "display:" + window
+	s.windowMenusMu.Lock()
+	defer s.windowMenusMu.Unlock()
+	menu, ok := s.windowMenus[window]
+	if !ok {
+		menu = application.NewContextMenu(name)
+		s.windowMenus[window] = menu
+	}
+	menu.Clear()
+	s.addContextMenuItems(menu.Menu, items, menuContext, func(item ContextMenuItem) {
+		s.clickContextMenu(window, id, item, menuContext)
+	})
+	menu.Update()
+	w.OpenContextMenu(&application.ContextMenuData{Id: name, X: x, Y: y})
+	return nil
+}
+
+// addContextMenuItems adds the items visible in menuContext to menu. Runs of
+// separators left by hidden items are collapsed.
+func (s *Service) addContextMenuItems(menu *application.Menu, items []ContextMenuItem, menuContext ContextMenuContext, onClick func(ContextMenuItem)) {
+	pendingSeparator := false
+	added := 0
+	for _, item := range items {
+		if item.Visible != nil && !item.Visible(menuContext) {
+			continue
+		}
+		if item.Separator {
+			pendingSeparator = added > 0
+			continue
+		}
+		if pendingSeparator {
+			menu.AddSeparator()
+			pendingSeparator = false
+		}
+		added++
+		if len(item.Items) > 0 {
+			s.addContextMenuItems(menu.AddSubmenu(item.Label), item.Items, menuContext, onClick)
+			continue
+		}
+		var mi *application.MenuItem
+		if item.Checked != nil {
+			mi = menu.AddCheckbox(item.Label, item.Checked(menuContext))
+		} else {
+			mi = menu.Add(item.Label)
+		}
+		if item.Enabled != nil {
+			mi.SetEnabled(item.Enabled(menuContext))
+		}
+		mi.OnClick(func(*application.Context) { onClick(item) })
+	}
+}
+
+// clickContextMenu tells the window that opened a menu which item was
+// clicked, and invokes the item's action on the window's behalf.
+func (s *Service) clickContextMenu(window, id string, item ContextMenuItem, menuContext ContextMenuContext) {
+	if s.app != nil {
+		if w, ok := s.app.Window.GetByName(window); ok {
+			w.DispatchWailsEvent(&application.CustomEvent{
+				Name: EventContextMenuClick,
+				Data: ContextMenuClick{Menu: id, Item: item.ID, Context: menuContext},
+			})
+		}
+	}
+	if item.Action == "" {
+		return
+	}
+	payload := maps.Clone(item.Payload)
+	if payload == nil {
+		payload = maps.Clone(menuContext)
+	}
+	if _, err := s.Dispatch(context.Background(), window, item.Action, payload); err != nil && s.app != nil {
+		s.app.Logger.Warn("Context menu action failed", "window", window, "menu", id, "action", item.Action, "error", err)
+	}
+}
+
+// forgetContextMenu destroys the native context menu of a closed window.
+func (s *Service) forgetContextMenu(window string) {
+	s.windowMenusMu.Lock()
+	menu, ok := s.windowMenus[window]
+	delete(s.windowMenus, window)
+	s.windowMenusMu.Unlock()
+	if ok {
+		menu.Menu.Destroy()
+		menu.Destroy()
+	}
+}
+
+// contextMenuAction opens a context menu requested by a frontend in the
+// calling window.
+func (s *Service) contextMenuAction(_ context.Context, call ActionCall) (any, error) {
+	var req ContextMenuRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	return nil, s.OpenContextMenu(call.Window, req.ID, req.X, req.Y, req.Context)
+}
@@ -0,0 +1,102 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestContextMenuItemsFollowContext(t *testing.T) {
+	s, _ := New()
+	items := []ContextMenuItem{
+		{ID: "open", Label: "Open"},
+		{Separator: true},
+		{ID: "copy", Label: "Copy Address", Visible: func(c ContextMenuContext) bool { return c.String("address") != "" }},
+		{Separator: true},
+		{ID: "remove", Label: "Remove", Enabled: func(c ContextMenuContext) bool { return !c.Bool("default") }},
+		{ID: "pin", Label: "Pinned", Checked: func(c ContextMenuContext) bool { return c.Bool("pinned") }},
+		{Label: "Move To", Items: []ContextMenuItem{{ID: "move-a", Label: "A"}}},
+	}
+	tests := []struct {
+		name       string
+		context    ContextMenuContext
+		wantLabels []string
+		wantRemove bool
+		wantPinned bool
+	}{
+		{"Empty", nil, []string{"Open", "", "Remove", "Pinned", "Move To"}, true, false},
+		{"Selected", ContextMenuContext{"address": "0xabc", "default": true, "pinned": true},
+			[]string{"Open", "", "Copy Address", "", "Remove", "Pinned", "Move To"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			menu := application.NewMenu()
+			s.addContextMenuItems(menu, items, tt.context, func(ContextMenuItem) {})
+			var labels []string
+			for i := 0; menu.ItemAt(i) != nil; i++ {
+				item := menu.ItemAt(i)
+				labels = append(labels, item.Label())
+				switch item.Label() {
+				case "Remove":
+					if item.Enabled() != tt.wantRemove {
+						t.Errorf("Remove enabled = %v, want %v", item.Enabled(), tt.wantRemove)
+					}
+				case "Pinned":
+					if item.Checked() != tt.wantPinned {
+						t.Errorf("Pinned checked = %v, want %v", item.Checked(), tt.wantPinned)
+					}
+				case "Move To":
+					if !item.IsSubmenu() || item.GetSubmenu().ItemAt(0).Label() != "A" {
+						t.Errorf("Move To is not a submenu with A")
+					}
+				}
+			}
+			if len(labels) != len(tt.wantLabels) {
+				t.Fatalf("labels = %q, want %q", labels, tt.wantLabels)
+			}
+			for i := range labels {
+				if labels[i] != tt.wantLabels[i] {
+					t.Errorf("labels = %q, want %q", labels, tt.wantLabels)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestContextMenuClickDispatchesAction(t *testing.T) {
+	s, _ := New()
+	s.grantCapabilities("main", 1, []Capability{"wallet:sign"})
+	var got ActionCall
+	s.RegisterAction("wallet.copy", "wallet:sign", func(_ context.Context, call ActionCall) (any, error) {
+		got = call
+		return nil, nil
+	})
+	s.clickContextMenu("main", "wallet-row", ContextMenuItem{ID: "copy", Action: "wallet.copy"}, ContextMenuContext{"address": "0xabc"})
+	if got.Window != "main" || got.Payload["address"] != "0xabc" {
+		t.Errorf("action call = %+v, want the context as payload", got)
+	}
+	s.clickContextMenu("main", "wallet-row", ContextMenuItem{ID: "copy", Action: "wallet.copy", Payload: map[string]any{"format": "short"}}, nil)
+	if got.Payload["format"] != "short" {
+		t.Errorf("action call = %+v, want the item's payload", got)
+	}
+
+	got = ActionCall{}
+	s.clickContextMenu("viewer", "wallet-row", ContextMenuItem{ID: "copy", Action: "wallet.copy"}, nil)
+	if got.Window != "" {
+		t.Errorf("action ran for a window without the capability: %+v", got)
+	}
+}
+
+func TestOpenUnknownContextMenu(t *testing.T) {
+	s, _ := New()
+	if err := s.OpenContextMenu("main", "missing", 0, 0, nil); !errors.Is(err, ErrUnknownContextMenu) {
+		t.Errorf("OpenContextMenu() error = %v, want ErrUnknownContextMenu", err)
+	}
+	s.RegisterContextMenu("wallet-row", ContextMenuItem{ID: "copy", Label: "Copy"})
+	if err := s.OpenContextMenu("main", "wallet-row", 0, 0, nil); !errors.Is(err, ErrUnknownWindow) {
+		t.Errorf("OpenContextMenu() before startup error = %v, want ErrUnknownWindow", err)
+	}
+}
@@ -0,0 +1,356 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNoRoute is returned when a link does not match any registered route.
+	ErrNoRoute = errors.New("display: no route for link")
+	// ErrInvalidLink is returned when a link cannot be parsed or uses a
+	// different scheme to the router.
+	ErrInvalidLink = errors.New("display: invalid link")
+)
+
+// DeepLink is a custom URL scheme link that has been matched to a route.
+type DeepLink struct {
+	// URL is the link as it was received.
+	URL string `json:"url"`
+	// Route is the pattern that matched the link.
+	Route string `json:"route"`
+	// Params holds the values of the route's {placeholders}. A trailing "*"
+	// segment is stored under the "*" key.
+	Params map[string]string `json:"params,omitempty"`
+	// Query holds the link's query string values.
+	Query map[string]string `json:"query,omitempty"`
+}
+
+// DeepLinkHandler handles a link matched by the URLRouter.
+type DeepLinkHandler func(link DeepLink) error
+
+type urlRoute struct {
+	pattern  string
+	segments []string
+	handler  DeepLinkHandler
+}
+
+// URLRouter maps links such as core://workspace/foo to handlers. Patterns
+// are written without the scheme, with the host as the first segment, e.g.
+// "workspace/{id}" or "docs/*".
+type URLRouter struct {
+	mu     sync.RWMutex
+	scheme string
+	routes []*urlRoute
+}
+
+// newURLRouter creates a router for links using scheme. An empty scheme
+// accepts any scheme.
+func newURLRouter(scheme string) *URLRouter {
+	return &URLRouter{scheme: strings.ToLower(scheme)}
+}
+
+// Scheme returns the URL scheme handled by the router.
+func (r *URLRouter) Scheme() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.scheme
+}
+
+// Handle registers a handler for links matching pattern. Routes are tried in
+// the order they were registered.
+//
+// example:
+//
+//	displayService.URLRouter().Handle("workspace/{id}", func(link display.DeepLink) error {
+//		return openWorkspace(link.Params["id"])
+//	})
+func (r *URLRouter) Handle(pattern string, handler DeepLinkHandler) {
+	pattern = strings.Trim(pattern, "/")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, &urlRoute{
+		pattern:  pattern,
+		segments: strings.Split(pattern, "/"),
+		handler:  handler,
+	})
+}
+
+// Match finds the route for a link without running its handler.
+func (r *URLRouter) Match(raw string) (DeepLink, error) {
+	_, link, err := r.match(raw)
+	return link, err
+}
+
+// Dispatch runs the handler of the first route matching a link.
+func (r *URLRouter) Dispatch(raw string) error {
+	route, link, err := r.match(raw)
+	if err != nil {
+		return err
+	}
+	return route.handler(link)
+}
+
+// match parses raw and returns the first matching route.
+func (r *URLRouter) match(raw string) (*urlRoute, DeepLink, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return nil, DeepLink{}, fmt.Errorf("%w: %q", ErrInvalidLink, raw)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.scheme != "" && !strings.EqualFold(u.Scheme, r.scheme) {
+		return nil, DeepLink{}, fmt.Errorf("%w: %q does not use the %s scheme", ErrInvalidLink, raw, r.scheme)
+	}
+
+	// core://workspace/foo parses with "workspace" as the host, while
+	// core:workspace/foo parses it as opaque data; treat both the same.
+	path := strings.Trim(u.Host+"/"+strings.Trim(u.Path, "/"), "/")
+	if u.Opaque != "" {
+		path = strings.Trim(u.Opaque, "/")
+	}
+	segments := strings.Split(path, "/")
+
+	for _, route := range r.routes {
+		params, ok := matchSegments(route.segments, segments)
+		if !ok {
+			continue
+		}
+		link := DeepLink{
+			URL:    raw,
+			Route:  route.pattern,
+			Params: params,
+		}
+		if query := u.Query(); len(query) > 0 {
+			link.Query = make(map[string]string, len(query))
+			for key := range query {
+				link.Query[key] = query.Get(key)
+			}
+		}
+		return route, link, nil
+	}
+	return nil, DeepLink{}, fmt.Errorf("%w: %s", ErrNoRoute, raw)
+}
+
+// matchSegments matches path segments against a route pattern, returning the
+// captured parameters.
+func matchSegments(pattern, path []string) (map[string]string, bool) {
+	params := map[string]string{}
+	for i, segment := range pattern {
+		if segment == "*" && i == len(pattern)-1 {
+			params["*"] = strings.Join(path[i:], "/")
+			return params, true
+		}
+		if i >= len(path) {
+			return nil, false
+		}
+		value, err := url.PathUnescape(path[i])
+		if err != nil {
+			return nil, false
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if value == "" {
+				return nil, false
+			}
+			params[segment[1:len(segment)-1]] = value
+			continue
+		}
+		if segment != value {
+			return nil, false
+		}
+	}
+	if len(path) != len(pattern) {
+		return nil, false
+	}
+	return params, true
+}
+
+// expandLinkTemplate replaces {placeholders} in template with the link's
+// parameters. Values are path-escaped so they cannot inject extra segments.
+func expandLinkTemplate(template string, params map[string]string) string {
+	if template == "" || len(params) == 0 {
+		return template
+	}
+	replacements := make([]string, 0, len(params)*2)
+	for key, value := range params {
+		replacements = append(replacements, "{"+key+"}", url.PathEscape(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// linksFromArgs returns the command line arguments that are links using
+// scheme.
+func linksFromArgs(args []string, scheme string) []string {
+	if scheme == "" {
+		return nil
+	}
+	prefix := strings.ToLower(scheme) + ":"
+	var links []string
+	for _, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), prefix) {
+			links = append(links, arg)
+		}
+	}
+	return links
+}
+
+// URLSchemeDesktopEntry returns the contents of a Linux .desktop file that
+// registers exec as the x-scheme-handler for scheme. The link is passed to
+// exec as its last argument.
+//
+// example:
+//
+//	entry := display.URLSchemeDesktopEntry("core", "Core", "/usr/bin/core")
+//	err := os.WriteFile("core-url-handler.desktop", []byte(entry), 0o644)
+func URLSchemeDesktopEntry(scheme, name, exec string) string {
+	return desktopEntry{
+		Name:      name,
+		Exec:      desktopExecQuote(exec) + " %u",
+		MimeTypes: []string{"x-scheme-handler/" + strings.ToLower(scheme)},
+		NoDisplay: true,
+	}.String()
+}
+
+// desktopEntry is a minimal freedesktop.org desktop entry.
+type desktopEntry struct {
+	Name      string
+	Comment   string
+	Exec      string
+	Icon      string
+	MimeTypes []string
+	NoDisplay bool
+	// Extra holds additional key/value pairs, written in order.
+	Extra [][2]string
+}
+
+// String renders the entry in the .desktop file format.
+func (e desktopEntry) String() string {
+	var b strings.Builder
+	b.WriteString("[Desktop Entry]\n")
+	b.WriteString("Type=Application\n")
+	fmt.Fprintf(&b, "Name=%s\n", desktopValueEscape(e.Name))
+	if e.Comment != "" {
+		fmt.Fprintf(&b, "Comment=%s\n", desktopValueEscape(e.Comment))
+	}
+	fmt.Fprintf(&b, "Exec=%s\n", e.Exec)
+	if e.Icon != "" {
+		fmt.Fprintf(&b, "Icon=%s\n", desktopValueEscape(e.Icon))
+	}
+	b.WriteString("Terminal=false\n")
+	if len(e.MimeTypes) > 0 {
+		fmt.Fprintf(&b, "MimeType=%s;\n", strings.Join(e.MimeTypes, ";"))
+	}
+	if e.NoDisplay {
+		b.WriteString("NoDisplay=true\n")
+	}
+	for _, kv := range e.Extra {
+		fmt.Fprintf(&b, "%s=%s\n", kv[0], desktopValueEscape(kv[1]))
+	}
+	return b.String()
+}
+
+// desktopValueEscape escapes a string value for a .desktop file.
+func desktopValueEscape(value string) string {
+	return strings.NewReplacer("\\", `\\`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(value)
+}
+
+// desktopExecQuote quotes an argument for the Exec key of a .desktop file.
+func desktopExecQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\><~|&;$*?#()`%") {
+		return arg
+	}
+	escaped := strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", "$", `\\$`, "%", "%%").Replace(arg)
+	return `"` + escaped + `"`
+}
+
+// URLRouter returns the service's router for custom URL scheme links.
+//
+// example:
+//
+//	router := displayService.URLRouter()
+//	router.Handle("workspace/{id}", func(link display.DeepLink) error {
+//		return openWorkspace(link.Params["id"])
+//	})
+func (s *Service) URLRouter() *URLRouter {
+	return s.urlRouter
+}
+
+// RouteToWindow routes links matching pattern to a window. The window is
+// built from opts, with {placeholders} in its name, title and the URL
+// template replaced by the link's parameters. If a window with that name is
+// already open it is navigated and focused instead of opened again.
+//
+// example:
+//
+//	displayService.RouteToWindow("window/{name}", "/#/{name}",
+//		display.WithName("{name}"),
+//		display.WithWidth(800),
+//	)
+func (s *Service) RouteToWindow(pattern, urlTemplate string, opts ...WindowOption) {
+	s.urlRouter.Handle(pattern, func(link DeepLink) error {
+		expanded := append(append([]WindowOption{}, opts...), WindowOptionFunc(func(c *WindowConfig) {
+			c.Name = expandLinkTemplate(c.Name, link.Params)
+			c.Title = expandLinkTemplate(c.Title, link.Params)
+			c.URL = expandLinkTemplate(urlTemplate, link.Params)
+		}))
+		wailsOpts := buildWailsWindowOptions(expanded...)
+		if window, ok := s.app.Window.GetByName(wailsOpts.Name); ok {
+			if err := s.Navigate(wailsOpts.Name, wailsOpts.URL); err != nil {
+				return err
+			}
+			window.Show()
+			window.Focus()
+			return nil
+		}
+		return s.OpenWindow(expanded...)
+	})
+}
+
+// HandleDeepLink dispatches a link to its route and announces it on the action
+// bus as an `EventDeepLink` event.
+//
+// example:
+//
+//	err := displayService.HandleDeepLink("core://workspace/foo")
+func (s *Service) HandleDeepLink(raw string) error {
+	route, link, err := s.urlRouter.match(raw)
+	if err != nil {
+		return err
+	}
+	if s.app != nil {
+		s.app.Event.Emit(EventDeepLink, link)
+	}
+	return route.handler(link)
+}
+
+// handleDeepLinkArgs dispatches any links found in args, logging failures,
+// and reports whether there were any.
+func (s *Service) handleDeepLinkArgs(args []string) bool {
+	links := linksFromArgs(args, s.urlRouter.Scheme())
+	for _, link := range links {
+		if err := s.HandleDeepLink(link); err != nil {
+			s.app.Logger.Warn("Failed to handle link", "url", link, "error", err)
+		}
+	}
+	return len(links) > 0
+}
+
+// RegisterURLScheme registers the running executable as the handler for
+// `Options.URLScheme` links. On Linux this writes an x-scheme-handler .desktop
+// file to the user's applications directory and makes it the default.
+func (s *Service) RegisterURLScheme() error {
+	scheme := s.urlRouter.Scheme()
+	if scheme == "" {
+		return errors.New("display: Options.URLScheme is not set")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return registerURLScheme(scheme, s.appName(), exe)
+}
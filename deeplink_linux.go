@@ -0,0 +1,45 @@
+//go:build linux
+
+package display
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// registerURLScheme writes an x-scheme-handler .desktop file for scheme to
+// the user's applications directory and makes it the default handler with
+// xdg-mime when it is installed.
+func registerURLScheme(scheme, name, executable string) error {
+	dir := filepath.Join(xdgDataHome(), "applications")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("display: registering URL scheme: %w", err)
+	}
+	file := strings.ToLower(scheme) + "-url-handler.desktop"
+	entry := URLSchemeDesktopEntry(scheme, name, executable)
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(entry), 0o644); err != nil {
+		return fmt.Errorf("display: registering URL scheme: %w", err)
+	}
+
+	xdgMime, err := exec.LookPath("xdg-mime")
+	if err != nil {
+		return nil
+	}
+	out, err := exec.Command(xdgMime, "default", file, "x-scheme-handler/"+strings.ToLower(scheme)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("display: xdg-mime: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// xdgDataHome returns $XDG_DATA_HOME, defaulting to ~/.local/share.
+func xdgDataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share")
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package display
+
+import "errors"
+
+// registerURLScheme is not implemented on this platform; URL schemes are
+// registered by the application bundle or installer instead.
+func registerURLScheme(scheme, name, executable string) error {
+	return errors.New("display: URL scheme registration is not supported on this platform")
+}
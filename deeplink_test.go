@@ -0,0 +1,144 @@
+package display
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestURLRouter_Match(t *testing.T) {
+	r := newURLRouter("core")
+	noop := func(DeepLink) error { return nil }
+	r.Handle("workspace/{id}", noop)
+	r.Handle("window/{name}", noop)
+	r.Handle("docs/*", noop)
+	r.Handle("about", noop)
+
+	tests := []struct {
+		name    string
+		url     string
+		want    DeepLink
+		wantErr error
+	}{
+		{
+			name: "Placeholder",
+			url:  "core://workspace/foo",
+			want: DeepLink{URL: "core://workspace/foo", Route: "workspace/{id}", Params: map[string]string{"id": "foo"}},
+		},
+		{
+			name: "Escaped placeholder and query",
+			url:  "core://workspace/my%20space?tab=files",
+			want: DeepLink{
+				URL:    "core://workspace/my%20space?tab=files",
+				Route:  "workspace/{id}",
+				Params: map[string]string{"id": "my space"},
+				Query:  map[string]string{"tab": "files"},
+			},
+		},
+		{
+			name: "Opaque form",
+			url:  "core:window/settings",
+			want: DeepLink{URL: "core:window/settings", Route: "window/{name}", Params: map[string]string{"name": "settings"}},
+		},
+		{
+			name: "Wildcard",
+			url:  "CORE://docs/guide/install",
+			want: DeepLink{URL: "CORE://docs/guide/install", Route: "docs/*", Params: map[string]string{"*": "guide/install"}},
+		},
+		{
+			name: "Literal",
+			url:  "core://about/",
+			want: DeepLink{URL: "core://about/", Route: "about", Params: map[string]string{}},
+		},
+		{name: "Too many segments", url: "core://workspace/foo/bar", wantErr: ErrNoRoute},
+		{name: "Missing placeholder", url: "core://workspace", wantErr: ErrNoRoute},
+		{name: "Unknown route", url: "core://nowhere", wantErr: ErrNoRoute},
+		{name: "Wrong scheme", url: "https://workspace/foo", wantErr: ErrInvalidLink},
+		{name: "Not a URL", url: "workspace/foo", wantErr: ErrInvalidLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Match(tt.url)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Match(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Match(%q) error = %v", tt.url, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match(%q) = %+v, want %+v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLRouter_Dispatch(t *testing.T) {
+	r := newURLRouter("core")
+	var got string
+	r.Handle("workspace/{id}", func(link DeepLink) error {
+		got = link.Params["id"]
+		return nil
+	})
+	if err := r.Dispatch("core://workspace/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if got != "foo" {
+		t.Errorf("handler received id %q, want %q", got, "foo")
+	}
+}
+
+func TestExpandLinkTemplate(t *testing.T) {
+	params := map[string]string{"id": "a/b c", "name": "settings"}
+	if got, want := expandLinkTemplate("/#/workspace/{id}?from={name}", params), "/#/workspace/a%2Fb%20c?from=settings"; got != want {
+		t.Errorf("expandLinkTemplate() = %q, want %q", got, want)
+	}
+	if got := expandLinkTemplate("/static", params); got != "/static" {
+		t.Errorf("expandLinkTemplate() without placeholders = %q", got)
+	}
+}
+
+func TestLinksFromArgs(t *testing.T) {
+	args := []string{"--verbose", "core://workspace/foo", "https://example.com", "Core:window/settings"}
+	want := []string{"core://workspace/foo", "Core:window/settings"}
+	if got := linksFromArgs(args, "core"); !reflect.DeepEqual(got, want) {
+		t.Errorf("linksFromArgs() = %v, want %v", got, want)
+	}
+	if got := linksFromArgs(args, ""); got != nil {
+		t.Errorf("linksFromArgs() without a scheme = %v, want nil", got)
+	}
+}
+
+func TestURLSchemeDesktopEntry(t *testing.T) {
+	entry := URLSchemeDesktopEntry("Core", "Core Hub", "/opt/Core Hub/core")
+	for _, line := range []string{
+		"[Desktop Entry]",
+		"Type=Application",
+		"Name=Core Hub",
+		`Exec="/opt/Core Hub/core" %u`,
+		"MimeType=x-scheme-handler/core;",
+		"NoDisplay=true",
+	} {
+		if !strings.Contains(entry, line+"\n") {
+			t.Errorf("desktop entry is missing %q:\n%s", line, entry)
+		}
+	}
+}
+
+func TestDesktopExecQuote(t *testing.T) {
+	tests := map[string]string{
+		"/usr/bin/core":       "/usr/bin/core",
+		"/opt/my app/core":    `"/opt/my app/core"`,
+		`/opt/$HOME/"x"`:      `"/opt/\\$HOME/\\"x\\""`,
+		"/opt/100%/core":      `"/opt/100%%/core"`,
+		`/opt/back\slash/bin`: `"/opt/back\\\\slash/bin"`,
+	}
+	for in, want := range tests {
+		if got := desktopExecQuote(in); got != want {
+			t.Errorf("desktopExecQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package display
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ErrUnknownAction is returned when a window invokes an action that has not
+// been registered.
+var ErrUnknownAction = errors.New("display: unknown action")
+
+// Built-in actions that frontends can invoke through `Bridge.Invoke`.
+const (
+	ActionNameWindowOpen     = "window.open"
+	ActionNameDialogOpenFile = "dialog.openFile"
+	ActionNameDialogSaveFile = "dialog.saveFile"
+	ActionNameNotify         = "notify"
+	ActionNameTrayUpdate     = "tray.update"
+	ActionNameClipboardRead  = "clipboard.read"
+	ActionNameClipboardWrite = "clipboard.write"
+	ActionNameClipboardClear = "clipboard.clear"
+	ActionNameContextMenu    = "contextmenu.open"
+	ActionNameRecentList     = "recent.list"
+	ActionNameRecentAdd      = "recent.add"
+	ActionNameRecentPin      = "recent.pin"
+	ActionNameRecentRemove   = "recent.remove"
+	ActionNameRecentClear    = "recent.clear"
+)
+
+// ActionCall is a single invocation of an action by a window.
+type ActionCall struct {
+	// ID is the request ID, if the caller expects a reply.
+	ID string
+	// Action is the registered action name.
+	Action string
+	// Window is the name of the calling window.
+	Window string
+	// WindowID is the Wails ID of the calling window, or zero for callers
+	// that are not windows. Capabilities are checked against it.
+	WindowID uint
+	// Payload is the decoded JSON payload sent with the call.
+	Payload map[string]any
+}
+
+// caller identifies who made the call, for rate limits and pending requests:
+// the window ID for windows, otherwise the caller's name.
+func (c ActionCall) caller() string {
+	if c.WindowID != 0 {
+		return fmt.Sprintf("window#%d", c.WindowID)
+	}
+	return c.Window
+}
+
+// Decode unmarshals the call's payload into v.
+func (c ActionCall) Decode(v any) error {
+	data, err := json.Marshal(c.Payload)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("display: invalid payload for %s: %w", c.Action, err)
+	}
+	return nil
+}
+
+// ActionHandler handles an action invoked by a window. The value it returns
+// is the action's result.
+type ActionHandler func(ctx context.Context, call ActionCall) (any, error)
+
+// registeredAction is an action handler, the capability it requires and how
+// long a call may take.
+type registeredAction struct {
+	capability Capability
+	handler    ActionHandler
+	timeout    time.Duration
+}
+
+// RegisterAction makes an action available to frontends. Windows must be
+// granted capability to invoke it; an empty capability lets every window
+// invoke it. Registering a name again replaces the earlier handler. Calls
+// time out after 30 seconds unless changed with `SetActionTimeout`.
+//
+// example:
+//
+//	displayService.RegisterAction("wallet.sign", "wallet:sign",
+//		func(ctx context.Context, call display.ActionCall) (any, error) {
+//			return wallet.Sign(ctx, call.Payload["tx"])
+//		})
+func (s *Service) RegisterAction(name string, capability Capability, handler ActionHandler) {
+	s.actionsMu.Lock()
+	defer s.actionsMu.Unlock()
+	s.actions[name] = registeredAction{capability: capability, handler: handler, timeout: defaultActionTimeout}
+}
+
+// SetActionTimeout changes how long calls to an action may take before they
+// fail with `ErrActionTimeout`. Zero or less disables the timeout.
+//
+// example:
+//
+//	err := displayService.SetActionTimeout("wallet.sign", 2*time.Minute)
+func (s *Service) SetActionTimeout(name string, timeout time.Duration) error {
+	s.actionsMu.Lock()
+	defer s.actionsMu.Unlock()
+	registered, ok := s.actions[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownAction, name)
+	}
+	registered.timeout = timeout
+	s.actions[name] = registered
+	return nil
+}
+
+// actionTimeout returns the timeout of a registered action.
+func (s *Service) actionTimeout(name string) time.Duration {
+	s.actionsMu.Lock()
+	defer s.actionsMu.Unlock()
+	if registered, ok := s.actions[name]; ok {
+		return registered.timeout
+	}
+	return defaultActionTimeout
+}
+
+// Dispatch invokes an action on behalf of a window. The window must hold the
+// capability the action requires, otherwise a `*PermissionError` is returned
+// and the denial is logged and emitted as `EventActionDenied`.
+//
+// example:
+//
+//	result, err := displayService.Dispatch(ctx, "main", display.ActionNameNotify,
+//		map[string]any{"Title": "Saved"})
+func (s *Service) Dispatch(ctx context.Context, window, action string, payload map[string]any) (any, error) {
+	return s.dispatch(ctx, s.callFrom(window, ActionCall{Action: action, Payload: payload}))
+}
+
+// callFrom fills in the caller of call from the name of an open window. A
+// name that does not belong to a window the service granted capabilities to
+// is treated as a caller without any.
+func (s *Service) callFrom(name string, call ActionCall) ActionCall {
+	call.Window = name
+	call.WindowID, _ = s.windowID(name)
+	return call
+}
+
+// dispatch records a call, runs it through the middleware in
+// `Options.Middleware`, then checks the caller's capabilities and the payload
+// before invoking the action's handler.
+func (s *Service) dispatch(ctx context.Context, call ActionCall) (any, error) {
+	if call.Payload == nil {
+		call.Payload = map[string]any{}
+	}
+	s.record(RecordedEvent{Kind: RecordAction, Window: call.Window, ID: call.ID, Action: call.Action, Payload: redactPayload(call.Action, call.Payload)})
+	s.actionsMu.Lock()
+	registered, ok := s.actions[call.Action]
+	s.actionsMu.Unlock()
+	handler := func(ctx context.Context, call ActionCall) (any, error) {
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownAction, call.Action)
+		}
+		if registered.capability != "" && !s.hasCapability(call.WindowID, registered.capability) {
+			err := &PermissionError{Window: call.Window, Action: call.Action, Capability: registered.capability}
+			s.denyAction(call.WindowID, err)
+			return nil, err
+		}
+		if err := ValidateAction(call.Action, call.Payload); err != nil {
+			return nil, err
+		}
+		return registered.handler(ctx, call)
+	}
+	return chainMiddleware(handler, s.config.Middleware)(ctx, call)
+}
+
+// registerBuiltinActions registers the actions the service provides itself.
+func (s *Service) registerBuiltinActions() {
+	s.RegisterAction(ActionNameWindowOpen, CapWindowOpen, s.openWindowAction)
+	s.RegisterAction(ActionNameDialogOpenFile, CapDialogFile, s.openFileAction)
+	s.RegisterAction(ActionNameDialogSaveFile, CapDialogFile, s.saveFileAction)
+	s.RegisterAction(ActionNameNotify, CapNotify, s.notifyAction)
+	s.RegisterAction(ActionNameTrayUpdate, CapTrayUpdate, s.trayUpdateAction)
+	s.RegisterAction(ActionNameClipboardRead, CapClipboardRead, s.clipboardReadAction)
+	s.RegisterAction(ActionNameClipboardWrite, CapClipboardWrite, s.clipboardWriteAction)
+	s.RegisterAction(ActionNameClipboardClear, CapClipboardWrite, s.clipboardClearAction)
+	// Menu items are checked against the window's capabilities when clicked.
+	s.RegisterAction(ActionNameContextMenu, "", s.contextMenuAction)
+	s.RegisterAction(ActionNameRecentList, CapRecent, s.recentListAction)
+	s.RegisterAction(ActionNameRecentAdd, CapRecent, s.recentAddAction)
+	s.RegisterAction(ActionNameRecentPin, CapRecent, s.recentPinAction)
+	s.RegisterAction(ActionNameRecentRemove, CapRecent, s.recentRemoveAction)
+	s.RegisterAction(ActionNameRecentClear, CapRecent, s.recentClearAction)
+	// File dialogs wait for the user.
+	_ = s.SetActionTimeout(ActionNameDialogOpenFile, 0)
+	_ = s.SetActionTimeout(ActionNameDialogSaveFile, 0)
+}
+
+// windowCall returns an empty call made by window.
+func windowCall(window application.Window) ActionCall {
+	return ActionCall{Window: window.Name(), WindowID: window.ID()}
+}
+
+// openWindowAction opens a window requested by a frontend. The new window's
+// capabilities are narrowed to those of the calling window.
+func (s *Service) openWindowAction(_ context.Context, call ActionCall) (any, error) {
+	var requested []Capability
+	if raw, ok := call.Payload["capabilities"]; ok {
+		requested = []Capability{}
+		data, _ := json.Marshal(raw)
+		if err := json.Unmarshal(data, &requested); err != nil {
+			return nil, fmt.Errorf("display: invalid capabilities: %w", err)
+		}
+	}
+	msg := make(map[string]any, len(call.Payload)+1)
+	for k, v := range call.Payload {
+		msg[k] = v
+	}
+	msg["capabilities"] = narrowCapabilities(s.capabilities(call.WindowID), requested)
+	return nil, s.handleOpenWindowAction(msg)
+}
+
+// openFileAction shows a file open dialog over the calling window and returns
+// the chosen paths.
+func (s *Service) openFileAction(_ context.Context, call ActionCall) (any, error) {
+	var req FileDialogRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	dialog := s.app.Dialog.OpenFile().SetTitle(req.Title)
+	for _, f := range req.Filters {
+		dialog.AddFilter(f.Name, f.Pattern)
+	}
+	if window, ok := s.app.Window.GetByName(call.Window); ok {
+		dialog.AttachToWindow(window)
+	}
+	if req.Multiple {
+		return dialog.PromptForMultipleSelection()
+	}
+	path, err := dialog.PromptForSingleSelection()
+	if err != nil || path == "" {
+		return []string{}, err
+	}
+	return []string{path}, nil
+}
+
+// saveFileAction shows a file save dialog over the calling window and returns
+// the chosen path.
+func (s *Service) saveFileAction(_ context.Context, call ActionCall) (any, error) {
+	var req FileDialogRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	dialog := s.app.Dialog.SaveFile().SetFilename(req.Filename)
+	for _, f := range req.Filters {
+		dialog.AddFilter(f.Name, f.Pattern)
+	}
+	if window, ok := s.app.Window.GetByName(call.Window); ok {
+		dialog.AttachToWindow(window)
+	}
+	return dialog.PromptForSingleSelection()
+}
+
+// notifyAction shows a desktop notification and returns its ID.
+func (s *Service) notifyAction(ctx context.Context, call ActionCall) (any, error) {
+	var n Notification
+	if err := call.Decode(&n); err != nil {
+		return nil, err
+	}
+	return s.Notify(ctx, n)
+}
+
+// trayUpdateAction changes the system tray tooltip and label.
+func (s *Service) trayUpdateAction(_ context.Context, call ActionCall) (any, error) {
+	if s.tray == nil {
+		return nil, errors.New("display: no system tray")
+	}
+	var req TrayUpdateRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	if req.Tooltip != nil {
+		s.tray.SetTooltip(*req.Tooltip)
+	}
+	if req.Label != nil {
+		s.tray.SetLabel(*req.Label)
+	}
+	return nil, nil
+}
+
+// clipboardReadAction returns the clipboard content in the requested formats.
+func (s *Service) clipboardReadAction(_ context.Context, call ActionCall) (any, error) {
+	var req ClipboardReadRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	return s.Clipboard().Read(req.Formats...)
+}
+
+// clipboardWriteAction copies to the clipboard, clearing it again after
+// "clearAfterMs" if it is set.
+func (s *Service) clipboardWriteAction(_ context.Context, call ActionCall) (any, error) {
+	var req ClipboardWriteRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	clipboard := s.Clipboard()
+	if req.ClearAfterMs > 0 {
+		clearAfter := time.Duration(req.ClearAfterMs) * time.Millisecond
+		if secure := clipboard.SecureMode(); secure > 0 {
+			clearAfter = min(clearAfter, secure)
+		}
+		return nil, clipboard.write(req.ClipboardContent, clearAfter)
+	}
+	return nil, clipboard.Write(req.ClipboardContent)
+}
+
+// clipboardClearAction empties the clipboard.
+func (s *Service) clipboardClearAction(context.Context, ActionCall) (any, error) {
+	return nil, s.Clipboard().Clear()
+}
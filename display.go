@@ -2,7 +2,14 @@ package display
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/events"
@@ -10,19 +17,208 @@ import (
 
 // Options holds configuration for the display service.
 // This struct is used to configure the display service at startup.
-type Options struct{}
+type Options struct {
+	// KeymapPath is the file user shortcut rebinds are saved to. It defaults
+	// to keymap.json in the user's config directory.
+	KeymapPath string
+
+	// SingleInstance makes later launches forward their arguments to the
+	// running instance and exit instead of starting a second copy.
+	SingleInstance bool
+
+	// InstanceID identifies the application for the single-instance lock. It
+	// defaults to the Wails application name.
+	InstanceID string
+
+	// OnSecondInstance handles launches forwarded from a second instance. If
+	// it is nil, the "main" window is focused instead.
+	OnSecondInstance func(ActionSecondInstance)
+
+	// URLScheme is the custom URL scheme, such as "core", whose links are
+	// routed by `URLRouter`. Links passed on the command line at startup or
+	// forwarded by a second instance are dispatched automatically.
+	URLScheme string
+
+	// ConfigPath is an optional display configuration file, see `Config`.
+	ConfigPath string
+
+	// LayoutsPath is the file saved window layouts are persisted to. It
+	// defaults to layouts.json in the user's config directory.
+	LayoutsPath string
+
+	// PlacementsPath is the file the positions of windows opened with
+	// `PlacementRemember` are saved to. It defaults to placements.json in the
+	// user's config directory.
+	PlacementsPath string
+
+	// Kiosk enables kiosk mode: "main" opens fullscreen and locked, and the
+	// app menu and system tray are not created. See `KioskOptions`.
+	Kiosk *KioskOptions
+
+	// Splash shows a splash window while "main" loads. "main" stays hidden
+	// until it signals that it is ready. See `SplashOptions`.
+	Splash *SplashOptions
+
+	// Middleware wraps every action invoked by a frontend. The first
+	// middleware is the outermost, so it sees each call first and its result
+	// last.
+	Middleware []ActionMiddleware
+
+	// RecordPath, if set, records every action and window event to this file
+	// as JSON lines. See `Service.StartRecording` and `Replay`.
+	RecordPath string
+
+	// ClipboardPollInterval, if set, watches the clipboard for changes made
+	// by other applications, see `Clipboard.OnChange` and
+	// `EventClipboardChanged`.
+	ClipboardPollInterval time.Duration
+
+	// Locale overrides the locale detected from the environment, see
+	// `DetectLocale`.
+	Locale string
+
+	// Catalogues holds message catalogues named after their locale, such as
+	// "de.json" or "pt_BR.po", see `Service.LoadCatalogues`.
+	Catalogues fs.FS
+
+	// RecentPath is the file the recently opened items are saved to. It
+	// defaults to recent.json in the user's config directory.
+	RecentPath string
+
+	// MaxRecent is the most recent items kept, pinned ones included. It
+	// defaults to `DefaultMaxRecent`.
+	MaxRecent int
+
+	// OnOpenRecent is called when a recent item is chosen from the "Open
+	// Recent" menu of the app menu or the system tray.
+	OnOpenRecent func(RecentItem)
+
+	// RunInBackground keeps the app running in the system tray when its last
+	// visible window is closed: the window is hidden instead, and the tray's
+	// "Open Desktop" restores it. See `Service.HideToTray`. Launched with
+	// `BackgroundArg`, the app starts in the tray with "main" hidden.
+	RunInBackground bool
+}
 
 // Service manages windowing, dialogs, and other visual elements.
 // It is the primary interface for interacting with the UI.
 type Service struct {
 	app    *application.App
 	config Options
+
+	notifierMu sync.Mutex
+	notifier   notifier
+
+	shortcuts   *ShortcutRegistry
+	menuItemsMu sync.Mutex
+	menuItems   map[string][]*application.MenuItem
+
+	instanceLock *instanceLock
+	urlRouter    *URLRouter
+
+	windows       *windowRegistry
+	windowHooksMu sync.Mutex
+	windowHooks   map[string]bool
+
+	layoutsMu sync.Mutex
+	layouts   map[string]SavedLayout
+
+	templatesMu sync.Mutex
+	templates   map[string]WindowConfig
+
+	placementsMu sync.Mutex
+	placements   map[string]windowPlacement
+
+	kiosk  *kioskState
+	splash *splashState
+
+	navigationMu sync.Mutex
+	navigation   map[string]*navigationState
+
+	grantsMu  sync.Mutex
+	grants    map[uint][]Capability
+	windowIDs map[string]uint
+	actionsMu sync.Mutex
+	actions   map[string]registeredAction
+	pendingMu sync.Mutex
+	pending   map[string]context.CancelCauseFunc
+	// handlers tracks running action handlers; overdue counts those whose
+	// call has already replied with a timeout or cancellation. Once
+	// handlersClosed is set no new handler starts, so shutdown can wait.
+	handlersMu     sync.Mutex
+	handlersClosed bool
+	handlers       sync.WaitGroup
+	overdue        atomic.Int64
+
+	recorderMu sync.Mutex
+	recorder   *recorder
+
+	tray     *application.SystemTray
+	trayMenu *application.Menu
+
+	clipboardMu sync.Mutex
+	clipboard   *Clipboard
+
+	fileDropsMu sync.Mutex
+	fileDrops   map[string]*fileDropTarget
+
+	contextMenusMu sync.Mutex
+	contextMenus   map[string][]ContextMenuItem
+	// windowMenusMu guards windowMenus and is held while a window's menu
+	// is rebuilt and shown.
+	windowMenusMu sync.Mutex
+	windowMenus   map[string]*application.ContextMenu
+
+	i18n *translator
+
+	appMenu *AppMenu
+
+	recentMu   sync.Mutex
+	recent     []RecentItem
+	trayRecent *application.Menu
+	// recentVersion counts changes to recent. recentMenuVersion is the
+	// version the application menu shows, guarded by its lock, and
+	// recentTrayVersion the version the tray shows.
+	recentVersion     uint64
+	recentMenuVersion uint64
+	recentTrayVersion uint64
+
+	background *backgroundState
+
+	// mainThread, if set, replaces the Wails main thread in tests.
+	mainThread func(fn func())
 }
 
 // newDisplayService contains the common logic for initializing a Service struct.
 // It is called by the New function.
 func newDisplayService() (*Service, error) {
-	return &Service{}, nil
+	s := &Service{
+		shortcuts:   newShortcutRegistry(),
+		menuItems:   map[string][]*application.MenuItem{},
+		urlRouter:   newURLRouter(""),
+		windows:     newWindowRegistry(),
+		windowHooks: map[string]bool{},
+		layouts:     map[string]SavedLayout{},
+		templates:   map[string]WindowConfig{},
+		placements:  map[string]windowPlacement{},
+		navigation:  map[string]*navigationState{},
+		grants:      map[uint][]Capability{},
+		windowIDs:   map[string]uint{},
+		actions:     map[string]registeredAction{},
+		pending:     map[string]context.CancelCauseFunc{},
+		fileDrops:   map[string]*fileDropTarget{},
+
+		contextMenus: map[string][]ContextMenuItem{},
+		windowMenus:  map[string]*application.ContextMenu{},
+
+		i18n:       newTranslator(DetectLocale()),
+		background: &backgroundState{},
+	}
+	s.shortcuts.onChange = s.syncShortcut
+	s.appMenu = newAppMenu(s)
+	s.renderRecent(0, nil)
+	s.registerBuiltinActions()
+	return s, nil
 }
 
 // New is the constructor for the display service.
@@ -42,9 +238,41 @@ func New() (*Service, error) {
 	return s, nil
 }
 
+// NewWithOptions creates a new Service configured with the given options.
+//
+// example:
+//
+//	displayService, err := display.NewWithOptions(display.Options{
+//		KeymapPath: "/home/user/.config/myapp/keymap.json",
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewWithOptions(options Options) (*Service, error) {
+	s, err := newDisplayService()
+	if err != nil {
+		return nil, err
+	}
+	s.config = options
+	s.urlRouter = newURLRouter(options.URLScheme)
+	if options.Locale != "" {
+		s.i18n = newTranslator(options.Locale)
+	}
+	if options.Kiosk != nil {
+		s.kiosk = newKioskState(*options.Kiosk)
+	}
+	if options.Splash != nil {
+		s.splash = newSplashState(*options.Splash)
+	}
+	return s, nil
+}
+
 // Startup is called when the app starts. It initializes the display service
 // and sets up the main application window and system tray.
 //
+// In single-instance mode, Startup returns `ErrAlreadyRunning` and quits the
+// application if another instance is running.
+//
 //	err := displayService.Startup(ctx)
 //	if err != nil {
 //		log.Fatal(err)
@@ -52,24 +280,165 @@ func New() (*Service, error) {
 func (s *Service) Startup(ctx context.Context) error {
 	s.app = application.Get()
 	s.app.Logger.Info("Display service started")
-	s.buildMenu()
-	s.systemTray()
-	return s.OpenWindow()
+	s.app.OnShutdown(s.closeNotifier)
+	s.app.OnShutdown(s.waitForActions)
+	if s.config.SingleInstance {
+		if err := s.startSingleInstance(); err != nil {
+			if errors.Is(err, ErrAlreadyRunning) {
+				s.app.Logger.Info("Forwarded launch to running instance")
+				s.app.Quit()
+				return err
+			}
+			s.app.Logger.Warn("Single-instance lock unavailable", "error", err)
+		}
+	}
+	if s.config.ConfigPath != "" {
+		config, err := LoadConfig(s.config.ConfigPath)
+		if err != nil {
+			return err
+		}
+		s.applyConfig(config)
+	}
+	if s.config.Catalogues != nil {
+		if err := s.LoadCatalogues(s.config.Catalogues); err != nil {
+			s.app.Logger.Warn("Failed to load message catalogues", "error", err)
+		}
+	}
+	if err := s.shortcuts.LoadKeymap(s.keymapPath()); err != nil {
+		s.app.Logger.Warn("Failed to load keymap", "error", err)
+	}
+	if err := s.loadLayouts(); err != nil {
+		s.app.Logger.Warn("Failed to load saved layouts", "error", err)
+	}
+	if err := s.loadPlacements(); err != nil {
+		s.app.Logger.Warn("Failed to load window placements", "error", err)
+	}
+	if err := s.loadRecent(); err != nil {
+		s.app.Logger.Warn("Failed to load recent items", "error", err)
+	}
+	if s.config.RecordPath != "" {
+		if err := s.startRecordingFile(s.config.RecordPath); err != nil {
+			s.app.Logger.Warn("Failed to start recording", "error", err)
+		}
+	}
+	s.monitorScreenChanges()
+	s.installNavigationGuard()
+	if s.config.ClipboardPollInterval > 0 {
+		go s.Clipboard().watch(ctx, s.config.ClipboardPollInterval)
+	}
+	if s.kiosk != nil {
+		s.installKioskEscape()
+		s.installShortcuts()
+	} else {
+		s.buildMenu()
+		s.installShortcuts()
+		s.systemTray()
+		s.installBackgroundMode()
+	}
+	var mainOpts []WindowOption
+	switch {
+	case s.startInBackground():
+		mainOpts = append(mainOpts, WithHidden(true))
+	case s.splash != nil:
+		s.showSplash()
+		mainOpts = append(mainOpts, WithHidden(true))
+	}
+	if err := s.OpenWindow(mainOpts...); err != nil {
+		return err
+	}
+	s.handleDeepLinkArgs(os.Args[1:])
+	return nil
+}
+
+// appName returns the configured application name, which is shown by the
+// notification server and identifies the single-instance lock.
+func (s *Service) appName() string {
+	if s.app != nil && s.app.Config().Name != "" {
+		return s.app.Config().Name
+	}
+	return "Core"
 }
 
 // handleOpenWindowAction processes a message to configure and create a new window
-// using the specified name and options.
+// using the specified name and options. If the message names a template, the
+// options are layered on top of it; a "parent" (and optional "modal") opens
+// the window as a child of that window, "screen" and "placement" choose where
+// it opens, "navigation" sets its `NavigationPolicy`, and "capabilities" its
+// grants. A window opened without a name or template is named "window-1",
+// "window-2" and so on.
 func (s *Service) handleOpenWindowAction(msg map[string]any) error {
-	opts := parseWindowOptions(msg)
-	s.app.Window.NewWithOptions(opts)
-	return nil
+	opts, err := parseWindowOptions(msg)
+	if err != nil {
+		return err
+	}
+	var windowOpts []WindowOption
+	template, _ := msg["template"].(string)
+	if template != "" {
+		if _, ok := s.LookupWindowTemplate(template); !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownTemplate, template)
+		}
+		windowOpts = append(windowOpts, s.FromTemplate(template))
+	}
+	if parent, ok := msg["parent"].(string); ok && parent != "" {
+		modal, _ := msg["modal"].(bool)
+		windowOpts = append(windowOpts, WithParent(parent), WithModal(modal))
+	}
+	if screen, ok := msg["screen"].(string); ok && screen != "" {
+		windowOpts = append(windowOpts, WithScreen(screen))
+	}
+	if placement, ok := msg["placement"].(string); ok && placement != "" {
+		windowOpts = append(windowOpts, WithPlacement(Placement(placement)))
+	}
+	if raw, ok := msg["navigation"]; ok {
+		var policy NavigationPolicy
+		data, _ := json.Marshal(raw)
+		if err := json.Unmarshal(data, &policy); err != nil {
+			return fmt.Errorf("display: invalid navigation policy: %w", err)
+		}
+		windowOpts = append(windowOpts, WithNavigationPolicy(policy))
+	}
+	if raw, ok := msg["capabilities"]; ok {
+		var caps []Capability
+		data, _ := json.Marshal(raw)
+		if err := json.Unmarshal(data, &caps); err != nil {
+			return fmt.Errorf("display: invalid capabilities: %w", err)
+		}
+		windowOpts = append(windowOpts, WithCapabilities(caps...))
+	}
+	if opts.Name == "" && template == "" {
+		opts.Name = s.windows.unusedName("window")
+	}
+	return s.OpenWindow(append(windowOpts, withWailsOverrides(opts))...)
+}
+
+// withWailsOverrides returns a WindowOption that applies the fields set in
+// parsed IPC options, leaving everything else untouched.
+func withWailsOverrides(opts application.WebviewWindowOptions) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		if opts.Name != "" {
+			c.Name = opts.Name
+		}
+		if opts.Title != "" {
+			c.Title = opts.Title
+		}
+		if opts.Width != 0 {
+			c.Width = opts.Width
+		}
+		if opts.Height != 0 {
+			c.Height = opts.Height
+		}
+	})
 }
 
 // parseWindowOptions extracts window configuration from a map and returns it
 // as a `application.WebviewWindowOptions` struct. This function is used by
-// `handleOpenWindowAction` to parse the incoming message.
-func parseWindowOptions(msg map[string]any) application.WebviewWindowOptions {
+// `handleOpenWindowAction` to parse the incoming message, which is first
+// validated against the `OpenWindowRequest` schema published by `JSONSchema`.
+func parseWindowOptions(msg map[string]any) (application.WebviewWindowOptions, error) {
 	opts := application.WebviewWindowOptions{}
+	if err := validatePayload("OpenWindowRequest", msg); err != nil {
+		return opts, err
+	}
 	if name, ok := msg["name"].(string); ok {
 		opts.Name = name
 	}
@@ -84,7 +453,7 @@ func parseWindowOptions(msg map[string]any) application.WebviewWindowOptions {
 			opts.Height = int(height)
 		}
 	}
-	return opts
+	return opts, nil
 }
 
 // ShowEnvironmentDialog displays a dialog containing detailed information about
@@ -97,12 +466,12 @@ func parseWindowOptions(msg map[string]any) application.WebviewWindowOptions {
 func (s *Service) ShowEnvironmentDialog() {
 	envInfo := s.app.Env.Info()
 
-	details := "Environment Information:\n\n"
-	details += fmt.Sprintf("Operating System: %s\n", envInfo.OS)
-	details += fmt.Sprintf("Architecture: %s\n", envInfo.Arch)
-	details += fmt.Sprintf("Debug Mode: %t\n\n", envInfo.Debug)
-	details += fmt.Sprintf("Dark Mode: %t\n\n", s.app.Env.IsDarkMode())
-	details += "Platform Information:"
+	details := s.T("Environment Information") + ":\n\n"
+	details += s.T("Operating System: %s", envInfo.OS) + "\n"
+	details += s.T("Architecture: %s", envInfo.Arch) + "\n"
+	details += s.T("Debug Mode: %t", envInfo.Debug) + "\n\n"
+	details += s.T("Dark Mode: %t", s.app.Env.IsDarkMode()) + "\n\n"
+	details += s.T("Platform Information") + ":"
 
 	// Add platform-specific details
 	for key, value := range envInfo.PlatformInfo {
@@ -110,13 +479,13 @@ func (s *Service) ShowEnvironmentDialog() {
 	}
 
 	if envInfo.OSInfo != nil {
-		details += fmt.Sprintf("\n\nOS Details:\nName: %s\nVersion: %s",
-			envInfo.OSInfo.Name,
-			envInfo.OSInfo.Version)
+		details += "\n\n" + s.T("OS Details") + ":\n" +
+			s.T("Name: %s", envInfo.OSInfo.Name) + "\n" +
+			s.T("Version: %s", envInfo.OSInfo.Version)
 	}
 
 	dialog := s.app.Dialog.Info()
-	dialog.SetTitle("Environment Information")
+	dialog.SetTitle(s.T("Environment Information"))
 	dialog.SetMessage(details)
 	dialog.Show()
 }
@@ -136,15 +505,41 @@ func (s *Service) ShowEnvironmentDialog() {
 //		log.Fatal(err)
 //	}
 func (s *Service) OpenWindow(opts ...WindowOption) error {
-	wailsOpts := buildWailsWindowOptions(opts...)
-	s.app.Window.NewWithOptions(wailsOpts)
+	config := buildWindowConfig(opts...)
+	wailsOpts := config.wailsOptions()
+	// Reserve the name before anything is keyed by it, so that a duplicate
+	// or an unknown parent fails before the window is created.
+	if err := s.windows.add(config.Name, config.Parent, config.Modal); err != nil {
+		return err
+	}
+	s.placeWindow(config, &wailsOpts)
+	s.applyKiosk(config, &wailsOpts)
+	if err := s.applyNavigationPolicy(config, &wailsOpts); err != nil {
+		s.windows.remove(config.Name)
+		return err
+	}
+	s.applyFileDrop(config, &wailsOpts)
+	s.appMenu.showIn(config, &wailsOpts)
+	window := s.app.Window.NewWithOptions(wailsOpts)
+	s.grantCapabilities(config.Name, window.ID(), config.Capabilities)
+	if err := s.trackWindow(window, config); err != nil {
+		// The closing hook installed by trackWindow forgets the window.
+		window.Close()
+		return err
+	}
+	s.appMenu.track(window, wailsOpts)
+	s.recordOpenWindow(window, config)
+	s.watchPlacement(window, config)
+	s.watchNavigation(window)
+	s.watchFileDrop(window, config)
+	s.watchBackground(window, config)
+	s.lockKioskWindow(window)
 	return nil
 }
 
-// buildWailsWindowOptions creates Wails window options from the given
-// `WindowOption`s. This function is used by `OpenWindow` to construct the
-// options for the new window.
-func buildWailsWindowOptions(opts ...WindowOption) application.WebviewWindowOptions {
+// buildWindowConfig applies the given `WindowOption`s on top of the default
+// window configuration.
+func buildWindowConfig(opts ...WindowOption) *WindowConfig {
 	// Default options
 	winOpts := &WindowConfig{
 		Name:   "main",
@@ -158,27 +553,53 @@ func buildWailsWindowOptions(opts ...WindowOption) application.WebviewWindowOpti
 	for _, opt := range opts {
 		opt.Apply(winOpts)
 	}
+	return winOpts
+}
+
+// buildWailsWindowOptions creates Wails window options from the given
+// `WindowOption`s. This function is used by `OpenWindow` to construct the
+// options for the new window.
+func buildWailsWindowOptions(opts ...WindowOption) application.WebviewWindowOptions {
+	return buildWindowConfig(opts...).wailsOptions()
+}
 
-	// Create Wails window options
+// wailsOptions converts the configuration to Wails window options.
+func (c *WindowConfig) wailsOptions() application.WebviewWindowOptions {
 	return application.WebviewWindowOptions{
-		Name:                winOpts.Name,
-		Title:               winOpts.Title,
-		Width:               winOpts.Width,
-		Height:              winOpts.Height,
-		URL:                 winOpts.URL,
-		AlwaysOnTop:         winOpts.AlwaysOnTop,
-		Hidden:              winOpts.Hidden,
-		MinimiseButtonState: winOpts.MinimiseButtonState,
-		MaximiseButtonState: winOpts.MaximiseButtonState,
-		CloseButtonState:    winOpts.CloseButtonState,
-		Frameless:           winOpts.Frameless,
-	}
-}
-
-// monitorScreenChanges listens for theme change events and logs when the screen
-// configuration changes.
+		Name:                c.Name,
+		Title:               c.Title,
+		Width:               c.Width,
+		Height:              c.Height,
+		URL:                 c.URL,
+		AlwaysOnTop:         c.AlwaysOnTop,
+		Hidden:              c.Hidden,
+		MinimiseButtonState: c.MinimiseButtonState,
+		MaximiseButtonState: c.MaximiseButtonState,
+		CloseButtonState:    c.CloseButtonState,
+		Frameless:           c.Frameless,
+
+		DefaultContextMenuDisabled: c.DefaultContextMenuDisabled,
+	}
+}
+
+// monitorScreenChanges logs theme changes and keeps windows on-screen when
+// the displays themselves change, see `Service.EnsureWindowsOnScreen`.
 func (s *Service) monitorScreenChanges() {
 	s.app.Event.OnApplicationEvent(events.Common.ThemeChanged, func(event *application.ApplicationEvent) {
 		s.app.Logger.Info("Screen configuration changed")
 	})
+	s.watchScreens()
+}
+
+// onMainThread runs fn on the main thread, where native menus may be changed,
+// and waits for it. Without a running app, fn runs on the calling goroutine.
+func (s *Service) onMainThread(fn func()) {
+	switch {
+	case s.mainThread != nil:
+		s.mainThread(fn)
+	case s.app != nil:
+		application.InvokeSync(fn)
+	default:
+		fn()
+	}
 }
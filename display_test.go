@@ -1,6 +1,7 @@
 package display
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -9,9 +10,10 @@ import (
 
 func TestParseWindowOptions(t *testing.T) {
 	tests := []struct {
-		name string
-		msg  map[string]any
-		want application.WebviewWindowOptions
+		name    string
+		msg     map[string]any
+		want    application.WebviewWindowOptions
+		wantErr bool
 	}{
 		{
 			name: "Valid options",
@@ -71,11 +73,7 @@ func TestParseWindowOptions(t *testing.T) {
 					"Height": 768.0,
 				},
 			},
-			want: application.WebviewWindowOptions{
-				Name:   "main",
-				Title:  "My App",
-				Height: 768,
-			},
+			wantErr: true,
 		},
 		{
 			name: "Invalid height type",
@@ -87,11 +85,7 @@ func TestParseWindowOptions(t *testing.T) {
 					"Height": "not a number",
 				},
 			},
-			want: application.WebviewWindowOptions{
-				Name:  "main",
-				Title: "My App",
-				Width: 1024,
-			},
+			wantErr: true,
 		},
 		{
 			name: "Deeply nested and complex message",
@@ -106,19 +100,21 @@ func TestParseWindowOptions(t *testing.T) {
 					},
 				},
 			},
-			want: application.WebviewWindowOptions{
-				Name:   "main",
-				Title:  "My App",
-				Width:  1024,
-				Height: 768,
-			},
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := parseWindowOptions(tt.msg); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("parseWindowOptions() = %v, want %v", got, tt.want)
+			got, err := parseWindowOptions(tt.msg)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPayload) {
+					t.Errorf("parseWindowOptions() error = %v, want ErrInvalidPayload", err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseWindowOptions() = %v, %v, want %v", got, err, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,282 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrActionTimeout is returned when an action does not finish within its
+	// timeout.
+	ErrActionTimeout = errors.New("display: action timed out")
+	// ErrActionCancelled is returned when the caller cancels an action.
+	ErrActionCancelled = errors.New("display: action cancelled")
+	// ErrDuplicateAction is returned when a caller reuses the ID of one of
+	// its requests that is still pending.
+	ErrDuplicateAction = errors.New("display: action ID already pending")
+	// ErrShuttingDown is returned for actions called after the app began to
+	// shut down.
+	ErrShuttingDown = errors.New("display: shutting down")
+)
+
+const (
+	// defaultActionTimeout is how long an action may take unless changed with
+	// `SetActionTimeout` or the request's own timeout.
+	defaultActionTimeout = 30 * time.Second
+	// actionShutdownGrace is how long shutdown waits for running handlers,
+	// including those still running after their call timed out.
+	actionShutdownGrace = 5 * time.Second
+)
+
+// Handler states, used to tell whether a call gave up on its handler.
+const (
+	handlerRunning int32 = iota
+	handlerFinished
+	handlerAbandoned
+)
+
+// Error codes carried by `ActionError`.
+const (
+	ActionErrorTimeout          = "timeout"
+	ActionErrorCancelled        = "cancelled"
+	ActionErrorPermissionDenied = "permission_denied"
+	ActionErrorUnknownAction    = "unknown_action"
+	ActionErrorRateLimited      = "rate_limited"
+	ActionErrorDuplicateID      = "duplicate_id"
+	ActionErrorInvalidPayload   = "invalid_payload"
+	ActionErrorFailed           = "failed"
+)
+
+// ActionRequest is the envelope a frontend passes to `Bridge.Invoke` to run
+// an action. Its ID lets the frontend cancel it with `Bridge.CancelInvoke`.
+//
+// example:
+//
+//	// From a frontend:
+//	const reply = await Call.ByName('github.com/Snider/display.Bridge.Invoke', {
+//		id: crypto.randomUUID(),
+//		action: "dialog.openFile",
+//		payload: {title: "Import wallet"},
+//		timeoutMs: 60000,
+//	})
+type ActionRequest struct {
+	ID      string         `json:"id,omitempty"`
+	Action  string         `json:"action"`
+	Payload map[string]any `json:"payload,omitempty"`
+	// TimeoutMs overrides the action's timeout when it is positive.
+	TimeoutMs int `json:"timeoutMs,omitempty"`
+}
+
+// ActionReply is the outcome of an `ActionRequest`, returned to the caller
+// only.
+type ActionReply struct {
+	ID     string       `json:"id"`
+	Action string       `json:"action"`
+	Result any          `json:"result,omitempty"`
+	Error  *ActionError `json:"error,omitempty"`
+}
+
+// ActionError describes why an action failed. Code is one of the
+// `ActionError*` constants.
+type ActionError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// newActionError converts an error returned by an action into its reply form.
+func newActionError(err error) *ActionError {
+	if err == nil {
+		return nil
+	}
+	code := ActionErrorFailed
+	switch {
+	case errors.Is(err, ErrActionTimeout):
+		code = ActionErrorTimeout
+	case errors.Is(err, ErrActionCancelled):
+		code = ActionErrorCancelled
+	case errors.Is(err, ErrPermissionDenied):
+		code = ActionErrorPermissionDenied
+	case errors.Is(err, ErrUnknownAction):
+		code = ActionErrorUnknownAction
+	case errors.Is(err, ErrRateLimited):
+		code = ActionErrorRateLimited
+	case errors.Is(err, ErrDuplicateAction):
+		code = ActionErrorDuplicateID
+	case errors.Is(err, ErrInvalidPayload):
+		code = ActionErrorInvalidPayload
+	}
+	return &ActionError{Code: code, Message: err.Error()}
+}
+
+// actionOutcome is what an action handler returned.
+type actionOutcome struct {
+	result any
+	err    error
+}
+
+// Call invokes an action for a caller and waits for its reply. The call fails
+// with `ErrActionTimeout` once its timeout passes, with `ErrActionCancelled`
+// if `CancelAction` is called with its ID, and with `ErrDuplicateAction` if
+// the caller already has a pending request with the same ID. A caller that
+// names a window opened by the service acts with that window's capabilities;
+// other connections, such as a WebSocket bridge, can call it with their own
+// caller name. Pages use `Bridge.Invoke` instead.
+//
+// example:
+//
+//	reply := displayService.Call(ctx, "main", display.ActionRequest{
+//		ID:     "1",
+//		Action: display.ActionNameNotify,
+//		Payload: map[string]any{"Title": "Saved"},
+//	})
+//	if reply.Error != nil {
+//		log.Println(reply.Error.Message)
+//	}
+func (s *Service) Call(ctx context.Context, caller string, req ActionRequest) ActionReply {
+	return s.call(ctx, s.callFrom(caller, ActionCall{}), req)
+}
+
+// call runs req for the caller described by from and waits for its reply.
+func (s *Service) call(ctx context.Context, from ActionCall, req ActionRequest) ActionReply {
+	timeout := s.actionTimeout(req.Action)
+	if req.TimeoutMs > 0 {
+		timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if timeout > 0 {
+		var stop context.CancelFunc
+		ctx, stop = context.WithTimeoutCause(ctx, timeout, ErrActionTimeout)
+		defer stop()
+	}
+	if req.ID != "" {
+		key := pendingKey(from.caller(), req.ID)
+		s.pendingMu.Lock()
+		if _, ok := s.pending[key]; ok {
+			s.pendingMu.Unlock()
+			return ActionReply{ID: req.ID, Action: req.Action, Error: newActionError(fmt.Errorf("%w: %q", ErrDuplicateAction, req.ID))}
+		}
+		s.pending[key] = cancel
+		s.pendingMu.Unlock()
+		defer func() {
+			s.pendingMu.Lock()
+			delete(s.pending, key)
+			s.pendingMu.Unlock()
+		}()
+	}
+
+	call := from
+	call.ID, call.Action, call.Payload = req.ID, req.Action, req.Payload
+	done := make(chan actionOutcome, 1)
+	var state atomic.Int32
+	if !s.startHandler() {
+		return ActionReply{ID: req.ID, Action: req.Action, Error: newActionError(ErrShuttingDown)}
+	}
+	go func() {
+		defer s.handlers.Done()
+		start := time.Now()
+		result, err := s.dispatch(ctx, call)
+		done <- actionOutcome{result: result, err: err}
+		if !state.CompareAndSwap(handlerRunning, handlerFinished) {
+			s.overdue.Add(-1)
+			s.logAction("Action finished after its call gave up", call, "elapsed", time.Since(start))
+		}
+	}()
+	reply := ActionReply{ID: req.ID, Action: req.Action}
+	select {
+	case outcome := <-done:
+		reply.Result, reply.Error = outcome.result, newActionError(outcomeError(ctx, outcome.err))
+	case <-ctx.Done():
+		if !state.CompareAndSwap(handlerRunning, handlerAbandoned) {
+			// The handler finished as the call gave up; use its outcome.
+			outcome := <-done
+			reply.Result, reply.Error = outcome.result, newActionError(outcomeError(ctx, outcome.err))
+			break
+		}
+		s.overdue.Add(1)
+		s.logAction("Action still running after its call gave up", call, "cause", context.Cause(ctx), "overdue", s.overdue.Load())
+		reply.Error = newActionError(context.Cause(ctx))
+	}
+	return reply
+}
+
+// outcomeError returns the error a handler returned, replacing the bare
+// ctx.Err() of a handler that stopped for its context with the reason the
+// call gave up, such as `ErrActionTimeout`.
+func outcomeError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return context.Cause(ctx)
+	}
+	return err
+}
+
+// logAction warns about an action handler that ignores its context.
+func (s *Service) logAction(message string, call ActionCall, args ...any) {
+	if s.app == nil {
+		return
+	}
+	s.app.Logger.Warn(message, append([]any{"window", call.Window, "action", call.Action}, args...)...)
+}
+
+// startHandler counts a new action handler, unless the service has stopped
+// accepting actions for shutdown.
+func (s *Service) startHandler() bool {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	if s.handlersClosed {
+		return false
+	}
+	s.handlers.Add(1)
+	return true
+}
+
+// waitForActions stops accepting actions and waits, up to
+// `actionShutdownGrace`, for action handlers to return, so that handlers
+// still running after their call timed out are not cut off mid-write when
+// the app quits.
+func (s *Service) waitForActions() {
+	s.handlersMu.Lock()
+	s.handlersClosed = true
+	s.handlersMu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		s.handlers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(actionShutdownGrace):
+		s.logAction("Shutting down with action handlers still running", ActionCall{}, "overdue", s.overdue.Load())
+	}
+}
+
+// CancelAction cancels a pending request made by caller. It reports whether
+// a request with that ID was still running. It is for Go callers of `Call`;
+// pages cancel their own requests with `Bridge.CancelInvoke`.
+//
+// example:
+//
+//	displayService.CancelAction("main", "42")
+func (s *Service) CancelAction(caller, id string) bool {
+	return s.cancelAction(s.callFrom(caller, ActionCall{}).caller(), id)
+}
+
+// cancelAction cancels a pending request by the caller key of its
+// `ActionCall`.
+func (s *Service) cancelAction(caller, id string) bool {
+	s.pendingMu.Lock()
+	cancel, ok := s.pending[pendingKey(caller, id)]
+	s.pendingMu.Unlock()
+	if ok {
+		cancel(ErrActionCancelled)
+	}
+	return ok
+}
+
+// pendingKey identifies a pending request. IDs are only unique per caller.
+func pendingKey(caller, id string) string {
+	return caller + "\x00" + id
+}
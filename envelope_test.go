@@ -0,0 +1,133 @@
+package display
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCallReplies(t *testing.T) {
+	s, _ := New()
+	s.grantCapabilities("main", 1, nil)
+	s.RegisterAction("test.echo", "", func(_ context.Context, call ActionCall) (any, error) {
+		return call.Payload["text"], nil
+	})
+	s.RegisterAction("test.block", "", func(ctx context.Context, _ ActionCall) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx := context.Background()
+
+	reply := s.Call(ctx, "main", ActionRequest{ID: "1", Action: "test.echo", Payload: map[string]any{"text": "hi"}})
+	if reply.ID != "1" || reply.Result != "hi" || reply.Error != nil {
+		t.Errorf("Call() = %+v", reply)
+	}
+
+	reply = s.Call(ctx, "main", ActionRequest{ID: "2", Action: "test.block", TimeoutMs: 10})
+	if reply.Error == nil || reply.Error.Code != ActionErrorTimeout {
+		t.Errorf("Call() timeout = %+v, want %q", reply, ActionErrorTimeout)
+	}
+
+	reply = s.Call(ctx, "system-tray", ActionRequest{ID: "3", Action: ActionNameNotify})
+	if reply.Error == nil || reply.Error.Code != ActionErrorPermissionDenied {
+		t.Errorf("Call() denied = %+v, want %q", reply, ActionErrorPermissionDenied)
+	}
+
+	reply = s.Call(ctx, "main", ActionRequest{ID: "4", Action: "test.missing"})
+	if reply.Error == nil || reply.Error.Code != ActionErrorUnknownAction {
+		t.Errorf("Call() unknown = %+v, want %q", reply, ActionErrorUnknownAction)
+	}
+}
+
+func TestCancelAction(t *testing.T) {
+	s, _ := New()
+	started := make(chan struct{})
+	s.RegisterAction("test.block", "", func(ctx context.Context, _ ActionCall) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	go func() {
+		<-started
+		if !s.CancelAction("other", "7") && s.CancelAction("main", "7") {
+			return
+		}
+		t.Error("CancelAction() did not cancel only the caller's request")
+	}()
+	reply := s.Call(context.Background(), "main", ActionRequest{ID: "7", Action: "test.block", TimeoutMs: int(time.Minute / time.Millisecond)})
+	if reply.Error == nil || reply.Error.Code != ActionErrorCancelled {
+		t.Errorf("Call() = %+v, want %q", reply, ActionErrorCancelled)
+	}
+	if s.CancelAction("main", "7") {
+		t.Error("CancelAction() after the reply = true")
+	}
+}
+
+func TestCallRejectsDuplicateID(t *testing.T) {
+	s, _ := New()
+	started, release := make(chan struct{}), make(chan struct{})
+	s.RegisterAction("test.block", "", func(context.Context, ActionCall) (any, error) {
+		close(started)
+		<-release
+		return "first", nil
+	})
+	first := make(chan ActionReply)
+	go func() {
+		first <- s.Call(context.Background(), "main", ActionRequest{ID: "1", Action: "test.block"})
+	}()
+	<-started
+	reply := s.Call(context.Background(), "main", ActionRequest{ID: "1", Action: "test.block"})
+	if reply.Error == nil || reply.Error.Code != ActionErrorDuplicateID {
+		t.Errorf("Call() with a pending ID = %+v, want %q", reply, ActionErrorDuplicateID)
+	}
+	// The first request must still be cancellable by its ID.
+	if !s.CancelAction("main", "1") {
+		t.Error("CancelAction() lost the first request")
+	}
+	if reply := <-first; reply.Error == nil || reply.Error.Code != ActionErrorCancelled {
+		t.Errorf("first Call() = %+v, want %q", reply, ActionErrorCancelled)
+	}
+	close(release)
+}
+
+func TestCallTracksOverdueHandlers(t *testing.T) {
+	s, _ := New()
+	release := make(chan struct{})
+	s.RegisterAction("test.stuck", "", func(context.Context, ActionCall) (any, error) {
+		<-release
+		return nil, nil
+	})
+	reply := s.Call(context.Background(), "main", ActionRequest{Action: "test.stuck", TimeoutMs: 10})
+	if reply.Error == nil || reply.Error.Code != ActionErrorTimeout {
+		t.Fatalf("Call() = %+v, want %q", reply, ActionErrorTimeout)
+	}
+	if got := s.overdue.Load(); got != 1 {
+		t.Errorf("overdue after the timeout = %d, want 1", got)
+	}
+	close(release)
+	s.handlers.Wait()
+	if got := s.overdue.Load(); got != 0 {
+		t.Errorf("overdue after the handler returned = %d, want 0", got)
+	}
+}
+
+func TestCallDuringShutdown(t *testing.T) {
+	s, _ := New()
+	s.RegisterAction("test.noop", "", func(context.Context, ActionCall) (any, error) { return nil, nil })
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Call(context.Background(), "main", ActionRequest{Action: "test.noop"})
+		}()
+	}
+	s.waitForActions()
+	wg.Wait()
+
+	reply := s.Call(context.Background(), "main", ActionRequest{ID: "1", Action: "test.noop"})
+	if reply.Error == nil || reply.Error.Message != ErrShuttingDown.Error() {
+		t.Errorf("Call() after shutdown = %+v, want %v", reply, ErrShuttingDown)
+	}
+}
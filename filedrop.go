@@ -0,0 +1,261 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// DroppedFile is a file dropped onto a window that passed the window's
+// `FileDropFilter`.
+type DroppedFile struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	// MIMEType is sniffed from the file's content, falling back to its
+	// extension for content that cannot be told apart, such as text.
+	MIMEType string `json:"mimeType"`
+	Size     int64  `json:"size"`
+}
+
+// RejectedFile is a dropped file refused by the window's `FileDropFilter`.
+type RejectedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// FileDrop describes files dropped onto a window. It is passed to the
+// window's `FileDropHandler` and sent to the window as an `EventFileDrop`.
+type FileDrop struct {
+	Window string        `json:"window"`
+	Files  []DroppedFile `json:"files"`
+	// Rejected are the dropped files the filter refused. They never reach
+	// the handler's Files.
+	Rejected []RejectedFile `json:"rejected,omitempty"`
+	// X and Y are the drop position in the window, in CSS pixels.
+	X int `json:"x"`
+	Y int `json:"y"`
+	// Target is the ID of the element the files were dropped on, if any.
+	Target string `json:"target,omitempty"`
+}
+
+// FileDropHandler handles files dropped onto a window. It is only called
+// when at least one file is accepted.
+type FileDropHandler func(drop FileDrop)
+
+// FileDropFilter restricts the files a window accepts.
+//
+// example:
+//
+//	filter := display.FileDropFilter{
+//		Types:   []string{".json", "image/*"},
+//		MaxSize: 10 << 20,
+//	}
+type FileDropFilter struct {
+	// Types are the accepted MIME types, such as "image/png" or "image/*",
+	// and file extensions, such as ".json". Empty accepts every type.
+	Types []string `json:"types,omitempty"`
+	// MaxSize is the largest accepted file, in bytes. Zero accepts any size.
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// MaxFiles is the most files accepted from one drop. Zero accepts any
+	// number.
+	MaxFiles int `json:"maxFiles,omitempty"`
+}
+
+// fileDropTarget is the drop handler and filter of an open window.
+type fileDropTarget struct {
+	handler FileDropHandler
+	filter  FileDropFilter
+}
+
+// WithFileDrop lets files be dropped onto the window. Dropped files are
+// checked against the window's `FileDropFilter` before handler is called,
+// and the window is sent an `EventFileDrop` either way.
+//
+// example:
+//
+//	err := displayService.OpenWindow(
+//		display.WithName("import"),
+//		display.WithFileDrop(func(drop display.FileDrop) {
+//			for _, f := range drop.Files {
+//				importWorkspace(f.Path)
+//			}
+//		}),
+//		display.WithFileDropFilter(display.FileDropFilter{Types: []string{".json"}}),
+//	)
+func WithFileDrop(handler FileDropHandler) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.FileDrop = handler
+	})
+}
+
+// WithFileDropFilter restricts the files accepted by `WithFileDrop`.
+func WithFileDropFilter(filter FileDropFilter) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.FileDropFilter = &filter
+	})
+}
+
+// applyFileDrop enables drag and drop on a window that has a drop handler.
+func (s *Service) applyFileDrop(config *WindowConfig, opts *application.WebviewWindowOptions) {
+	if config.FileDrop == nil {
+		return
+	}
+	opts.EnableDragAndDrop = true
+	target := &fileDropTarget{handler: config.FileDrop}
+	if config.FileDropFilter != nil {
+		target.filter = *config.FileDropFilter
+	}
+	s.fileDropsMu.Lock()
+	defer s.fileDropsMu.Unlock()
+	s.fileDrops[config.Name] = target
+}
+
+// watchFileDrop delivers files dropped onto a window to its drop handler.
+func (s *Service) watchFileDrop(window application.Window, config *WindowConfig) {
+	if config.FileDrop == nil {
+		return
+	}
+	window.OnWindowEvent(events.Common.WindowDropZoneFilesDropped, func(event *application.WindowEvent) {
+		ctx := event.Context()
+		drop := FileDrop{Window: config.Name}
+		if details := ctx.DropZoneDetails(); details != nil {
+			drop.X, drop.Y, drop.Target = details.X, details.Y, details.ElementID
+		}
+		s.handleFileDrop(drop, ctx.DroppedFiles())
+	})
+}
+
+// handleFileDrop filters dropped paths, announces the drop to its window and
+// passes the accepted files to the window's handler.
+func (s *Service) handleFileDrop(drop FileDrop, paths []string) {
+	s.fileDropsMu.Lock()
+	target := s.fileDrops[drop.Window]
+	s.fileDropsMu.Unlock()
+	if target == nil {
+		return
+	}
+	drop.Files, drop.Rejected = target.filter.check(paths)
+	if s.app != nil {
+		for _, rejected := range drop.Rejected {
+			s.app.Logger.Info("Dropped file rejected", "window", drop.Window, "path", rejected.Path, "reason", rejected.Reason)
+		}
+		if window, ok := s.app.Window.GetByName(drop.Window); ok {
+			window.DispatchWailsEvent(&application.CustomEvent{Name: EventFileDrop, Data: drop})
+		}
+	}
+	if len(drop.Files) > 0 {
+		target.handler(drop)
+	}
+}
+
+// forgetFileDrop removes the drop handler of a closed window.
+func (s *Service) forgetFileDrop(name string) {
+	s.fileDropsMu.Lock()
+	defer s.fileDropsMu.Unlock()
+	delete(s.fileDrops, name)
+}
+
+// check splits dropped paths into the files the filter accepts and those it
+// rejects. Only regular files are accepted.
+func (f FileDropFilter) check(paths []string) ([]DroppedFile, []RejectedFile) {
+	accepted := []DroppedFile{}
+	var rejected []RejectedFile
+	for _, path := range paths {
+		file, err := inspectDroppedFile(path)
+		switch {
+		case err != nil:
+			rejected = append(rejected, RejectedFile{Path: path, Reason: err.Error()})
+		case f.MaxFiles > 0 && len(accepted) >= f.MaxFiles:
+			rejected = append(rejected, RejectedFile{Path: path, Reason: fmt.Sprintf("more than %d files", f.MaxFiles)})
+		case f.MaxSize > 0 && file.Size > f.MaxSize:
+			rejected = append(rejected, RejectedFile{Path: path, Reason: fmt.Sprintf("larger than %d bytes", f.MaxSize)})
+		case !f.accepts(file):
+			rejected = append(rejected, RejectedFile{Path: path, Reason: "type " + file.MIMEType + " not accepted"})
+		default:
+			accepted = append(accepted, file)
+		}
+	}
+	return accepted, rejected
+}
+
+// accepts reports whether a file matches one of the filter's types. A file
+// matched by extension must also have content of that extension's type, if
+// it has a known one.
+func (f FileDropFilter) accepts(file DroppedFile) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(file.Name))
+	for _, t := range f.Types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch {
+		case strings.HasPrefix(t, "."):
+			if t != ext {
+				continue
+			}
+			byExt, _, _ := strings.Cut(mime.TypeByExtension(ext), ";")
+			if byExt == "" || byExt == file.MIMEType {
+				return true
+			}
+		case strings.HasSuffix(t, "/*"):
+			if strings.HasPrefix(file.MIMEType, strings.TrimSuffix(t, "*")) {
+				return true
+			}
+		case t == file.MIMEType:
+			return true
+		}
+	}
+	return false
+}
+
+// inspectDroppedFile stats a dropped path and sniffs its MIME type.
+func inspectDroppedFile(path string) (DroppedFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return DroppedFile{}, fmt.Errorf("cannot read file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return DroppedFile{}, errors.New("not a regular file")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return DroppedFile{}, fmt.Errorf("cannot read file: %w", err)
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return DroppedFile{}, fmt.Errorf("cannot read file: %w", err)
+	}
+	return DroppedFile{
+		Path:     path,
+		Name:     filepath.Base(path),
+		MIMEType: detectMIMEType(path, head[:n]),
+		Size:     info.Size(),
+	}, nil
+}
+
+// detectMIMEType sniffs the MIME type of a file's content. Text and unknown
+// binary content, which sniffing cannot tell apart further, are typed by the
+// file's extension instead; unknown binary content is never given a text or
+// media type by its name, so an executable renamed to .png is not an image.
+func detectMIMEType(path string, head []byte) string {
+	sniffed, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	byExt, _, _ := strings.Cut(mime.TypeByExtension(filepath.Ext(path)), ";")
+	switch {
+	case byExt == "":
+	case sniffed == "text/plain":
+		return byExt
+	case sniffed == "application/octet-stream" && strings.HasPrefix(byExt, "application/"):
+		return byExt
+	}
+	return sniffed
+}
@@ -0,0 +1,101 @@
+package display
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func writeDropFiles(t *testing.T) (dir string, files map[string]string) {
+	t.Helper()
+	dir = t.TempDir()
+	files = map[string]string{
+		"workspace.json": `{"name": "core"}`,
+		"logo.png":       "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+		"fake.png":       "MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff",
+		"big.json":       `{"padding": "` + string(make([]byte, 64)) + `"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, files
+}
+
+func TestFileDropFilter(t *testing.T) {
+	dir, _ := writeDropFiles(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+	tests := []struct {
+		name         string
+		filter       FileDropFilter
+		paths        []string
+		wantAccepted []string
+		wantRejected []string
+	}{
+		{"Any type", FileDropFilter{}, []string{path("workspace.json"), path("logo.png")}, []string{"workspace.json", "logo.png"}, nil},
+		{"By extension", FileDropFilter{Types: []string{".JSON"}}, []string{path("workspace.json"), path("logo.png")}, []string{"workspace.json"}, []string{"logo.png"}},
+		{"By MIME wildcard", FileDropFilter{Types: []string{"image/*"}}, []string{path("logo.png"), path("fake.png")}, []string{"logo.png"}, []string{"fake.png"}},
+		{"Spoofed extension", FileDropFilter{Types: []string{".png"}}, []string{path("fake.png")}, nil, []string{"fake.png"}},
+		{"Too large", FileDropFilter{MaxSize: 32}, []string{path("workspace.json"), path("big.json")}, []string{"workspace.json"}, []string{"big.json"}},
+		{"Too many", FileDropFilter{MaxFiles: 1}, []string{path("workspace.json"), path("logo.png")}, []string{"workspace.json"}, []string{"logo.png"}},
+		{"Directories and missing files", FileDropFilter{}, []string{dir, path("missing.json")}, nil, []string{filepath.Base(dir), "missing.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accepted, rejected := tt.filter.check(tt.paths)
+			var gotAccepted, gotRejected []string
+			for _, f := range accepted {
+				gotAccepted = append(gotAccepted, f.Name)
+			}
+			for _, f := range rejected {
+				gotRejected = append(gotRejected, filepath.Base(f.Path))
+			}
+			if !reflect.DeepEqual(gotAccepted, tt.wantAccepted) || !reflect.DeepEqual(gotRejected, tt.wantRejected) {
+				t.Errorf("check() accepted %v and rejected %v, want %v and %v", gotAccepted, gotRejected, tt.wantAccepted, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func TestDroppedFileDetails(t *testing.T) {
+	dir, files := writeDropFiles(t)
+	accepted, _ := FileDropFilter{}.check([]string{filepath.Join(dir, "workspace.json"), filepath.Join(dir, "logo.png")})
+	want := []DroppedFile{
+		{Path: filepath.Join(dir, "workspace.json"), Name: "workspace.json", MIMEType: "application/json", Size: int64(len(files["workspace.json"]))},
+		{Path: filepath.Join(dir, "logo.png"), Name: "logo.png", MIMEType: "image/png", Size: int64(len(files["logo.png"]))},
+	}
+	if !reflect.DeepEqual(accepted, want) {
+		t.Errorf("check() = %+v, want %+v", accepted, want)
+	}
+}
+
+func TestHandleFileDrop(t *testing.T) {
+	dir, _ := writeDropFiles(t)
+	s, _ := New()
+	var drops []FileDrop
+	config := buildWindowConfig(
+		WithName("import"),
+		WithFileDrop(func(drop FileDrop) { drops = append(drops, drop) }),
+		WithFileDropFilter(FileDropFilter{Types: []string{".json"}}),
+	)
+	s.applyFileDrop(config, &application.WebviewWindowOptions{})
+
+	s.handleFileDrop(FileDrop{Window: "import", X: 10, Y: 20, Target: "dropzone"}, []string{filepath.Join(dir, "logo.png")})
+	if len(drops) != 0 {
+		t.Fatalf("handler called for a rejected drop: %+v", drops)
+	}
+	s.handleFileDrop(FileDrop{Window: "import", X: 10, Y: 20, Target: "dropzone"}, []string{filepath.Join(dir, "workspace.json"), filepath.Join(dir, "logo.png")})
+	if len(drops) != 1 || len(drops[0].Files) != 1 || len(drops[0].Rejected) != 1 || drops[0].X != 10 || drops[0].Target != "dropzone" {
+		t.Fatalf("drops = %+v", drops)
+	}
+
+	s.forgetFileDrop("import")
+	s.handleFileDrop(FileDrop{Window: "import"}, []string{filepath.Join(dir, "workspace.json")})
+	if len(drops) != 1 {
+		t.Errorf("handler called after the window closed")
+	}
+}
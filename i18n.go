@@ -0,0 +1,329 @@
+package display
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// DefaultLocale is the locale of the display package's own strings, used for
+// every message missing from the current locale's catalogue.
+const DefaultLocale = "en"
+
+// translator holds the message catalogues, the current locale and the menu
+// items to relabel when it changes. Messages are identified by their English
+// text, as in gettext, so an untranslated message is shown in English.
+type translator struct {
+	mu         sync.Mutex
+	locale     string
+	catalogues map[string]map[string]string
+	labels     []localisedLabel
+}
+
+// localisedLabel is a menu item labelled with a translated message.
+type localisedLabel struct {
+	item    *application.MenuItem
+	message string
+}
+
+// newTranslator creates a translator for a locale.
+func newTranslator(locale string) *translator {
+	return &translator{locale: NormaliseLocale(locale), catalogues: map[string]map[string]string{}}
+}
+
+// DetectLocale returns the user's locale: the one chosen in the system
+// settings on Windows and macOS, and otherwise, or if it cannot be read, the
+// one in the LC_ALL, LC_MESSAGES and LANG environment variables. It is
+// `DefaultLocale` if none is set.
+//
+// example:
+//
+//	locale := display.DetectLocale() // "de-DE" when LANG=de_DE.UTF-8
+func DetectLocale() string {
+	if locale := NormaliseLocale(systemLocale()); locale != "" {
+		return locale
+	}
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := NormaliseLocale(os.Getenv(key)); locale != "" {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// NormaliseLocale converts a POSIX locale such as "pt_BR.UTF-8@euro" to a
+// language tag such as "pt-BR", keeping a script such as the "Hans" of
+// "zh-Hans-CN". The "C" and "POSIX" locales are English.
+func NormaliseLocale(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	switch locale {
+	case "":
+		return ""
+	case "C", "POSIX":
+		return DefaultLocale
+	}
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToUpper(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// translate returns message in the current locale, trying the locale's
+// language when the region has no catalogue, and English last.
+func (t *translator) translate(message string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	locale := t.locale
+	for locale != "" {
+		if translated, ok := t.catalogues[locale][message]; ok && translated != "" {
+			return translated
+		}
+		cut := strings.LastIndex(locale, "-")
+		if cut < 0 {
+			break
+		}
+		locale = locale[:cut]
+	}
+	return message
+}
+
+// Locale returns the current locale, such as "en" or "de-DE".
+func (s *Service) Locale() string {
+	s.i18n.mu.Lock()
+	defer s.i18n.mu.Unlock()
+	return s.i18n.locale
+}
+
+// SetLocale changes the locale, relabels the app and tray menus and emits
+// `EventLocaleChanged` so frontends can follow.
+//
+// example:
+//
+//	displayService.SetLocale("de")
+func (s *Service) SetLocale(locale string) {
+	locale = NormaliseLocale(locale)
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	s.i18n.mu.Lock()
+	s.i18n.locale = locale
+	s.i18n.mu.Unlock()
+	// Menus are rendered on the main thread, so relabelling there sees
+	// every item they label.
+	s.onMainThread(func() {
+		s.i18n.mu.Lock()
+		labels := slices.Clone(s.i18n.labels)
+		s.i18n.mu.Unlock()
+		for _, label := range labels {
+			label.item.SetLabel(s.i18n.translate(label.message))
+		}
+	})
+	if s.app != nil {
+		s.app.Event.Emit(EventLocaleChanged, ActionLocaleChanged{Locale: locale})
+	}
+}
+
+// T translates an English message into the current locale. Arguments are
+// formatted into the translated message as by `fmt.Sprintf`.
+//
+// example:
+//
+//	title := displayService.T("Environment Information")
+//	details := displayService.T("Operating System: %s", runtime.GOOS)
+func (s *Service) T(message string, args ...any) string {
+	translated := s.i18n.translate(message)
+	if len(args) > 0 {
+		return fmt.Sprintf(translated, args...)
+	}
+	return translated
+}
+
+// localise labels a menu item with a translated message, and keeps it
+// translated when the locale changes.
+func (s *Service) localise(item *application.MenuItem, message string) *application.MenuItem {
+	if item == nil {
+		return nil
+	}
+	item.SetLabel(s.i18n.translate(message))
+	s.i18n.mu.Lock()
+	defer s.i18n.mu.Unlock()
+	for i, label := range s.i18n.labels {
+		if label.item == item {
+			s.i18n.labels[i].message = message
+			return item
+		}
+	}
+	s.i18n.labels = append(s.i18n.labels, localisedLabel{item: item, message: message})
+	return item
+}
+
+// unlocalise stops translating menu items that have been discarded.
+func (s *Service) unlocalise(items ...*application.MenuItem) {
+	s.i18n.mu.Lock()
+	defer s.i18n.mu.Unlock()
+	s.i18n.labels = slices.DeleteFunc(s.i18n.labels, func(label localisedLabel) bool {
+		return slices.Contains(items, label.item)
+	})
+}
+
+// AddCatalogue adds translations for a locale, keyed by their English
+// message. Later catalogues override earlier ones.
+//
+// example:
+//
+//	displayService.AddCatalogue("de", map[string]string{
+//		"Open Desktop": "Desktop öffnen",
+//		"Quit":         "Beenden",
+//	})
+func (s *Service) AddCatalogue(locale string, messages map[string]string) {
+	locale = NormaliseLocale(locale)
+	s.i18n.mu.Lock()
+	catalogue, ok := s.i18n.catalogues[locale]
+	if !ok {
+		catalogue = map[string]string{}
+		s.i18n.catalogues[locale] = catalogue
+	}
+	for message, translated := range messages {
+		catalogue[message] = translated
+	}
+	current := s.i18n.locale
+	s.i18n.mu.Unlock()
+	if current == locale || strings.HasPrefix(current, locale+"-") {
+		s.SetLocale(current)
+	}
+}
+
+// LoadCatalogues adds every catalogue in fsys. Catalogues are JSON objects
+// of English messages and their translations, or gettext PO files, named
+// after their locale, such as "de.json" or "pt_BR.po".
+//
+// example:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	sub, _ := fs.Sub(locales, "locales")
+//	err := displayService.LoadCatalogues(sub)
+func (s *Service) LoadCatalogues(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("display: reading catalogues: %w", err)
+	}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".po") {
+			continue
+		}
+		f, err := fsys.Open(entry.Name())
+		if err != nil {
+			return fmt.Errorf("display: reading catalogue %s: %w", entry.Name(), err)
+		}
+		var messages map[string]string
+		if ext == ".po" {
+			messages, err = ParsePO(f)
+		} else {
+			err = json.NewDecoder(f).Decode(&messages)
+		}
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("display: reading catalogue %s: %w", entry.Name(), err)
+		}
+		s.AddCatalogue(strings.TrimSuffix(entry.Name(), ext), messages)
+	}
+	return nil
+}
+
+// ParsePO reads the translations of a gettext PO file. Plural forms use
+// their first translation, message contexts are ignored, and entries marked
+// fuzzy are skipped, as gettext does.
+func ParsePO(r io.Reader) (map[string]string, error) {
+	messages := map[string]string{}
+	var msgid, msgstr, current *strings.Builder
+	fuzzy := false
+	flush := func() {
+		if msgid != nil && msgstr != nil && msgid.Len() > 0 && !fuzzy {
+			messages[msgid.String()] = msgstr.String()
+		}
+		msgid, msgstr, current, fuzzy = nil, nil, nil, false
+	}
+	// A comment, context or msgid after a translation starts a new entry.
+	next := func() {
+		if msgstr != nil {
+			flush()
+		}
+	}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		keyword, rest, _ := strings.Cut(text, " ")
+		switch {
+		case text == "":
+			flush()
+		case strings.HasPrefix(text, "#"):
+			next()
+			if strings.HasPrefix(text, "#,") && strings.Contains(text, "fuzzy") {
+				fuzzy = true
+			}
+		case strings.HasPrefix(text, `"`):
+			if current == nil {
+				return nil, fmt.Errorf("display: PO line %d: string outside an entry", line)
+			}
+			if err := appendPOString(current, text, line); err != nil {
+				return nil, err
+			}
+		default:
+			switch keyword {
+			case "msgctxt":
+				next()
+				current = &strings.Builder{}
+			case "msgid":
+				next()
+				msgid = &strings.Builder{}
+				current = msgid
+			case "msgstr", "msgstr[0]":
+				msgstr = &strings.Builder{}
+				current = msgstr
+			default:
+				if keyword != "msgid_plural" && !strings.HasPrefix(keyword, "msgstr[") {
+					return nil, fmt.Errorf("display: PO line %d: unexpected %q", line, keyword)
+				}
+				// Other plural forms are ignored.
+				current = &strings.Builder{}
+			}
+			if err := appendPOString(current, rest, line); err != nil {
+				return nil, err
+			}
+		}
+	}
+	flush()
+	return messages, scanner.Err()
+}
+
+// appendPOString appends a quoted PO string to b.
+func appendPOString(b *strings.Builder, quoted string, line int) error {
+	s, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return fmt.Errorf("display: PO line %d: %w", line, err)
+	}
+	b.WriteString(s)
+	return nil
+}
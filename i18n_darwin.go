@@ -0,0 +1,37 @@
+//go:build darwin && cgo
+
+package display
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation
+#import <Foundation/Foundation.h>
+#include <stdlib.h>
+#include <string.h>
+
+// preferredLanguage returns a malloc'd copy of the first language in the
+// user's list, or NULL if the list is empty.
+static char *preferredLanguage(void) {
+	@autoreleasepool {
+		NSString *language = [[NSLocale preferredLanguages] firstObject];
+		if (language == nil) {
+			return NULL;
+		}
+		return strdup(language.UTF8String);
+	}
+}
+*/
+import "C"
+
+import "unsafe"
+
+// systemLocale returns the first of the user's preferred languages from the
+// macOS settings, such as "en-GB", or "" if there is none.
+func systemLocale() string {
+	language := C.preferredLanguage()
+	if language == nil {
+		return ""
+	}
+	defer C.free(unsafe.Pointer(language))
+	return C.GoString(language)
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !(darwin && cgo)
+
+package display
+
+// systemLocale returns "": the locale comes from the environment.
+func systemLocale() string {
+	return ""
+}
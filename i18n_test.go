@@ -0,0 +1,165 @@
+package display
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestNormaliseLocale(t *testing.T) {
+	tests := map[string]string{
+		"":                "",
+		"C":               "en",
+		"POSIX":           "en",
+		"de_DE.UTF-8":     "de-DE",
+		"pt_br.utf8@euro": "pt-BR",
+		"fr":              "fr",
+		"EN-gb":           "en-GB",
+		"zh_hans_CN":      "zh-Hans-CN",
+		"sr-Latn-RS":      "sr-Latn-RS",
+	}
+	for in, want := range tests {
+		if got := NormaliseLocale(in); got != want {
+			t.Errorf("NormaliseLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "nl_NL.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	if got := DetectLocale(); got != "nl-NL" {
+		t.Errorf("DetectLocale() = %q, want nl-NL", got)
+	}
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	if got := DetectLocale(); got != DefaultLocale {
+		t.Errorf("DetectLocale() = %q, want %q", got, DefaultLocale)
+	}
+}
+
+func TestTranslateFallsBack(t *testing.T) {
+	s, _ := NewWithOptions(Options{Locale: "de_AT"})
+	s.AddCatalogue("de", map[string]string{"Quit": "Beenden", "Operating System: %s": "Betriebssystem: %s"})
+	s.AddCatalogue("de-AT", map[string]string{"Quit": "Schließen", "Workspace": ""})
+	tests := []struct{ message, want string }{
+		{"Quit", "Schließen"},
+		{"Operating System: %s", "Betriebssystem: linux"},
+		{"Workspace", "Workspace"},
+		{"Close Desktop", "Close Desktop"},
+	}
+	for _, tt := range tests {
+		if got := s.T(tt.message, "linux"); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("T(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestSetLocaleRelabelsMenus(t *testing.T) {
+	s, _ := NewWithOptions(Options{Locale: "en"})
+	s.AddCatalogue("fr", map[string]string{"Open Desktop": "Ouvrir le bureau", "Workspace": "Espace de travail"})
+	menu := application.NewMenu()
+	item := s.localise(menu.Add("Open Desktop"), "Open Desktop")
+	menu.AddSubmenu("Workspace")
+	s.localise(menu.ItemAt(1), "Workspace")
+	if item.Label() != "Open Desktop" {
+		t.Fatalf("label = %q", item.Label())
+	}
+	s.SetLocale("fr_FR.UTF-8")
+	if s.Locale() != "fr-FR" || item.Label() != "Ouvrir le bureau" || menu.ItemAt(1).Label() != "Espace de travail" {
+		t.Errorf("after SetLocale: locale %q, labels %q and %q", s.Locale(), item.Label(), menu.ItemAt(1).Label())
+	}
+	s.SetLocale("")
+	if s.Locale() != DefaultLocale || item.Label() != "Open Desktop" {
+		t.Errorf("after SetLocale(\"\"): locale %q, label %q", s.Locale(), item.Label())
+	}
+}
+
+func TestSetLocaleOnMainThread(t *testing.T) {
+	s, menu := newTestAppMenu(t)
+	s.AddCatalogue("fr", map[string]string{"New Workspace": "Nouvel espace", "Open Workspace": "Ouvrir un espace"})
+	s.mainThread = fakeMainThread(t)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 20 {
+			s.SetLocale("fr")
+			s.SetLocale("en")
+		}
+		s.SetLocale("fr")
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 20 {
+			label := "New Workspace"
+			if i%2 == 0 {
+				label = "Open Workspace"
+			}
+			s.Menu().SetLabel(MenuIDWorkspaceNew, label)
+		}
+	}()
+	wg.Wait()
+
+	// The last label set is "New Workspace", shown in the last locale.
+	if menu.FindByLabel("Nouvel espace") == nil {
+		t.Errorf("Workspace menu = %q, want \"Nouvel espace\" in it", labels(menu.FindByLabel("Workspace").GetSubmenu()))
+	}
+}
+
+func TestLoadCatalogues(t *testing.T) {
+	s, _ := NewWithOptions(Options{Locale: "es"})
+	err := s.LoadCatalogues(fstest.MapFS{
+		"es.json": {Data: []byte(`{"Quit": "Salir"}`)},
+		"pt_BR.po": {Data: []byte(`# Portuguese
+msgid ""
+msgstr ""
+"Language: pt_BR\n"
+
+#: tray.go:41
+msgid "Open Desktop"
+msgstr "Abrir "
+"área de trabalho"
+
+#, fuzzy
+msgid "Quit"
+msgstr "Sair?"
+
+msgctxt "menu"
+msgid "Close Desktop"
+msgid_plural "Close Desktops"
+msgstr[0] "Fechar área de trabalho"
+msgstr[1] "Fechar áreas de trabalho"
+`)},
+		"README.md": {Data: []byte("not a catalogue")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.T("Quit"); got != "Salir" {
+		t.Errorf("T(Quit) in es = %q", got)
+	}
+	s.SetLocale("pt-BR")
+	for message, want := range map[string]string{
+		"Open Desktop":  "Abrir área de trabalho",
+		"Quit":          "Quit",
+		"Close Desktop": "Fechar área de trabalho",
+	} {
+		if got := s.T(message); got != want {
+			t.Errorf("T(%q) in pt-BR = %q, want %q", message, got, want)
+		}
+	}
+}
+
+func TestParsePOErrors(t *testing.T) {
+	for _, po := range []string{"msgid \"unterminated\n", "\"orphan\"\n", "msgfoo \"x\"\n"} {
+		if _, err := ParsePO(strings.NewReader(po)); err == nil {
+			t.Errorf("ParsePO(%q) succeeded", po)
+		}
+	}
+}
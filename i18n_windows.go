@@ -0,0 +1,24 @@
+//go:build windows
+
+package display
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// localeNameMaxLength is LOCALE_NAME_MAX_LENGTH, in UTF-16 units.
+const localeNameMaxLength = 85
+
+var procGetUserDefaultLocaleName = kernel32.NewProc("GetUserDefaultLocaleName")
+
+// systemLocale returns the user's locale from the Windows settings, such as
+// "de-DE", or "" if it cannot be read.
+func systemLocale() string {
+	var name [localeNameMaxLength]uint16
+	if n, _, _ := procGetUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&name[0])), localeNameMaxLength); n == 0 {
+		return ""
+	}
+	return windows.UTF16ToString(name[:])
+}
@@ -0,0 +1,259 @@
+package display
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+var (
+	// ErrKioskPIN is returned when the kiosk unlock PIN is wrong, or kiosk
+	// mode is not active.
+	ErrKioskPIN = errors.New("display: incorrect kiosk PIN")
+	// ErrKioskLockedOut is returned when too many wrong PINs have been
+	// entered. No PIN is accepted until the lockout ends.
+	ErrKioskLockedOut = errors.New("display: too many incorrect kiosk PINs")
+)
+
+const (
+	// kioskUnlockWindow is the name of the admin PIN window.
+	kioskUnlockWindow = "kiosk-unlock"
+	// kioskUnlockAction is the shortcut action bound to the exit chord.
+	kioskUnlockAction = "kiosk.unlock"
+	// kioskMaxAttempts is the number of wrong PINs after which the unlock
+	// window is closed and PINs are locked out.
+	kioskMaxAttempts = 3
+	// kioskLockout is how long PINs are locked out the first time. It doubles
+	// with every further lockout, up to kioskMaxLockout.
+	kioskLockout    = 30 * time.Second
+	kioskMaxLockout = 15 * time.Minute
+)
+
+// KioskOptions configures kiosk mode, in which "main" fills the screen and
+// cannot be closed, minimised or navigated away from.
+//
+// example:
+//
+//	displayService, err := display.NewWithOptions(display.Options{
+//		Kiosk: &display.KioskOptions{
+//			AllowedURLs: []string{"/", "https://status.example.com/"},
+//			ExitChord:   "CmdOrCtrl+Alt+Shift+K",
+//			PIN:         os.Getenv("KIOSK_PIN"),
+//		},
+//	})
+type KioskOptions struct {
+	// AllowedURLs are the URLs windows may show, in the format used by
+	// `NavigationPolicy`. "/" is allowed if the list is empty. Everything else
+	// is blocked, including external links.
+	AllowedURLs []string
+
+	// ExitChord is the accelerator that opens the admin PIN window. It
+	// defaults to "CmdOrCtrl+Alt+Shift+K".
+	ExitChord string
+
+	// PIN unlocks kiosk mode. The escape hatch is disabled if it is empty.
+	PIN string
+
+	// UnlockURL is the page shown in the admin PIN window. It should call
+	// `Bridge.UnlockKiosk` with the entered PIN. It defaults to
+	// "kiosk-unlock.html", which the bundled frontend ships in ui/public.
+	UnlockURL string
+
+	// OnUnlock is called once the correct PIN has been entered. If it is nil,
+	// the application quits.
+	OnUnlock func()
+}
+
+// kioskState tracks whether kiosk mode is still locked.
+type kioskState struct {
+	options KioskOptions
+
+	mu          sync.Mutex
+	locked      bool
+	failures    int
+	lockouts    int
+	lockedUntil time.Time
+}
+
+// newKioskState creates the state for kiosk mode, filling in defaults.
+func newKioskState(options KioskOptions) *kioskState {
+	if len(options.AllowedURLs) == 0 {
+		options.AllowedURLs = []string{"/"}
+	}
+	if options.ExitChord == "" {
+		options.ExitChord = "CmdOrCtrl+Alt+Shift+K"
+	}
+	if options.UnlockURL == "" {
+		options.UnlockURL = "kiosk-unlock.html"
+	}
+	return &kioskState{options: options, locked: true}
+}
+
+// isLocked reports whether kiosk mode is active and still locked.
+func (k *kioskState) isLocked() bool {
+	if k == nil {
+		return false
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.locked
+}
+
+// attempt checks pin at time now and unlocks kiosk mode if it is correct. It
+// reports whether this attempt unlocked it. After kioskMaxAttempts wrong PINs
+// every attempt fails with ErrKioskLockedOut until the lockout ends.
+func (k *kioskState) attempt(pin string, now time.Time) (bool, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !k.locked {
+		return false, nil
+	}
+	if now.Before(k.lockedUntil) {
+		return false, fmt.Errorf("%w: try again in %s", ErrKioskLockedOut, k.lockedUntil.Sub(now).Round(time.Second))
+	}
+	if subtle.ConstantTimeCompare([]byte(pin), []byte(k.options.PIN)) != 1 {
+		k.failures++
+		if k.failures < kioskMaxAttempts {
+			return false, ErrKioskPIN
+		}
+		k.failures = 0
+		k.lockouts++
+		lockout := min(kioskLockout<<min(k.lockouts-1, 10), kioskMaxLockout)
+		k.lockedUntil = now.Add(lockout)
+		return false, fmt.Errorf("%w: try again in %s", ErrKioskLockedOut, lockout)
+	}
+	k.locked = false
+	k.failures = 0
+	k.lockouts = 0
+	return true, nil
+}
+
+// applyKiosk locks a window's configuration down for kiosk mode. The "main"
+// window is made fullscreen and frameless with its buttons hidden, and every
+// window is restricted to the allow list.
+func (s *Service) applyKiosk(config *WindowConfig, opts *application.WebviewWindowOptions) {
+	if !s.kiosk.isLocked() || config.Name == kioskUnlockWindow {
+		return
+	}
+	config.Navigation = &NavigationPolicy{Allowed: s.kiosk.options.AllowedURLs, Default: NavigationBlock}
+	if config.Name == "main" {
+		opts.Frameless = true
+		opts.StartState = application.WindowStateFullscreen
+		opts.MinimiseButtonState = application.ButtonHidden
+		opts.MaximiseButtonState = application.ButtonHidden
+		opts.CloseButtonState = application.ButtonHidden
+	}
+}
+
+// lockKioskWindow stops the kiosk window from being closed, minimised or
+// taken out of fullscreen while kiosk mode is locked.
+func (s *Service) lockKioskWindow(window application.Window) {
+	if !s.kiosk.isLocked() || window.Name() != "main" {
+		return
+	}
+	window.RegisterHook(events.Common.WindowClosing, func(event *application.WindowEvent) {
+		if s.kiosk.isLocked() {
+			event.Cancel()
+		}
+	})
+	window.OnWindowEvent(events.Common.WindowMinimise, func(*application.WindowEvent) {
+		if s.kiosk.isLocked() {
+			window.UnMinimise()
+		}
+	})
+	window.OnWindowEvent(events.Common.WindowUnFullscreen, func(*application.WindowEvent) {
+		if s.kiosk.isLocked() {
+			window.Fullscreen()
+		}
+	})
+}
+
+// installKioskEscape binds the admin exit chord, if a PIN is configured.
+func (s *Service) installKioskEscape() {
+	if s.kiosk == nil || s.kiosk.options.PIN == "" {
+		return
+	}
+	err := s.shortcuts.Register(Shortcut{
+		Action:      kioskUnlockAction,
+		Accelerator: s.kiosk.options.ExitChord,
+		Description: "Unlock kiosk mode",
+	}, func(application.Window) {
+		s.showKioskUnlock()
+	})
+	if err != nil {
+		s.app.Logger.Warn("Failed to bind kiosk exit chord", "error", err)
+	}
+}
+
+// showKioskUnlock opens the admin PIN window over "main".
+func (s *Service) showKioskUnlock() {
+	if !s.kiosk.isLocked() {
+		return
+	}
+	if window, ok := s.app.Window.GetByName(kioskUnlockWindow); ok {
+		window.Focus()
+		return
+	}
+	err := s.OpenWindow(
+		WithName(kioskUnlockWindow),
+		WithTitle(s.T("Administrator")),
+		WithURL(s.kiosk.options.UnlockURL),
+		WithWidth(360),
+		WithHeight(220),
+		WithAlwaysOnTop(true),
+		WithFrameless(true),
+		WithParent("main"),
+		WithModal(true),
+	)
+	if err != nil {
+		s.app.Logger.Error("Failed to open kiosk unlock window", "error", err)
+	}
+}
+
+// UnlockKiosk leaves kiosk mode if pin is the configured admin PIN. It is
+// called by the page shown in the admin PIN window. After too many wrong PINs
+// the window is closed and `ErrKioskLockedOut` is returned, even for the
+// right PIN, until the lockout ends. Each further lockout lasts twice as long.
+// The unlock page calls it through `Bridge.UnlockKiosk`.
+//
+// example:
+//
+//	err := displayService.UnlockKiosk(pin)
+func (s *Service) UnlockKiosk(pin string) error {
+	k := s.kiosk
+	if k == nil || k.options.PIN == "" {
+		return ErrKioskPIN
+	}
+	unlocked, err := k.attempt(pin, time.Now())
+	if err != nil {
+		s.app.Logger.Warn("Kiosk unlock failed", "error", err)
+		if errors.Is(err, ErrKioskLockedOut) {
+			s.closeWindow(kioskUnlockWindow)
+		}
+		return err
+	}
+	if !unlocked {
+		return nil
+	}
+
+	s.app.Logger.Info("Kiosk mode unlocked")
+	s.closeWindow(kioskUnlockWindow)
+	if k.options.OnUnlock != nil {
+		k.options.OnUnlock()
+		return nil
+	}
+	s.app.Quit()
+	return nil
+}
+
+// closeWindow closes a window by name if it is open.
+func (s *Service) closeWindow(name string) {
+	if window, ok := s.app.Window.GetByName(name); ok {
+		window.Close()
+	}
+}
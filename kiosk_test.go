@@ -0,0 +1,74 @@
+package display
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestApplyKiosk(t *testing.T) {
+	s, _ := NewWithOptions(Options{Kiosk: &KioskOptions{PIN: "2468"}})
+	if got := s.kiosk.options.ExitChord; got != "CmdOrCtrl+Alt+Shift+K" {
+		t.Errorf("default exit chord = %q", got)
+	}
+
+	config := buildWindowConfig()
+	opts := config.wailsOptions()
+	s.applyKiosk(config, &opts)
+	if !opts.Frameless || opts.StartState != application.WindowStateFullscreen ||
+		opts.CloseButtonState != application.ButtonHidden || opts.MinimiseButtonState != application.ButtonHidden {
+		t.Errorf("applyKiosk() did not lock down main: %+v", opts)
+	}
+	if config.Navigation == nil || config.Navigation.Decide("https://example.com/") != NavigationBlock {
+		t.Errorf("applyKiosk() navigation policy = %+v, want external links blocked", config.Navigation)
+	}
+
+	other := buildWindowConfig(WithName("help"), WithURL("/#/help"))
+	otherOpts := other.wailsOptions()
+	s.applyKiosk(other, &otherOpts)
+	if otherOpts.Frameless || otherOpts.StartState == application.WindowStateFullscreen {
+		t.Errorf("applyKiosk() changed a window other than main: %+v", otherOpts)
+	}
+}
+
+func TestUnlockKiosk_NotInKioskMode(t *testing.T) {
+	s, _ := New()
+	if err := s.UnlockKiosk("1234"); !errors.Is(err, ErrKioskPIN) {
+		t.Errorf("UnlockKiosk() error = %v, want ErrKioskPIN", err)
+	}
+}
+
+func TestKioskAttemptLockout(t *testing.T) {
+	k := newKioskState(KioskOptions{PIN: "2468"})
+	now := time.Now()
+	for i := 1; i < kioskMaxAttempts; i++ {
+		if _, err := k.attempt("0000", now); !errors.Is(err, ErrKioskPIN) {
+			t.Fatalf("attempt %d error = %v, want ErrKioskPIN", i, err)
+		}
+	}
+	if _, err := k.attempt("0000", now); !errors.Is(err, ErrKioskLockedOut) {
+		t.Fatalf("attempt %d error = %v, want ErrKioskLockedOut", kioskMaxAttempts, err)
+	}
+	if _, err := k.attempt("2468", now.Add(kioskLockout-time.Second)); !errors.Is(err, ErrKioskLockedOut) {
+		t.Errorf("right PIN during lockout error = %v, want ErrKioskLockedOut", err)
+	}
+
+	// The second lockout is twice as long.
+	now = now.Add(kioskLockout)
+	for range kioskMaxAttempts {
+		k.attempt("0000", now)
+	}
+	if _, err := k.attempt("2468", now.Add(kioskLockout)); !errors.Is(err, ErrKioskLockedOut) {
+		t.Errorf("right PIN during second lockout error = %v, want ErrKioskLockedOut", err)
+	}
+
+	unlocked, err := k.attempt("2468", now.Add(2*kioskLockout))
+	if !unlocked || err != nil {
+		t.Fatalf("right PIN after lockout = %v, %v", unlocked, err)
+	}
+	if unlocked, err := k.attempt("2468", now.Add(2*kioskLockout)); unlocked || err != nil {
+		t.Errorf("attempt after unlocking = %v, %v, want nothing to do", unlocked, err)
+	}
+}
@@ -0,0 +1,345 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+var (
+	// ErrUnknownLayout is returned for an unsupported layout or a saved layout
+	// that does not exist.
+	ErrUnknownLayout = errors.New("display: unknown layout")
+	// ErrInvalidSnap is returned when a window is snapped to an unsupported
+	// position.
+	ErrInvalidSnap = errors.New("display: invalid snap position")
+	// ErrUnknownScreen is returned when a screen ID does not match any
+	// connected screen.
+	ErrUnknownScreen = errors.New("display: unknown screen")
+)
+
+// Layout is an arrangement of windows within a screen's work area.
+type Layout string
+
+const (
+	// LayoutGrid arranges windows in a near-square grid. Windows in an
+	// incomplete last row share its full width.
+	LayoutGrid Layout = "grid"
+	// LayoutColumns arranges windows side by side at full height.
+	LayoutColumns Layout = "columns"
+	// LayoutMainStack gives the first window the left part of the screen and
+	// stacks the rest on the right.
+	LayoutMainStack Layout = "main-stack"
+	// LayoutCascade overlaps windows diagonally from the top-left corner.
+	LayoutCascade Layout = "cascade"
+)
+
+// Snap is a half or quarter of a screen's work area.
+type Snap string
+
+// Snap positions: the halves and quarters of the work area.
+const (
+	SnapLeft        Snap = "left"
+	SnapRight       Snap = "right"
+	SnapTop         Snap = "top"
+	SnapBottom      Snap = "bottom"
+	SnapTopLeft     Snap = "top-left"
+	SnapTopRight    Snap = "top-right"
+	SnapBottomLeft  Snap = "bottom-left"
+	SnapBottomRight Snap = "bottom-right"
+)
+
+const (
+	// mainStackRatio is the share of the width given to the main window in
+	// `LayoutMainStack`.
+	mainStackRatio = 0.6
+	// cascadeStep is the offset between windows in `LayoutCascade`.
+	cascadeStep = 32
+	// cascadeRatio is the size of each cascaded window relative to the work
+	// area.
+	cascadeRatio = 2.0 / 3.0
+)
+
+// layoutRects computes the bounds of count windows arranged by layout within
+// area. It only does the geometry, so it can be tested without a screen.
+func layoutRects(layout Layout, area application.Rect, count int) ([]application.Rect, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	rects := make([]application.Rect, 0, count)
+	switch layout {
+	case LayoutGrid:
+		cols := int(math.Ceil(math.Sqrt(float64(count))))
+		rows := (count + cols - 1) / cols
+		for i := 0; i < count; i++ {
+			row, col := i/cols, i%cols
+			inRow := cols
+			if row == rows-1 {
+				inRow = count - row*cols
+			}
+			rects = append(rects, cell(area, col, inRow, row, rows))
+		}
+	case LayoutColumns:
+		for i := 0; i < count; i++ {
+			rects = append(rects, cell(area, i, count, 0, 1))
+		}
+	case LayoutMainStack:
+		if count == 1 {
+			return append(rects, area), nil
+		}
+		mainWidth := int(float64(area.Width) * mainStackRatio)
+		rects = append(rects, application.Rect{X: area.X, Y: area.Y, Width: mainWidth, Height: area.Height})
+		stack := application.Rect{X: area.X + mainWidth, Y: area.Y, Width: area.Width - mainWidth, Height: area.Height}
+		for i := 0; i < count-1; i++ {
+			rects = append(rects, cell(stack, 0, 1, i, count-1))
+		}
+	case LayoutCascade:
+		width := int(float64(area.Width) * cascadeRatio)
+		height := int(float64(area.Height) * cascadeRatio)
+		steps := min(area.Width-width, area.Height-height)/cascadeStep + 1
+		for i := 0; i < count; i++ {
+			offset := (i % steps) * cascadeStep
+			rects = append(rects, application.Rect{X: area.X + offset, Y: area.Y + offset, Width: width, Height: height})
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLayout, layout)
+	}
+	return rects, nil
+}
+
+// cell returns cell (col, row) of area divided into cols x rows. Rounding is
+// spread across the cells so that they tile the area exactly.
+func cell(area application.Rect, col, cols, row, rows int) application.Rect {
+	x0, x1 := area.X+area.Width*col/cols, area.X+area.Width*(col+1)/cols
+	y0, y1 := area.Y+area.Height*row/rows, area.Y+area.Height*(row+1)/rows
+	return application.Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// snapRect returns the part of area that a window snapped to snap occupies.
+func snapRect(area application.Rect, snap Snap) (application.Rect, error) {
+	switch snap {
+	case SnapLeft:
+		return cell(area, 0, 2, 0, 1), nil
+	case SnapRight:
+		return cell(area, 1, 2, 0, 1), nil
+	case SnapTop:
+		return cell(area, 0, 1, 0, 2), nil
+	case SnapBottom:
+		return cell(area, 0, 1, 1, 2), nil
+	case SnapTopLeft:
+		return cell(area, 0, 2, 0, 2), nil
+	case SnapTopRight:
+		return cell(area, 1, 2, 0, 2), nil
+	case SnapBottomLeft:
+		return cell(area, 0, 2, 1, 2), nil
+	case SnapBottomRight:
+		return cell(area, 1, 2, 1, 2), nil
+	}
+	return application.Rect{}, fmt.Errorf("%w: %q", ErrInvalidSnap, snap)
+}
+
+// findScreen returns the screen with the given ID. An empty ID or "primary"
+// selects the primary screen.
+func findScreen(screens []*application.Screen, id string) (*application.Screen, error) {
+	for _, screen := range screens {
+		if screen == nil {
+			continue
+		}
+		if screen.ID == id || ((id == "" || id == "primary") && screen.IsPrimary) {
+			return screen, nil
+		}
+	}
+	if (id == "" || id == "primary") && len(screens) > 0 && screens[0] != nil {
+		return screens[0], nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownScreen, id)
+}
+
+// SavedLayout records the bounds of a set of windows so they can be put back
+// later with `RestoreLayout`.
+type SavedLayout struct {
+	Windows map[string]application.Rect `json:"windows"`
+}
+
+// ArrangeWindows arranges the named windows within the work area of a screen.
+// The screen is given by ID, or "primary"/"" for the primary screen. If no
+// windows are named, every top-level window opened by the service is
+// arranged, in the order they were opened.
+//
+// example:
+//
+//	err := displayService.ArrangeWindows(display.LayoutMainStack, "primary",
+//		"main", "inspector", "logs")
+func (s *Service) ArrangeWindows(layout Layout, screenID string, names ...string) error {
+	screen, err := findScreen(s.app.Screen.GetAll(), screenID)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		for _, node := range s.windows.tree() {
+			names = append(names, node.Name)
+		}
+	}
+	windows, err := s.windowsByName(names)
+	if err != nil {
+		return err
+	}
+	rects, err := layoutRects(layout, screen.WorkArea, len(windows))
+	if err != nil {
+		return err
+	}
+	for i, window := range windows {
+		setWindowBounds(window, rects[i])
+	}
+	return nil
+}
+
+// SnapWindow snaps a window to half or a quarter of the screen it is on.
+//
+// example:
+//
+//	err := displayService.SnapWindow("main", display.SnapLeft)
+func (s *Service) SnapWindow(name string, snap Snap) error {
+	window, ok := s.app.Window.GetByName(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownWindow, name)
+	}
+	screen, err := window.GetScreen()
+	if err != nil || screen == nil {
+		if screen, err = findScreen(s.app.Screen.GetAll(), "primary"); err != nil {
+			return err
+		}
+	}
+	rect, err := snapRect(screen.WorkArea, snap)
+	if err != nil {
+		return err
+	}
+	setWindowBounds(window, rect)
+	return nil
+}
+
+// SaveLayout saves the current bounds of the named windows, or of every
+// window opened by the service if none are named, under name. Saved layouts
+// are persisted to `Options.LayoutsPath`.
+//
+// example:
+//
+//	err := displayService.SaveLayout("review", "main", "diff")
+func (s *Service) SaveLayout(name string, names ...string) error {
+	if len(names) == 0 {
+		names = s.windows.names()
+	}
+	windows, err := s.windowsByName(names)
+	if err != nil {
+		return err
+	}
+	layout := SavedLayout{Windows: make(map[string]application.Rect, len(windows))}
+	for _, window := range windows {
+		layout.Windows[window.Name()] = window.Bounds()
+	}
+
+	s.layoutsMu.Lock()
+	defer s.layoutsMu.Unlock()
+	s.layouts[name] = layout
+	return s.saveLayoutsLocked()
+}
+
+// RestoreLayout moves the windows in a saved layout back to their saved
+// bounds. Windows that are no longer open are skipped.
+//
+// example:
+//
+//	err := displayService.RestoreLayout("review")
+func (s *Service) RestoreLayout(name string) error {
+	s.layoutsMu.Lock()
+	layout, ok := s.layouts[name]
+	s.layoutsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownLayout, name)
+	}
+	for windowName, bounds := range layout.Windows {
+		if window, ok := s.app.Window.GetByName(windowName); ok {
+			setWindowBounds(window, bounds)
+		}
+	}
+	return nil
+}
+
+// DeleteLayout removes a saved layout.
+func (s *Service) DeleteLayout(name string) error {
+	s.layoutsMu.Lock()
+	defer s.layoutsMu.Unlock()
+	if _, ok := s.layouts[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownLayout, name)
+	}
+	delete(s.layouts, name)
+	return s.saveLayoutsLocked()
+}
+
+// SavedLayouts returns the names of the saved layouts in sorted order.
+func (s *Service) SavedLayouts() []string {
+	s.layoutsMu.Lock()
+	defer s.layoutsMu.Unlock()
+	names := make([]string, 0, len(s.layouts))
+	for name := range s.layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// windowsByName looks up open windows, failing if any of them is not open.
+func (s *Service) windowsByName(names []string) ([]application.Window, error) {
+	windows := make([]application.Window, 0, len(names))
+	for _, name := range names {
+		window, ok := s.app.Window.GetByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownWindow, name)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// setWindowBounds restores a maximised or fullscreen window before moving it,
+// as neither state can be resized.
+func setWindowBounds(window application.Window, bounds application.Rect) {
+	if window.IsFullscreen() {
+		window.UnFullscreen()
+	}
+	if window.IsMaximised() {
+		window.UnMaximise()
+	}
+	window.SetBounds(bounds)
+}
+
+// layoutsPath returns the saved layouts file configured in Options, falling
+// back to the user's config directory.
+func (s *Service) layoutsPath() string {
+	return statePath(s.config.LayoutsPath, "layouts.json")
+}
+
+// loadLayouts reads the saved layouts file. A missing file is not an error.
+func (s *Service) loadLayouts() error {
+	layouts := map[string]SavedLayout{}
+	if err := readStateFile(s.layoutsPath(), "layouts", &layouts); err != nil {
+		return err
+	}
+	if layouts == nil {
+		// The file held JSON null.
+		layouts = map[string]SavedLayout{}
+	}
+	s.layoutsMu.Lock()
+	defer s.layoutsMu.Unlock()
+	s.layouts = layouts
+	return nil
+}
+
+// saveLayoutsLocked writes the saved layouts file. The caller must hold
+// s.layoutsMu.
+func (s *Service) saveLayoutsLocked() error {
+	return writeStateFile(s.layoutsPath(), "layouts", s.layouts)
+}
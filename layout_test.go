@@ -0,0 +1,151 @@
+package display
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestLayoutRects(t *testing.T) {
+	// A 1920x1080 screen with a 40px panel along the top.
+	area := application.Rect{X: 0, Y: 40, Width: 1920, Height: 1040}
+	r := func(x, y, w, h int) application.Rect { return application.Rect{X: x, Y: y, Width: w, Height: h} }
+
+	tests := []struct {
+		name   string
+		layout Layout
+		area   application.Rect
+		count  int
+		want   []application.Rect
+	}{
+		{name: "Grid of one", layout: LayoutGrid, area: area, count: 1, want: []application.Rect{area}},
+		{
+			name: "Grid of four", layout: LayoutGrid, area: area, count: 4,
+			want: []application.Rect{r(0, 40, 960, 520), r(960, 40, 960, 520), r(0, 560, 960, 520), r(960, 560, 960, 520)},
+		},
+		{
+			name: "Grid with a short last row", layout: LayoutGrid, area: area, count: 3,
+			want: []application.Rect{r(0, 40, 960, 520), r(960, 40, 960, 520), r(0, 560, 1920, 520)},
+		},
+		{
+			name: "Columns spread rounding", layout: LayoutColumns, area: r(0, 0, 1000, 600), count: 3,
+			want: []application.Rect{r(0, 0, 333, 600), r(333, 0, 333, 600), r(666, 0, 334, 600)},
+		},
+		{
+			name: "Main and stack", layout: LayoutMainStack, area: area, count: 3,
+			want: []application.Rect{r(0, 40, 1152, 1040), r(1152, 40, 768, 520), r(1152, 560, 768, 520)},
+		},
+		{name: "Main alone", layout: LayoutMainStack, area: area, count: 1, want: []application.Rect{area}},
+		{
+			name: "Cascade", layout: LayoutCascade, area: r(100, 0, 300, 300), count: 3,
+			want: []application.Rect{r(100, 0, 200, 200), r(132, 32, 200, 200), r(164, 64, 200, 200)},
+		},
+		{
+			name: "Cascade wraps", layout: LayoutCascade, area: r(0, 0, 150, 150), count: 3,
+			want: []application.Rect{r(0, 0, 100, 100), r(32, 32, 100, 100), r(0, 0, 100, 100)},
+		},
+		{name: "No windows", layout: LayoutGrid, area: area, count: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := layoutRects(tt.layout, tt.area, tt.count)
+			if err != nil {
+				t.Fatalf("layoutRects() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("layoutRects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := layoutRects("spiral", area, 2); !errors.Is(err, ErrUnknownLayout) {
+		t.Errorf("layoutRects() with an unknown layout error = %v, want ErrUnknownLayout", err)
+	}
+}
+
+func TestSnapRect(t *testing.T) {
+	area := application.Rect{X: 1920, Y: 0, Width: 1280, Height: 1024}
+	tests := map[Snap]application.Rect{
+		SnapLeft:        {X: 1920, Y: 0, Width: 640, Height: 1024},
+		SnapRight:       {X: 2560, Y: 0, Width: 640, Height: 1024},
+		SnapTop:         {X: 1920, Y: 0, Width: 1280, Height: 512},
+		SnapBottom:      {X: 1920, Y: 512, Width: 1280, Height: 512},
+		SnapTopLeft:     {X: 1920, Y: 0, Width: 640, Height: 512},
+		SnapTopRight:    {X: 2560, Y: 0, Width: 640, Height: 512},
+		SnapBottomLeft:  {X: 1920, Y: 512, Width: 640, Height: 512},
+		SnapBottomRight: {X: 2560, Y: 512, Width: 640, Height: 512},
+	}
+	for snap, want := range tests {
+		if got, err := snapRect(area, snap); err != nil || got != want {
+			t.Errorf("snapRect(%s) = %v, %v, want %v", snap, got, err, want)
+		}
+	}
+	if _, err := snapRect(area, "centre"); !errors.Is(err, ErrInvalidSnap) {
+		t.Errorf("snapRect() with an unknown position error = %v, want ErrInvalidSnap", err)
+	}
+}
+
+func TestFindScreen(t *testing.T) {
+	left := &application.Screen{ID: "1"}
+	right := &application.Screen{ID: "2", IsPrimary: true}
+	screens := []*application.Screen{left, right}
+
+	for id, want := range map[string]*application.Screen{"": right, "primary": right, "1": left} {
+		if got, err := findScreen(screens, id); err != nil || got != want {
+			t.Errorf("findScreen(%q) = %v, %v, want screen %s", id, got, err, want.ID)
+		}
+	}
+	if _, err := findScreen(screens, "3"); !errors.Is(err, ErrUnknownScreen) {
+		t.Errorf("findScreen() with an unknown ID error = %v, want ErrUnknownScreen", err)
+	}
+	if got, err := findScreen([]*application.Screen{left}, "primary"); err != nil || got != left {
+		t.Errorf("findScreen() without a primary screen = %v, %v, want the first screen", got, err)
+	}
+}
+
+func TestSavedLayoutsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layouts.json")
+	s, _ := NewWithOptions(Options{LayoutsPath: path})
+	s.layouts["review"] = SavedLayout{Windows: map[string]application.Rect{
+		"main": {X: 0, Y: 0, Width: 960, Height: 1080},
+		"diff": {X: 960, Y: 0, Width: 960, Height: 1080},
+	}}
+	if err := s.saveLayoutsLocked(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, _ := NewWithOptions(Options{LayoutsPath: path})
+	if err := reloaded.loadLayouts(); err != nil {
+		t.Fatalf("loadLayouts() error = %v", err)
+	}
+	if !reflect.DeepEqual(reloaded.layouts, s.layouts) {
+		t.Errorf("loadLayouts() = %+v, want %+v", reloaded.layouts, s.layouts)
+	}
+	if got := reloaded.SavedLayouts(); !reflect.DeepEqual(got, []string{"review"}) {
+		t.Errorf("SavedLayouts() = %v", got)
+	}
+	if err := reloaded.DeleteLayout("missing"); !errors.Is(err, ErrUnknownLayout) {
+		t.Errorf("DeleteLayout() of a missing layout error = %v, want ErrUnknownLayout", err)
+	}
+}
+
+func TestLoadLayoutsNull(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layouts.json")
+	if err := os.WriteFile(path, []byte("null"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := NewWithOptions(Options{LayoutsPath: path})
+	if err := s.loadLayouts(); err != nil {
+		t.Fatal(err)
+	}
+	s.layoutsMu.Lock()
+	defer s.layoutsMu.Unlock()
+	if s.layouts == nil {
+		t.Fatal("loadLayouts() of null left a nil map")
+	}
+	s.layouts["review"] = SavedLayout{}
+}
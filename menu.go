@@ -1,33 +1,31 @@
 package display
 
 import (
-	"runtime"
-
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 // buildMenu creates and sets the main application menu. This function is called
-// during the startup of the display service.
+// during the startup of the display service. The menu's items come from
+// `Service.Menu`, which can change them while the app runs.
 func (s *Service) buildMenu() {
 	appMenu := s.app.Menu.New()
-	if runtime.GOOS == "darwin" {
-		appMenu.AddRole(application.AppMenu)
-	}
-	appMenu.AddRole(application.FileMenu)
-	appMenu.AddRole(application.ViewMenu)
-	appMenu.AddRole(application.EditMenu)
-
-	workspace := appMenu.AddSubmenu("Workspace")
-	workspace.Add("New").OnClick(func(ctx *application.Context) { /* TODO */ })
-	workspace.Add("List").OnClick(func(ctx *application.Context) { /* TODO */ })
-
-	// Add brand-specific menu items
-	//if s.brand == DeveloperHub {
-	//	appMenu.AddSubmenu("Developer")
-	//}
-
-	appMenu.AddRole(application.WindowMenu)
-	appMenu.AddRole(application.HelpMenu)
-
+	s.appMenu.attach(appMenu)
 	s.app.Menu.Set(appMenu)
 }
+
+// addMenuAction adds a menu item that triggers a named shortcut action. The
+// item shows the accelerator currently bound to the action and is kept up to
+// date when the action is rebound.
+func (s *Service) addMenuAction(menu *application.Menu, label, action string) *application.MenuItem {
+	s.shortcuts.ensure(action)
+	item := s.localise(menu.Add(label), label).OnClick(func(ctx *application.Context) {
+		s.runShortcutAction(action, s.app.Window.Current())
+	})
+	if accel := s.shortcuts.accelerator(action); accel != "" {
+		item.SetAccelerator(accel)
+	}
+	s.menuItemsMu.Lock()
+	s.menuItems[action] = append(s.menuItems[action], item)
+	s.menuItemsMu.Unlock()
+	return item
+}
@@ -0,0 +1,306 @@
+package display
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+var (
+	// ErrActionPanic is returned when an action handler panics and the panic
+	// is recovered by `RecoverActions`.
+	ErrActionPanic = errors.New("display: action panicked")
+	// ErrRateLimited is returned when a window invokes actions faster than
+	// `RateLimitActions` allows.
+	ErrRateLimited = errors.New("display: too many actions")
+)
+
+// ActionMiddleware wraps action handling. It receives the next handler in
+// the chain and returns a handler that calls it, or returns early.
+//
+// example:
+//
+//	func requireUnlocked(next display.ActionHandler) display.ActionHandler {
+//		return func(ctx context.Context, call display.ActionCall) (any, error) {
+//			if vault.Locked() {
+//				return nil, errVaultLocked
+//			}
+//			return next(ctx, call)
+//		}
+//	}
+type ActionMiddleware func(next ActionHandler) ActionHandler
+
+// chainMiddleware wraps handler in middleware, with the first middleware
+// outermost.
+func chainMiddleware(handler ActionHandler, middleware []ActionMiddleware) ActionHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// LogActions logs every action with its caller, duration and error. A nil
+// logger logs to the Wails `app.Logger`.
+//
+// example:
+//
+//	displayService, err := display.NewWithOptions(display.Options{
+//		Middleware: []display.ActionMiddleware{display.LogActions(nil)},
+//	})
+func LogActions(logger *slog.Logger) ActionMiddleware {
+	return func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, call ActionCall) (any, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			log := logger
+			if log == nil {
+				log = application.Get().Logger
+			}
+			attrs := []any{"window", call.Window, "action", call.Action, "duration", time.Since(start)}
+			if err != nil {
+				log.Warn("Action failed", append(attrs, "error", err)...)
+			} else {
+				log.Info("Action handled", attrs...)
+			}
+			return result, err
+		}
+	}
+}
+
+// RecoverActions turns a panicking action handler into an `ErrActionPanic`
+// error, so that one bad handler cannot take the application down. It should
+// be the first middleware.
+func RecoverActions() ActionMiddleware {
+	return func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, call ActionCall) (result any, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					result = nil
+					err = fmt.Errorf("%w: %s: %v", ErrActionPanic, call.Action, r)
+				}
+			}()
+			return next(ctx, call)
+		}
+	}
+}
+
+// defaultLatencyBuckets are the upper bounds used by `NewActionMetrics` when
+// none are given.
+var defaultLatencyBuckets = []time.Duration{
+	time.Millisecond, 5 * time.Millisecond, 25 * time.Millisecond, 100 * time.Millisecond,
+	500 * time.Millisecond, time.Second, 5 * time.Second,
+}
+
+// LatencyHistogram counts action calls by how long they took. Counts[i] is
+// the number of calls that took at most Buckets[i]; the final count holds the
+// slower calls.
+type LatencyHistogram struct {
+	Buckets []time.Duration `json:"buckets"`
+	Counts  []uint64        `json:"counts"`
+	Count   uint64          `json:"count"`
+	Errors  uint64          `json:"errors"`
+	Sum     time.Duration   `json:"sum"`
+}
+
+// observe records one call.
+func (h *LatencyHistogram) observe(d time.Duration, failed bool) {
+	i := sort.Search(len(h.Buckets), func(i int) bool { return d <= h.Buckets[i] })
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+	if failed {
+		h.Errors++
+	}
+}
+
+// ActionMetrics keeps a latency histogram per action.
+//
+// example:
+//
+//	metrics := display.NewActionMetrics()
+//	displayService, err := display.NewWithOptions(display.Options{
+//		Middleware: []display.ActionMiddleware{metrics.Middleware()},
+//	})
+//	// Later:
+//	for action, h := range metrics.Snapshot() {
+//		log.Printf("%s: %d calls, %s total", action, h.Count, h.Sum)
+//	}
+type ActionMetrics struct {
+	buckets []time.Duration
+
+	mu         sync.Mutex
+	histograms map[string]*LatencyHistogram
+}
+
+// NewActionMetrics creates action metrics with the given histogram bucket
+// upper bounds, or a default set from 1ms to 5s.
+func NewActionMetrics(buckets ...time.Duration) *ActionMetrics {
+	if len(buckets) == 0 {
+		buckets = defaultLatencyBuckets
+	}
+	buckets = append([]time.Duration{}, buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	return &ActionMetrics{buckets: buckets, histograms: map[string]*LatencyHistogram{}}
+}
+
+// Middleware returns the middleware that records action latencies.
+func (m *ActionMetrics) Middleware() ActionMiddleware {
+	return func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, call ActionCall) (any, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			m.observe(call.Action, time.Since(start), err != nil)
+			return result, err
+		}
+	}
+}
+
+// observe records one call of an action.
+func (m *ActionMetrics) observe(action string, d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.histograms[action]
+	if !ok {
+		h = &LatencyHistogram{Buckets: m.buckets, Counts: make([]uint64, len(m.buckets)+1)}
+		m.histograms[action] = h
+	}
+	h.observe(d, failed)
+}
+
+// Snapshot returns a copy of the histograms, keyed by action.
+func (m *ActionMetrics) Snapshot() map[string]LatencyHistogram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]LatencyHistogram, len(m.histograms))
+	for action, h := range m.histograms {
+		copied := *h
+		copied.Counts = append([]uint64{}, h.Counts...)
+		snapshot[action] = copied
+	}
+	return snapshot
+}
+
+// rateLimiter is a token bucket per window.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	// swept is when idle buckets were last removed.
+	swept time.Time
+}
+
+// tokenBucket is the remaining allowance of one window.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the window's bucket, if there is one.
+func (l *rateLimiter) allow(window string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+	b, ok := l.buckets[window]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[window] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep removes the buckets of windows that have been idle long enough to
+// refill, which would start over full anyway, so that closed windows and
+// one-off callers do not accumulate. It runs at most once per refill period.
+func (l *rateLimiter) sweep(now time.Time) {
+	if l.rate <= 0 {
+		return
+	}
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.swept) < refill {
+		return
+	}
+	l.swept = now
+	for window, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, window)
+		}
+	}
+}
+
+// RateLimitActions limits each caller to perSecond actions on average, with
+// bursts of up to burst actions. Calls over the limit fail with
+// `ErrRateLimited`.
+//
+// example:
+//
+//	display.RateLimitActions(20, 50)
+func RateLimitActions(perSecond float64, burst int) ActionMiddleware {
+	limiter := &rateLimiter{
+		rate:    perSecond,
+		burst:   float64(max(burst, 1)),
+		now:     time.Now,
+		buckets: map[string]*tokenBucket{},
+	}
+	return func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, call ActionCall) (any, error) {
+			if !limiter.allow(call.caller()) {
+				return nil, fmt.Errorf("%w: %s", ErrRateLimited, call.Window)
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// TraceActions writes every call and its outcome, including payloads and
+// results, to w, or to standard error if w is nil. Clipboard contents are
+// redacted. It is meant for debugging and should not be enabled in
+// production builds.
+//
+// example:
+//
+//	display.TraceActions(os.Stderr)
+func TraceActions(w io.Writer) ActionMiddleware {
+	if w == nil {
+		w = os.Stderr
+	}
+	var mu sync.Mutex
+	trace := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, format, args...)
+	}
+	return func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, call ActionCall) (any, error) {
+			payload, _ := json.Marshal(redactPayload(call.Action, call.Payload))
+			trace("-> %s %s [%s] %s\n", call.Window, call.Action, call.ID, payload)
+			start := time.Now()
+			result, err := next(ctx, call)
+			if err != nil {
+				trace("<- %s %s [%s] %s error: %v\n", call.Window, call.Action, call.ID, time.Since(start), err)
+			} else {
+				out, _ := json.Marshal(redactResult(call.Action, result))
+				trace("<- %s %s [%s] %s %s\n", call.Window, call.Action, call.ID, time.Since(start), out)
+			}
+			return result, err
+		}
+	}
+}
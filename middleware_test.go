@@ -0,0 +1,129 @@
+package display
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) ActionMiddleware {
+		return func(next ActionHandler) ActionHandler {
+			return func(ctx context.Context, call ActionCall) (any, error) {
+				order = append(order, name)
+				return next(ctx, call)
+			}
+		}
+	}
+	s, _ := NewWithOptions(Options{Middleware: []ActionMiddleware{mark("first"), mark("second")}})
+	s.RegisterAction("test.noop", "", func(context.Context, ActionCall) (any, error) {
+		order = append(order, "handler")
+		return nil, nil
+	})
+	if _, err := s.Dispatch(context.Background(), "main", "test.noop", nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first", "second", "handler"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestRecoverActions(t *testing.T) {
+	s, _ := NewWithOptions(Options{Middleware: []ActionMiddleware{RecoverActions()}})
+	s.RegisterAction("test.panic", "", func(context.Context, ActionCall) (any, error) {
+		panic("boom")
+	})
+	reply := s.Call(context.Background(), "main", ActionRequest{ID: "1", Action: "test.panic"})
+	if reply.Error == nil || !strings.Contains(reply.Error.Message, "boom") {
+		t.Errorf("Call() = %+v, want the panic as an error", reply)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := &rateLimiter{rate: 2, burst: 2, now: func() time.Time { return now }, buckets: map[string]*tokenBucket{}}
+	for i, want := range []bool{true, true, false} {
+		if got := limiter.allow("main"); got != want {
+			t.Errorf("allow() call %d = %v, want %v", i, got, want)
+		}
+	}
+	if !limiter.allow("inspector") {
+		t.Error("allow() for another window = false, want its own bucket")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if !limiter.allow("main") || limiter.allow("main") {
+		t.Error("allow() after refilling one token did not allow exactly one call")
+	}
+
+	// Buckets idle for long enough to refill are dropped.
+	now = now.Add(time.Second)
+	limiter.allow("main")
+	if _, ok := limiter.buckets["inspector"]; ok || len(limiter.buckets) != 1 {
+		t.Errorf("buckets after an idle second = %d, want only main's", len(limiter.buckets))
+	}
+}
+
+func TestRateLimitActionsError(t *testing.T) {
+	s, _ := NewWithOptions(Options{Middleware: []ActionMiddleware{RateLimitActions(1, 1)}})
+	s.RegisterAction("test.noop", "", func(context.Context, ActionCall) (any, error) { return nil, nil })
+	ctx := context.Background()
+	_, _ = s.Dispatch(ctx, "main", "test.noop", nil)
+	if _, err := s.Dispatch(ctx, "main", "test.noop", nil); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Dispatch() error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestActionMetrics(t *testing.T) {
+	metrics := NewActionMetrics(10*time.Millisecond, time.Millisecond)
+	metrics.observe("notify", 500*time.Microsecond, false)
+	metrics.observe("notify", 5*time.Millisecond, true)
+	metrics.observe("notify", time.Second, false)
+	h := metrics.Snapshot()["notify"]
+	if want := []uint64{1, 1, 1}; !reflect.DeepEqual(h.Counts, want) {
+		t.Errorf("Counts = %v, want %v", h.Counts, want)
+	}
+	if h.Count != 3 || h.Errors != 1 || h.Buckets[0] != time.Millisecond {
+		t.Errorf("histogram = %+v", h)
+	}
+}
+
+func TestTraceAndLogActions(t *testing.T) {
+	var trace, logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	s, _ := NewWithOptions(Options{Middleware: []ActionMiddleware{LogActions(logger), TraceActions(&trace)}})
+	s.RegisterAction("test.echo", "", func(_ context.Context, call ActionCall) (any, error) {
+		return call.Payload["text"], nil
+	})
+	s.Call(context.Background(), "main", ActionRequest{ID: "9", Action: "test.echo", Payload: map[string]any{"text": "hi"}})
+	if got := trace.String(); !strings.Contains(got, `-> main test.echo [9] {"text":"hi"}`) || !strings.Contains(got, `"hi"`) {
+		t.Errorf("trace = %q", got)
+	}
+	if got := logs.String(); !strings.Contains(got, "action=test.echo") || !strings.Contains(got, "window=main") {
+		t.Errorf("log = %q", got)
+	}
+}
+
+func TestTraceRedactsClipboard(t *testing.T) {
+	var trace, recording bytes.Buffer
+	s, _ := NewWithOptions(Options{Middleware: []ActionMiddleware{TraceActions(&trace)}})
+	s.StartRecording(&recording)
+	payload := map[string]any{"text": "seed words", "clearAfterMs": 20.0}
+	s.Call(context.Background(), "main", ActionRequest{Action: ActionNameClipboardWrite, Payload: payload})
+	if err := s.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	for name, got := range map[string]string{"trace": trace.String(), "recording": recording.String()} {
+		if strings.Contains(got, "seed words") || !strings.Contains(got, redacted) {
+			t.Errorf("%s = %q, want the clipboard text redacted", name, got)
+		}
+	}
+	if payload["text"] != "seed words" {
+		t.Error("redacting changed the caller's payload")
+	}
+}
@@ -0,0 +1,416 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// ErrNavigationBlocked is returned when a window is pointed at a URL that its
+// navigation policy does not allow.
+var ErrNavigationBlocked = errors.New("display: navigation blocked")
+
+// NavigationAction is what happens when a window navigates to a URL.
+type NavigationAction string
+
+const (
+	// NavigationAllow lets the window navigate.
+	NavigationAllow NavigationAction = "allow"
+	// NavigationExternal opens the URL in the system browser instead.
+	NavigationExternal NavigationAction = "external"
+	// NavigationBlock stops the navigation.
+	NavigationBlock NavigationAction = "block"
+	// NavigationPrompt asks the user whether to open the URL in the system
+	// browser.
+	NavigationPrompt NavigationAction = "prompt"
+)
+
+// NavigationPolicy restricts the URLs a window may show. URL entries starting
+// with "/" match app-local URLs by path prefix; other entries match absolute
+// URLs by scheme, host and path prefix, so "https://app.example.com/dapp"
+// allows "https://app.example.com/dapp/swap" but not
+// "https://app.example.com/dapps".
+//
+// example:
+//
+//	err := displayService.OpenWindow(
+//		display.WithName("dapp"),
+//		display.WithURL("https://app.uniswap.org/"),
+//		display.WithNavigationPolicy(display.NavigationPolicy{
+//			Allowed:  []string{"https://app.uniswap.org/"},
+//			External: []string{"https://docs.uniswap.org/"},
+//			Default:  display.NavigationPrompt,
+//		}),
+//	)
+type NavigationPolicy struct {
+	// Allowed are the URLs the window may navigate to.
+	Allowed []string `json:"allowed,omitempty"`
+	// External are URLs that are opened in the system browser.
+	External []string `json:"external,omitempty"`
+	// Default applies to every other URL: NavigationBlock (the default),
+	// NavigationPrompt or NavigationExternal.
+	Default NavigationAction `json:"default,omitempty"`
+}
+
+// Decide returns what should happen when a window with this policy navigates
+// to raw. Only web and mail links are ever handed to the system browser.
+func (p *NavigationPolicy) Decide(raw string) NavigationAction {
+	if urlAllowed(p.Allowed, raw) {
+		return NavigationAllow
+	}
+	if !externalURL(raw) {
+		return NavigationBlock
+	}
+	if urlAllowed(p.External, raw) {
+		return NavigationExternal
+	}
+	switch p.Default {
+	case NavigationExternal, NavigationPrompt:
+		return p.Default
+	}
+	return NavigationBlock
+}
+
+// WithNavigationPolicy restricts where the window may navigate. Links the
+// page follows go through `Service.Navigate`, and pages the policy does not
+// allow are put back; see `Service.HandleRawMessage`.
+func WithNavigationPolicy(policy NavigationPolicy) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.Navigation = &policy
+	})
+}
+
+// urlAllowed reports whether raw matches one of the allowed URL prefixes.
+func urlAllowed(allowed []string, raw string) bool {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	local := target.Scheme == "" && target.Host == ""
+	for _, entry := range allowed {
+		if strings.HasPrefix(entry, "/") {
+			if local && strings.HasPrefix("/"+strings.TrimPrefix(raw, "/"), entry) {
+				return true
+			}
+			continue
+		}
+		prefix, err := url.Parse(entry)
+		if err != nil || local {
+			continue
+		}
+		if strings.EqualFold(prefix.Scheme, target.Scheme) &&
+			strings.EqualFold(prefix.Host, target.Host) &&
+			pathHasPrefix(target.EscapedPath(), prefix.EscapedPath()) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathHasPrefix reports whether path is prefix or lies beneath it.
+func pathHasPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// externalURL reports whether raw can be handed to the system browser.
+func externalURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return true
+	}
+	return false
+}
+
+// navigationLocationMessage prefixes the raw message a page sends with its
+// URL once it has loaded. See `Service.HandleRawMessage`.
+const navigationLocationMessage = "display:location:"
+
+// navigationBlank is shown when a window has no allowed page to go back to.
+const navigationBlank = "about:blank"
+
+// appOrigins are the origins the Wails asset server serves the app from.
+var appOrigins = []string{"wails://localhost", "http://wails.localhost"}
+
+// navigationState is what the service knows about the page shown in a window
+// with a navigation policy. The platforms do not report the URL of a loaded
+// page, so pages report their own `location.href` after every load.
+type navigationState struct {
+	policy *NavigationPolicy
+	// page is the URL the window last reported.
+	page string
+	// allowed is the last reported URL the policy allows.
+	allowed string
+}
+
+// visited is called with the URL a window reports once it has loaded a page.
+// If the policy does not allow it, visited returns the page to put back.
+func (n *navigationState) visited(raw string) (string, bool) {
+	if raw == n.page {
+		// Pages report each load more than once.
+		return "", false
+	}
+	n.page = raw
+	if raw == navigationBlank {
+		return "", false
+	}
+	if n.policy.Decide(raw) == NavigationAllow {
+		n.allowed = raw
+		return "", false
+	}
+	if n.allowed == "" {
+		return navigationBlank, true
+	}
+	return n.allowed, true
+}
+
+// pageURL converts a URL reported by a page to the form policies match:
+// pages served by the app become their path, query and fragment.
+func pageURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	origin := strings.ToLower(u.Scheme + "://" + u.Host)
+	local := slices.Contains(appOrigins, origin)
+	if dev, err := url.Parse(os.Getenv("FRONTEND_DEVSERVER_URL")); err == nil && dev.Host != "" {
+		local = local || strings.EqualFold(origin, dev.Scheme+"://"+dev.Host)
+	}
+	if !local {
+		return raw
+	}
+	u.Scheme, u.Host, u.User = "", "", nil
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// navigationReportScript returns the script that sends the page's URL to the
+// service. It runs on every origin: the platforms give each page the Wails
+// invoke channel, even pages without the rest of the runtime.
+func navigationReportScript() string {
+	message, _ := json.Marshal(navigationLocationMessage)
+	return `window._wails && window._wails.invoke && window._wails.invoke(` + string(message) + ` + location.href);`
+}
+
+// navigationLoadEvents are the window events sent when a page has loaded.
+var navigationLoadEvents = []events.WindowEventType{
+	events.Mac.WebViewDidFinishNavigation,
+	events.Windows.WebViewNavigationCompleted,
+	events.Linux.WindowLoadChanged,
+}
+
+// navigationGuardScript returns the script injected into windows with a
+// navigation policy. It stops links, form submissions and window.open calls
+// that would load another page, and asks the display service to navigate
+// instead by emitting an `EventNavigationRequest` through the Wails runtime.
+// Pages without the runtime, such as those served from other origins, cannot
+// ask, so their navigations are simply stopped. It also reports the page's
+// URL, which is what the policy is enforced on; stopping navigations only
+// keeps the page from loading at all.
+func navigationGuardScript() string {
+	event, _ := json.Marshal(EventNavigationRequest)
+	return navigationReportScript() + `
+(function () {
+	if (window.__coreNavigationGuard) return;
+	window.__coreNavigationGuard = true;
+	function target(href) {
+		var u;
+		try { u = new URL(href, location.href); } catch (e) { return null; }
+		if (u.hash && u.href.split("#")[0] === location.href.split("#")[0]) return null;
+		return u.origin === location.origin ? u.pathname + u.search + u.hash : u.href;
+	}
+	function guard(href, e) {
+		var url = target(href);
+		if (url === null) return true;
+		if (e) { e.preventDefault(); e.stopPropagation(); }
+		var events = window.wails && window.wails.Events;
+		if (events) events.Emit(` + string(event) + `, {url: url});
+		return false;
+	}
+	document.addEventListener("click", function (e) {
+		var a = e.target.closest && e.target.closest("a[href]");
+		if (a) guard(a.href, e);
+	}, true);
+	document.addEventListener("submit", function (e) {
+		guard(e.target.action || location.href, e);
+	}, true);
+	var open = window.open;
+	window.open = function (href) {
+		return !href || guard(href) ? open.apply(window, arguments) : null;
+	};
+})();`
+}
+
+// applyNavigationPolicy checks a new window's URL against its navigation
+// policy and installs the in-page guard.
+func (s *Service) applyNavigationPolicy(config *WindowConfig, opts *application.WebviewWindowOptions) error {
+	policy := config.Navigation
+	if policy == nil {
+		return nil
+	}
+	if action := policy.Decide(config.URL); action != NavigationAllow {
+		s.blockNavigation(config.Name, config.URL, action)
+		return fmt.Errorf("%w: %s", ErrNavigationBlocked, config.URL)
+	}
+	opts.JS = navigationGuardScript()
+
+	state := &navigationState{policy: policy}
+	s.navigationMu.Lock()
+	defer s.navigationMu.Unlock()
+	s.navigation[config.Name] = state
+	return nil
+}
+
+// watchNavigation asks a window with a navigation policy for its URL each
+// time it finishes loading a page. The guard script reports it as well, but
+// Windows only injects it into windows opened with HTML.
+func (s *Service) watchNavigation(window application.Window) {
+	if s.navigationPolicy(window.Name()) == nil {
+		return
+	}
+	for _, event := range navigationLoadEvents {
+		window.OnWindowEvent(event, func(*application.WindowEvent) {
+			window.ExecJS(navigationReportScript())
+		})
+	}
+}
+
+// HandleRawMessage handles the raw messages sent by pages with a navigation
+// policy, and must be set as the app's `RawMessageHandler` for policies to be
+// enforced. Each such page reports its URL once it has loaded, however it got
+// there: by script, meta refresh, server redirect or a link the guard missed.
+// Pages the policy does not allow are replaced by the last allowed page the
+// window showed, or a blank one. They have run by then; the guard script is
+// what keeps most of them from loading at all. Other messages are ignored.
+//
+// example:
+//
+//	app := application.New(application.Options{
+//		RawMessageHandler: displayService.HandleRawMessage,
+//	})
+func (s *Service) HandleRawMessage(window application.Window, message string) {
+	raw, ok := strings.CutPrefix(message, navigationLocationMessage)
+	if !ok {
+		return
+	}
+	raw = pageURL(raw)
+	s.navigationMu.Lock()
+	state, ok := s.navigation[window.Name()]
+	var restore string
+	var revert bool
+	if ok {
+		restore, revert = state.visited(raw)
+	}
+	s.navigationMu.Unlock()
+	if !revert {
+		return
+	}
+	s.blockNavigation(window.Name(), raw, NavigationBlock)
+	window.SetURL(restore)
+}
+
+// navigationPolicy returns the policy of an open window.
+func (s *Service) navigationPolicy(name string) *NavigationPolicy {
+	s.navigationMu.Lock()
+	defer s.navigationMu.Unlock()
+	if state, ok := s.navigation[name]; ok {
+		return state.policy
+	}
+	return nil
+}
+
+// installNavigationGuard handles navigation requests sent by the in-page
+// guard, which are checked by `Service.Navigate` like any other.
+func (s *Service) installNavigationGuard() {
+	s.app.Event.On(EventNavigationRequest, func(event *application.CustomEvent) {
+		data, _ := event.Data.(map[string]any)
+		raw, _ := data["url"].(string)
+		if raw == "" || s.navigationPolicy(event.Sender) == nil {
+			return
+		}
+		if err := s.Navigate(event.Sender, raw); err != nil {
+			s.app.Logger.Debug("Navigation request refused", "window", event.Sender, "url", raw, "error", err)
+		}
+	})
+}
+
+// Navigate points a window at a URL, applying its navigation policy. Allowed
+// URLs are loaded in the window, external ones are opened in the system
+// browser, and blocked ones are logged and announced as an
+// `EventNavigationBlocked` event.
+//
+// example:
+//
+//	err := displayService.Navigate("dapp", "https://app.uniswap.org/#/swap")
+func (s *Service) Navigate(name, raw string) error {
+	window, ok := s.app.Window.GetByName(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownWindow, name)
+	}
+	action := NavigationAllow
+	if policy := s.navigationPolicy(name); policy != nil {
+		action = policy.Decide(raw)
+	}
+	switch action {
+	case NavigationAllow:
+		window.SetURL(raw)
+	case NavigationExternal:
+		return s.app.Browser.OpenURL(raw)
+	case NavigationPrompt:
+		s.promptNavigation(window, raw)
+	default:
+		s.blockNavigation(name, raw, action)
+		return fmt.Errorf("%w: %s", ErrNavigationBlocked, raw)
+	}
+	return nil
+}
+
+// promptNavigation asks the user whether to open a URL in the system browser.
+func (s *Service) promptNavigation(window application.Window, raw string) {
+	dialog := s.app.Dialog.Question()
+	dialog.SetTitle(s.T("Open external link?"))
+	dialog.SetMessage(s.T("This page wants to open:\n\n%s\n\nOpen it in your browser?", raw))
+	dialog.AttachToWindow(window)
+	open := dialog.AddButton(s.T("Open in Browser"))
+	open.OnClick(func() {
+		if err := s.app.Browser.OpenURL(raw); err != nil {
+			s.app.Logger.Warn("Failed to open link", "url", raw, "error", err)
+		}
+	})
+	cancel := dialog.AddButton(s.T("Cancel"))
+	cancel.OnClick(func() {
+		s.blockNavigation(window.Name(), raw, NavigationPrompt)
+	})
+	dialog.SetDefaultButton(cancel)
+	dialog.SetCancelButton(cancel)
+	dialog.Show()
+}
+
+// blockNavigation logs a blocked navigation and announces it on the action
+// bus.
+func (s *Service) blockNavigation(window, raw string, action NavigationAction) {
+	s.app.Logger.Warn("Navigation blocked", "window", window, "url", raw, "policy", action)
+	s.app.Event.Emit(EventNavigationBlocked, ActionNavigationBlocked{Window: window, URL: raw})
+}
+
+// forgetNavigationPolicy drops the policy of a closed window.
+func (s *Service) forgetNavigationPolicy(name string) {
+	s.navigationMu.Lock()
+	defer s.navigationMu.Unlock()
+	delete(s.navigation, name)
+}
@@ -0,0 +1,132 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestURLAllowed(t *testing.T) {
+	allowed := []string{"/#/dashboard", "/assets/", "https://status.example.com/board"}
+	tests := map[string]bool{
+		"/#/dashboard":                          true,
+		"/#/dashboard/alerts":                   true,
+		"#/dashboard":                           true,
+		"/#/settings":                           false,
+		"/assets/logo.png":                      true,
+		"https://status.example.com/board":      true,
+		"https://STATUS.example.com/board/42":   true,
+		"https://status.example.com/boardroom":  false,
+		"http://status.example.com/board":       false,
+		"https://status.example.com.evil/board": false,
+		"https://evil.example/#/dashboard":      false,
+		"javascript:alert(1)":                   false,
+	}
+	for raw, want := range tests {
+		if got := urlAllowed(allowed, raw); got != want {
+			t.Errorf("urlAllowed(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestNavigationPolicy_Decide(t *testing.T) {
+	policy := NavigationPolicy{
+		Allowed:  []string{"/", "https://app.uniswap.org/"},
+		External: []string{"https://docs.uniswap.org/"},
+	}
+	tests := map[string]NavigationAction{
+		"/#/swap":                           NavigationAllow,
+		"https://app.uniswap.org/#/pool":    NavigationAllow,
+		"https://docs.uniswap.org/intro":    NavigationExternal,
+		"https://phishing.example/":         NavigationBlock,
+		"file:///etc/passwd":                NavigationBlock,
+		"javascript:alert(document.domain)": NavigationBlock,
+	}
+	for raw, want := range tests {
+		if got := policy.Decide(raw); got != want {
+			t.Errorf("Decide(%q) = %s, want %s", raw, got, want)
+		}
+	}
+
+	policy.Default = NavigationPrompt
+	if got := policy.Decide("https://phishing.example/"); got != NavigationPrompt {
+		t.Errorf("Decide() with a prompt default = %s, want prompt", got)
+	}
+	if got := policy.Decide("file:///etc/passwd"); got != NavigationBlock {
+		t.Errorf("Decide() never prompts for non-web URLs, got %s", got)
+	}
+	policy.Default = NavigationExternal
+	if got := policy.Decide("mailto:team@example.com"); got != NavigationExternal {
+		t.Errorf("Decide() with an external default = %s, want external", got)
+	}
+}
+
+func TestNavigationGuardScript(t *testing.T) {
+	script := navigationGuardScript()
+	if want := `events.Emit("` + EventNavigationRequest + `"`; !strings.Contains(script, want) {
+		t.Errorf("guard script is missing %s", want)
+	}
+	if !strings.Contains(script, navigationLocationMessage) {
+		t.Error("guard script does not report the page URL")
+	}
+	if strings.Contains(script, "/wails/runtime") {
+		t.Error("guard script calls the Wails runtime endpoint directly")
+	}
+}
+
+func TestNavigationStateVisited(t *testing.T) {
+	state := &navigationState{policy: &NavigationPolicy{Allowed: []string{"/", "https://app.example.com/"}}}
+	if _, revert := state.visited("/#/home"); revert {
+		t.Error("visited() reverted an allowed page")
+	}
+	// The page then navigates by itself, for example with location.href.
+	if restore, revert := state.visited("https://evil.example/"); !revert || restore != "/#/home" {
+		t.Errorf("visited() after leaving the policy = %q, %v, want /#/home, true", restore, revert)
+	}
+	// Both the guard and the probe report the same load.
+	if _, revert := state.visited("https://evil.example/"); revert {
+		t.Error("visited() reverted the same load twice")
+	}
+	if _, revert := state.visited("/#/home"); revert {
+		t.Error("visited() reverted the page it put back")
+	}
+}
+
+func TestNavigationStateRedirect(t *testing.T) {
+	state := &navigationState{policy: &NavigationPolicy{Allowed: []string{"https://app.example.com/"}}}
+	state.visited("https://app.example.com/")
+	// The service loads an allowed URL that the server redirects elsewhere.
+	if restore, revert := state.visited("https://evil.example/landing"); !revert || restore != "https://app.example.com/" {
+		t.Errorf("visited() after a redirect = %q, %v, want https://app.example.com/, true", restore, revert)
+	}
+
+	// With no allowed page to go back to, the window is blanked.
+	state = &navigationState{policy: state.policy}
+	if restore, revert := state.visited("https://evil.example/landing"); !revert || restore != navigationBlank {
+		t.Errorf("visited() after a first-load redirect = %q, %v, want %s, true", restore, revert, navigationBlank)
+	}
+	if _, revert := state.visited(navigationBlank); revert {
+		t.Error("visited() reverted the blank page")
+	}
+}
+
+func TestPageURL(t *testing.T) {
+	tests := map[string]string{
+		"wails://localhost/#/home":        "/#/home",
+		"http://wails.localhost/settings": "/settings",
+		"wails://localhost":               "/",
+		"https://app.example.com/swap":    "https://app.example.com/swap",
+		"about:blank":                     "about:blank",
+	}
+	for raw, want := range tests {
+		if got := pageURL(raw); got != want {
+			t.Errorf("pageURL(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestWithNavigationPolicy(t *testing.T) {
+	config := buildWindowConfig(WithNavigationPolicy(NavigationPolicy{Allowed: []string{"/"}}))
+	if config.Navigation == nil || config.Navigation.Decide("/#/home") != NavigationAllow {
+		t.Errorf("WithNavigationPolicy() = %+v", config.Navigation)
+	}
+}
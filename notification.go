@@ -0,0 +1,134 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNotificationsUnsupported is returned by Notify on platforms without a
+// native notification backend.
+var ErrNotificationsUnsupported = errors.New("display: desktop notifications are not supported on this platform")
+
+// NotificationUrgency describes how important a notification is. The values
+// match the urgency levels of the freedesktop notifications spec.
+type NotificationUrgency byte
+
+const (
+	// UrgencyLow is used for informational notifications.
+	UrgencyLow NotificationUrgency = 0
+	// UrgencyNormal is the default urgency.
+	UrgencyNormal NotificationUrgency = 1
+	// UrgencyCritical is used for notifications that should not expire.
+	UrgencyCritical NotificationUrgency = 2
+)
+
+// NotificationAction is a button shown on a notification. When the user
+// clicks it, the ID is delivered back through the action bus.
+type NotificationAction struct {
+	ID    string
+	Label string
+}
+
+// Notification holds the content of a native desktop notification.
+//
+// example:
+//
+//	id, err := displayService.Notify(ctx, display.Notification{
+//		Title:   "Backup complete",
+//		Body:    "Your workspace has been saved.",
+//		Urgency: display.UrgencyNormal,
+//		Actions: []display.NotificationAction{{ID: "open", Label: "Open"}},
+//		Timeout: 5 * time.Second,
+//	})
+type Notification struct {
+	// ReplacesID replaces an existing notification instead of creating a new
+	// one when it is non-zero.
+	ReplacesID uint32
+	Title      string
+	Body       string
+	// Icon is an icon name from the current theme or a file:// URI.
+	Icon    string
+	Urgency NotificationUrgency
+	Actions []NotificationAction
+	// Timeout is how long the notification stays on screen. Zero uses the
+	// server default and a negative value never expires.
+	Timeout time.Duration
+}
+
+// notifier is implemented by each platform's notification backend.
+type notifier interface {
+	Notify(ctx context.Context, n Notification) (uint32, error)
+	Close() error
+}
+
+// Notify shows a native desktop notification and returns its ID. Clicks on
+// the notification's action buttons are emitted as `EventNotificationAction`
+// events carrying an `ActionNotificationAction`.
+//
+// example:
+//
+//	id, err := displayService.Notify(ctx, display.Notification{
+//		Title: "Hello",
+//		Body:  "From the display service",
+//	})
+//	if err != nil {
+//		log.Println(err)
+//	}
+func (s *Service) Notify(ctx context.Context, n Notification) (uint32, error) {
+	s.notifierMu.Lock()
+	if s.notifier == nil {
+		nt, err := newNotifier(s.appName(), s.handleNotificationAction)
+		if err != nil {
+			s.notifierMu.Unlock()
+			return 0, err
+		}
+		s.notifier = nt
+	}
+	nt := s.notifier
+	s.notifierMu.Unlock()
+	return nt.Notify(ctx, n)
+}
+
+// closeNotifier disconnects the notification backend, if one was started.
+// It is run when the app shuts down.
+func (s *Service) closeNotifier() {
+	s.notifierMu.Lock()
+	defer s.notifierMu.Unlock()
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.Close(); err != nil && s.app != nil {
+		s.app.Logger.Debug("Closing notifications", "error", err)
+	}
+	s.notifier = nil
+}
+
+// handleNotificationAction forwards a notification button click to the
+// action bus.
+func (s *Service) handleNotificationAction(id uint32, action string) {
+	if s.app == nil {
+		return
+	}
+	s.app.Event.Emit(EventNotificationAction, ActionNotificationAction{
+		ID:     id,
+		Action: action,
+	})
+}
+
+// timeoutMillis converts a notification timeout to the milliseconds value
+// expected by notification servers, where -1 means the server default and
+// 0 means never expire. Timeouts too long for an int32 are clamped.
+func timeoutMillis(timeout time.Duration) int32 {
+	switch {
+	case timeout == 0:
+		return -1
+	case timeout < 0:
+		return 0
+	case timeout/time.Millisecond > math.MaxInt32:
+		return math.MaxInt32
+	default:
+		return int32(timeout / time.Millisecond)
+	}
+}
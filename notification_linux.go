@@ -0,0 +1,150 @@
+//go:build linux
+
+package display
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// The freedesktop notifications spec: https://specifications.freedesktop.org/notification-spec/
+const (
+	notificationsBusName   = "org.freedesktop.Notifications"
+	notificationsPath      = dbus.ObjectPath("/org/freedesktop/Notifications")
+	notificationsInterface = "org.freedesktop.Notifications"
+)
+
+// dbusNotifier sends notifications to the freedesktop notification server on
+// the session bus and listens for action button clicks on them.
+type dbusNotifier struct {
+	conn     *dbus.Conn
+	appName  string
+	onAction func(id uint32, action string)
+	signals  chan *dbus.Signal
+
+	// ids are the notifications sent by this notifier that are still open.
+	// Signals are broadcast for every app's notifications, so others are
+	// ignored.
+	mu  sync.Mutex
+	ids map[uint32]bool
+}
+
+// newNotifier connects to the session bus and returns a notifier for it.
+func newNotifier(appName string, onAction func(id uint32, action string)) (notifier, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("display: connecting to session bus: %w", err)
+	}
+	n, err := newDBusNotifier(conn, appName, onAction)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return n, nil
+}
+
+// newDBusNotifier creates a notifier on an existing bus connection. It
+// subscribes to the ActionInvoked signal so that button clicks can be
+// forwarded to onAction, and to NotificationClosed to forget closed
+// notifications.
+func newDBusNotifier(conn *dbus.Conn, appName string, onAction func(id uint32, action string)) (*dbusNotifier, error) {
+	for _, member := range []string{"ActionInvoked", "NotificationClosed"} {
+		err := conn.AddMatchSignal(
+			dbus.WithMatchObjectPath(notificationsPath),
+			dbus.WithMatchInterface(notificationsInterface),
+			dbus.WithMatchMember(member),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("display: subscribing to notification signals: %w", err)
+		}
+	}
+	n := &dbusNotifier{
+		conn:     conn,
+		appName:  appName,
+		onAction: onAction,
+		signals:  make(chan *dbus.Signal, 16),
+		ids:      map[uint32]bool{},
+	}
+	conn.Signal(n.signals)
+	go n.listen()
+	return n, nil
+}
+
+// listen forwards ActionInvoked signals for this notifier's notifications
+// until the connection is closed.
+func (n *dbusNotifier) listen() {
+	for sig := range n.signals {
+		if len(sig.Body) != 2 {
+			continue
+		}
+		id, ok := sig.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+		switch sig.Name {
+		case notificationsInterface + ".NotificationClosed":
+			n.mu.Lock()
+			delete(n.ids, id)
+			n.mu.Unlock()
+		case notificationsInterface + ".ActionInvoked":
+			action, ok := sig.Body[1].(string)
+			if !ok || !n.sent(id) {
+				continue
+			}
+			if n.onAction != nil {
+				n.onAction(id, action)
+			}
+		}
+	}
+}
+
+// sent reports whether a notification was sent by this notifier.
+func (n *dbusNotifier) sent(id uint32) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.ids[id]
+}
+
+// Notify calls org.freedesktop.Notifications.Notify and returns the ID
+// assigned by the server.
+func (n *dbusNotifier) Notify(ctx context.Context, notification Notification) (uint32, error) {
+	actions := make([]string, 0, len(notification.Actions)*2)
+	for _, action := range notification.Actions {
+		actions = append(actions, action.ID, action.Label)
+	}
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(byte(notification.Urgency)),
+	}
+
+	var id uint32
+	call := n.conn.Object(notificationsBusName, notificationsPath).CallWithContext(
+		ctx,
+		notificationsInterface+".Notify",
+		0,
+		n.appName,
+		notification.ReplacesID,
+		notification.Icon,
+		notification.Title,
+		notification.Body,
+		actions,
+		hints,
+		timeoutMillis(notification.Timeout),
+	)
+	if err := call.Store(&id); err != nil {
+		return 0, fmt.Errorf("display: sending notification: %w", err)
+	}
+	n.mu.Lock()
+	n.ids[id] = true
+	n.mu.Unlock()
+	return id, nil
+}
+
+// Close stops listening for signals and closes the bus connection.
+func (n *dbusNotifier) Close() error {
+	n.conn.RemoveSignal(n.signals)
+	close(n.signals)
+	return n.conn.Close()
+}
@@ -0,0 +1,221 @@
+//go:build linux
+
+package display
+
+import (
+	"bufio"
+	"context"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakeNotificationServer implements the org.freedesktop.Notifications
+// interface and records the notifications it receives.
+type fakeNotificationServer struct {
+	mu     sync.Mutex
+	nextID uint32
+	calls  []fakeNotifyCall
+}
+
+type fakeNotifyCall struct {
+	AppName    string
+	ReplacesID uint32
+	Icon       string
+	Summary    string
+	Body       string
+	Actions    []string
+	Hints      map[string]dbus.Variant
+	Timeout    int32
+}
+
+func (f *fakeNotificationServer) Notify(appName string, replacesID uint32, icon, summary, body string, actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, fakeNotifyCall{appName, replacesID, icon, summary, body, actions, hints, timeout})
+	f.nextID++
+	return f.nextID, nil
+}
+
+// startSessionBus runs a private dbus-daemon for the duration of the test and
+// returns its address.
+func startSessionBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not available")
+	}
+	dir := t.TempDir()
+	config := filepath.Join(dir, "session.conf")
+	err = os.WriteFile(config, []byte(`<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:dir=`+dir+`</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(daemon, "--config-file="+config, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading dbus-daemon address: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+// startFakeNotificationServer exports a fakeNotificationServer on the bus at
+// address under the well-known notifications name.
+func startFakeNotificationServer(t *testing.T, address string) (*fakeNotificationServer, *dbus.Conn) {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	server := &fakeNotificationServer{}
+	if err := conn.Export(server, notificationsPath, notificationsInterface); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := conn.RequestName(notificationsBusName, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName() = %v, %v", reply, err)
+	}
+	return server, conn
+}
+
+func TestDBusNotifier_Notify(t *testing.T) {
+	address := startSessionBus(t)
+	server, _ := startFakeNotificationServer(t, address)
+
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := newDBusNotifier(conn, "Core Test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+
+	id, err := n.Notify(context.Background(), Notification{
+		Title:   "Backup complete",
+		Body:    "Your workspace has been saved.",
+		Icon:    "dialog-information",
+		Urgency: UrgencyCritical,
+		Actions: []NotificationAction{{ID: "open", Label: "Open"}, {ID: "dismiss", Label: "Dismiss"}},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if id != 1 {
+		t.Errorf("Notify() id = %d, want 1", id)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.calls) != 1 {
+		t.Fatalf("server received %d calls, want 1", len(server.calls))
+	}
+	call := server.calls[0]
+	if call.AppName != "Core Test" || call.Summary != "Backup complete" || call.Body != "Your workspace has been saved." || call.Icon != "dialog-information" {
+		t.Errorf("unexpected notification content: %+v", call)
+	}
+	if got, want := strings.Join(call.Actions, ","), "open,Open,dismiss,Dismiss"; got != want {
+		t.Errorf("actions = %q, want %q", got, want)
+	}
+	if urgency, ok := call.Hints["urgency"].Value().(byte); !ok || urgency != byte(UrgencyCritical) {
+		t.Errorf("urgency hint = %v, want %d", call.Hints["urgency"], UrgencyCritical)
+	}
+	if call.Timeout != 5000 {
+		t.Errorf("timeout = %d, want 5000", call.Timeout)
+	}
+}
+
+func TestDBusNotifier_ActionInvoked(t *testing.T) {
+	address := startSessionBus(t)
+	_, serverConn := startFakeNotificationServer(t, address)
+
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type click struct {
+		id     uint32
+		action string
+	}
+	clicks := make(chan click, 1)
+	n, err := newDBusNotifier(conn, "Core Test", func(id uint32, action string) {
+		clicks <- click{id, action}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+
+	// Clicks on other apps' notifications are not forwarded.
+	if err := serverConn.Emit(notificationsPath, notificationsInterface+".ActionInvoked", uint32(7), "open"); err != nil {
+		t.Fatal(err)
+	}
+	id, err := n.Notify(context.Background(), Notification{Title: "Backup complete"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := serverConn.Emit(notificationsPath, notificationsInterface+".ActionInvoked", id, "open"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-clicks:
+		if got.id != id || got.action != "open" {
+			t.Errorf("onAction(%d, %q), want (%d, \"open\")", got.id, got.action, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for ActionInvoked")
+	}
+}
+
+func TestTimeoutMillis(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    int32
+	}{
+		{0, -1},
+		{-1, 0},
+		{1500 * time.Millisecond, 1500},
+		{1000 * time.Hour, math.MaxInt32},
+	}
+	for _, tt := range tests {
+		if got := timeoutMillis(tt.timeout); got != tt.want {
+			t.Errorf("timeoutMillis(%v) = %d, want %d", tt.timeout, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package display
+
+// newNotifier reports that native notifications are not yet implemented on
+// this platform.
+func newNotifier(appName string, onAction func(id uint32, action string)) (notifier, error) {
+	return nil, ErrNotificationsUnsupported
+}
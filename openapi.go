@@ -0,0 +1,99 @@
+package display
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// SchemaPath is where `SchemaHandler` is mounted by `demo-cli serve`.
+const SchemaPath = "/api/v1/schema"
+
+// OpenAPI returns an OpenAPI 3.1 document for the HTTP API served by
+// `demo-cli serve`. Its components are the protocol types from `JSONSchema`,
+// and the actions and events are listed under "x-actions" and "x-events".
+//
+// example:
+//
+//	doc, err := display.OpenAPI()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	os.WriteFile("openapi.json", doc, 0o644)
+func OpenAPI() ([]byte, error) {
+	schema := protocolSchema()
+	jsonResponse := func(description, mediaType string, body any) map[string]any {
+		return map[string]any{
+			"200": map[string]any{
+				"description": description,
+				"content":     map[string]any{mediaType: map[string]any{"schema": body}},
+			},
+		}
+	}
+	doc := map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "Core display API",
+			"version":     "1",
+			"description": schema.Description,
+		},
+		"paths": map[string]any{
+			"/api/v1/demo": map[string]any{
+				"get": map[string]any{
+					"operationId": "demo",
+					"summary":     "Returns a greeting.",
+					"responses":   jsonResponse("A greeting.", "text/plain", map[string]any{"type": "string"}),
+				},
+			},
+			SchemaPath: map[string]any{
+				"get": map[string]any{
+					"operationId": "schema",
+					"summary":     "Returns the JSON Schema of the display protocol, or this document.",
+					"parameters": []any{map[string]any{
+						"name":        "format",
+						"in":          "query",
+						"description": `"openapi" returns this document instead of the JSON Schema.`,
+						"schema":      map[string]any{"type": "string", "enum": []string{"jsonschema", "openapi"}},
+					}},
+					"responses": jsonResponse("The requested document.", "application/json", map[string]any{"type": "object"}),
+				},
+			},
+		},
+		"components": map[string]any{"schemas": schema.Defs},
+		"x-actions":  schema.Actions,
+		"x-events":   schema.Events,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	data = []byte(strings.ReplaceAll(string(data), `"#/$defs/`, `"#/components/schemas/`))
+	return append(data, '\n'), nil
+}
+
+// SchemaHandler serves the JSON Schema of the display protocol, or the
+// OpenAPI document when the "format" query parameter is "openapi".
+//
+// example:
+//
+//	http.Handle(display.SchemaPath, display.SchemaHandler())
+func SchemaHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		generate, mediaType := JSONSchema, "application/schema+json"
+		if r.URL.Query().Get("format") == "openapi" {
+			generate, mediaType = OpenAPI, "application/vnd.oai.openapi+json"
+		}
+		data, err := generate()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		_, _ = w.Write(data)
+	})
+}
@@ -0,0 +1,353 @@
+package display
+
+import (
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// Placement is the policy used to position a new window.
+type Placement string
+
+const (
+	// PlacementCentre centres the window in the work area of its screen. It
+	// is the default when a screen is chosen with `WithScreen`.
+	PlacementCentre Placement = "centre"
+	// PlacementRemember reopens the window where it was last closed. If that
+	// screen has since been disconnected, the window is centred on its
+	// target screen instead.
+	PlacementRemember Placement = "remember"
+	// PlacementClamp leaves positioning to the platform, or to the parent
+	// window, and only pulls the window into the visible work area.
+	PlacementClamp Placement = "clamp"
+)
+
+// Special screen names accepted by `WithScreen`, in addition to screen IDs.
+const (
+	ScreenPrimary = "primary"
+	// ScreenCursor selects the screen the user is working on. Wails does not
+	// expose the pointer position, so this is the screen of the focused
+	// window.
+	ScreenCursor = "cursor"
+	ScreenParent = "parent"
+)
+
+// windowPlacement is the last known position of a window.
+type windowPlacement struct {
+	Screen string           `json:"screen"`
+	Bounds application.Rect `json:"bounds"`
+}
+
+// placementRequest holds everything needed to position a new window.
+type placementRequest struct {
+	Width, Height int
+	// Screen is the target screen, if one was chosen.
+	Screen *application.Screen
+	// Parent is the bounds of the parent window to centre over.
+	Parent *application.Rect
+	// Saved is the remembered position of the window.
+	Saved *windowPlacement
+}
+
+// placeRect decides the initial bounds of a window. It returns false when the
+// platform should place the window. Every position it chooses is clamped into
+// the work area of a connected screen.
+func placeRect(screens []*application.Screen, req placementRequest) (application.Rect, bool) {
+	if req.Saved != nil {
+		for _, screen := range screens {
+			if screen != nil && screen.ID == req.Saved.Screen {
+				return clampRect(req.Saved.Bounds, screen.WorkArea), true
+			}
+		}
+	}
+	rect := application.Rect{Width: req.Width, Height: req.Height}
+	switch {
+	case req.Parent != nil:
+		rect.X, rect.Y = centreOver(*req.Parent, req.Width, req.Height)
+	case req.Screen != nil:
+		rect.X, rect.Y = centreOver(req.Screen.WorkArea, req.Width, req.Height)
+	default:
+		return application.Rect{}, false
+	}
+	screen := screenFor(screens, rect)
+	if screen == nil {
+		screen = req.Screen
+	}
+	if screen == nil {
+		var err error
+		if screen, err = findScreen(screens, ScreenPrimary); err != nil {
+			return rect, true
+		}
+	}
+	return clampRect(rect, screen.WorkArea), true
+}
+
+// clampRect shrinks rect to fit within area and moves it inside.
+func clampRect(rect, area application.Rect) application.Rect {
+	rect.Width = min(rect.Width, area.Width)
+	rect.Height = min(rect.Height, area.Height)
+	rect.X = max(area.X, min(rect.X, area.X+area.Width-rect.Width))
+	rect.Y = max(area.Y, min(rect.Y, area.Y+area.Height-rect.Height))
+	return rect
+}
+
+// screenFor returns the screen whose work area overlaps rect the most, or nil
+// if rect is not on any screen.
+func screenFor(screens []*application.Screen, rect application.Rect) *application.Screen {
+	var best *application.Screen
+	bestArea := 0
+	for _, screen := range screens {
+		if screen == nil {
+			continue
+		}
+		overlap := screen.WorkArea.Intersect(rect)
+		if area := overlap.Width * overlap.Height; area > bestArea {
+			best, bestArea = screen, area
+		}
+	}
+	return best
+}
+
+// offscreenBounds returns where a window with the given bounds should be
+// moved to so that it is visible, and whether it needs moving at all.
+func offscreenBounds(screens []*application.Screen, rect application.Rect) (application.Rect, bool) {
+	screen := screenFor(screens, rect)
+	if screen == nil {
+		primary, err := findScreen(screens, ScreenPrimary)
+		if err != nil {
+			return rect, false
+		}
+		x, y := centreOver(primary.WorkArea, rect.Width, rect.Height)
+		return clampRect(application.Rect{X: x, Y: y, Width: rect.Width, Height: rect.Height}, primary.WorkArea), true
+	}
+	clamped := clampRect(rect, screen.WorkArea)
+	return clamped, clamped != rect
+}
+
+// WithScreen chooses the screen a window opens on: a screen ID, or one of
+// "primary", "cursor" or "parent". The window is centred on that screen
+// unless another placement is set with `WithPlacement`.
+//
+// example:
+//
+//	err := displayService.OpenWindow(
+//		display.WithName("presenter"),
+//		display.WithScreen("primary"),
+//	)
+func WithScreen(screen string) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.Screen = screen
+	})
+}
+
+// WithPlacement sets the policy used to position the window.
+//
+// example:
+//
+//	err := displayService.OpenWindow(
+//		display.WithName("inspector"),
+//		display.WithPlacement(display.PlacementRemember),
+//	)
+func WithPlacement(placement Placement) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.Placement = placement
+	})
+}
+
+// placeWindow sets the initial position of a new window from its screen,
+// parent and placement policy.
+func (s *Service) placeWindow(config *WindowConfig, opts *application.WebviewWindowOptions) {
+	var parent application.Window
+	if config.Parent != "" {
+		parent, _ = s.app.Window.GetByName(config.Parent)
+	}
+	screens := s.app.Screen.GetAll()
+	req := placementRequest{
+		Width:  opts.Width,
+		Height: opts.Height,
+		Screen: s.targetScreen(config, parent, screens),
+	}
+	if parent != nil && (config.Screen == "" || config.Screen == ScreenParent) {
+		bounds := parent.Bounds()
+		req.Parent = &bounds
+	}
+	if config.Placement == PlacementRemember {
+		s.placementsMu.Lock()
+		if saved, ok := s.placements[config.Name]; ok {
+			req.Saved = &saved
+		}
+		s.placementsMu.Unlock()
+	}
+	if rect, ok := placeRect(screens, req); ok {
+		opts.InitialPosition = application.WindowXY
+		opts.X, opts.Y = rect.X, rect.Y
+		opts.Width, opts.Height = rect.Width, rect.Height
+	}
+}
+
+// targetScreen resolves the screen named by `WithScreen`. It returns nil if
+// the platform should choose, and the primary screen if the named screen is
+// no longer connected.
+func (s *Service) targetScreen(config *WindowConfig, parent application.Window, screens []*application.Screen) *application.Screen {
+	var window application.Window
+	switch config.Screen {
+	case "":
+		if config.Placement != PlacementCentre && config.Placement != PlacementRemember {
+			return nil
+		}
+	case ScreenParent:
+		window = parent
+	case ScreenCursor:
+		window = s.app.Window.Current()
+	}
+	if window != nil {
+		if screen, err := window.GetScreen(); err == nil && screen != nil {
+			return screen
+		}
+	}
+	if config.Screen != "" && config.Screen != ScreenPrimary && config.Screen != ScreenParent && config.Screen != ScreenCursor {
+		if screen, err := findScreen(screens, config.Screen); err == nil {
+			return screen
+		}
+		s.app.Logger.Warn("Screen not connected, using the primary screen", "window", config.Name, "screen", config.Screen)
+	}
+	screen, err := findScreen(screens, ScreenPrimary)
+	if err != nil {
+		return nil
+	}
+	return screen
+}
+
+// watchPlacement applies the placement policy once a window is open: clamped
+// windows are pulled on-screen, and remembered windows have their position
+// saved when they close.
+func (s *Service) watchPlacement(window application.Window, config *WindowConfig) {
+	switch config.Placement {
+	case PlacementClamp:
+		if bounds, moved := offscreenBounds(s.app.Screen.GetAll(), window.Bounds()); moved {
+			window.SetBounds(bounds)
+		}
+	case PlacementRemember:
+		window.OnWindowEvent(events.Common.WindowClosing, func(*application.WindowEvent) {
+			s.rememberPlacement(window)
+		})
+	}
+}
+
+// rememberPlacement saves the current position and screen of a window.
+func (s *Service) rememberPlacement(window application.Window) {
+	placement := windowPlacement{Bounds: window.Bounds()}
+	if screen, err := window.GetScreen(); err == nil && screen != nil {
+		placement.Screen = screen.ID
+	}
+	s.placementsMu.Lock()
+	defer s.placementsMu.Unlock()
+	s.placements[window.Name()] = placement
+	if err := writeStateFile(s.placementsPath(), "window placements", s.placements); err != nil {
+		s.app.Logger.Warn("Failed to save window placement", "window", window.Name(), "error", err)
+	}
+}
+
+// EnsureWindowsOnScreen moves any window opened by the service that is no
+// longer visible, for example because its monitor was unplugged, back into
+// the work area of a connected screen. It returns the names of the windows
+// it moved. It runs automatically when the screen configuration changes: on
+// macOS when the system reports it, elsewhere when the screen list polled
+// every `screenPollInterval` differs from the last one seen.
+func (s *Service) EnsureWindowsOnScreen() []string {
+	screens := s.app.Screen.GetAll()
+	var moved []string
+	for _, name := range s.windows.names() {
+		window, ok := s.app.Window.GetByName(name)
+		if !ok || window.IsMinimised() || window.IsFullscreen() {
+			continue
+		}
+		if bounds, ok := offscreenBounds(screens, window.Bounds()); ok {
+			window.SetBounds(bounds)
+			moved = append(moved, name)
+		}
+	}
+	return moved
+}
+
+// screenPollInterval is how often the screen list is checked for changes on
+// platforms that do not report them.
+const screenPollInterval = 2 * time.Second
+
+// watchScreens calls `EnsureWindowsOnScreen` whenever the screens are
+// connected, disconnected or rearranged.
+func (s *Service) watchScreens() {
+	s.app.Event.OnApplicationEvent(events.Mac.ApplicationDidChangeScreenParameters, func(*application.ApplicationEvent) {
+		s.screensChanged()
+	})
+	if runtime.GOOS == "darwin" {
+		return
+	}
+	stop := make(chan struct{})
+	s.app.OnShutdown(func() { close(stop) })
+	go func() {
+		ticker := time.NewTicker(screenPollInterval)
+		defer ticker.Stop()
+		last := screenLayout(s.app.Screen.GetAll())
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			layout := screenLayout(s.app.Screen.GetAll())
+			if slices.Equal(layout, last) {
+				continue
+			}
+			last = layout
+			s.screensChanged()
+		}
+	}()
+}
+
+// screensChanged moves windows left off-screen back into view.
+func (s *Service) screensChanged() {
+	if moved := s.EnsureWindowsOnScreen(); len(moved) > 0 {
+		s.app.Logger.Info("Moved windows back on-screen", "windows", moved)
+	}
+}
+
+// screenArea is the part of a screen compared by watchScreens.
+type screenArea struct {
+	id       string
+	bounds   application.Rect
+	workArea application.Rect
+}
+
+// screenLayout returns a copy of the identity and geometry of screens.
+func screenLayout(screens []*application.Screen) []screenArea {
+	layout := make([]screenArea, 0, len(screens))
+	for _, screen := range screens {
+		layout = append(layout, screenArea{id: screen.ID, bounds: screen.Bounds, workArea: screen.WorkArea})
+	}
+	return layout
+}
+
+// placementsPath returns the remembered window placements file configured in
+// Options, falling back to the user's config directory.
+func (s *Service) placementsPath() string {
+	return statePath(s.config.PlacementsPath, "placements.json")
+}
+
+// loadPlacements reads the remembered window placements.
+func (s *Service) loadPlacements() error {
+	placements := map[string]windowPlacement{}
+	if err := readStateFile(s.placementsPath(), "window placements", &placements); err != nil {
+		return err
+	}
+	if placements == nil {
+		placements = map[string]windowPlacement{}
+	}
+	s.placementsMu.Lock()
+	defer s.placementsMu.Unlock()
+	s.placements = placements
+	return nil
+}
@@ -0,0 +1,136 @@
+package display
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// testScreens is a laptop with a larger monitor to its right.
+func testScreens() []*application.Screen {
+	return []*application.Screen{
+		{ID: "laptop", IsPrimary: true, WorkArea: application.Rect{X: 0, Y: 0, Width: 1440, Height: 860}},
+		{ID: "monitor", WorkArea: application.Rect{X: 1440, Y: 0, Width: 2560, Height: 1400}},
+	}
+}
+
+func TestPlaceRect(t *testing.T) {
+	screens := testScreens()
+	laptop, monitor := screens[0], screens[1]
+	r := func(x, y, w, h int) application.Rect { return application.Rect{X: x, Y: y, Width: w, Height: h} }
+
+	tests := []struct {
+		name   string
+		req    placementRequest
+		want   application.Rect
+		placed bool
+	}{
+		{name: "Platform default", req: placementRequest{Width: 800, Height: 600}},
+		{
+			name: "Centre on screen", req: placementRequest{Width: 800, Height: 600, Screen: monitor},
+			want: r(2320, 400, 800, 600), placed: true,
+		},
+		{
+			name: "Clamp oversized window", req: placementRequest{Width: 1600, Height: 1000, Screen: laptop},
+			want: r(0, 0, 1440, 860), placed: true,
+		},
+		{
+			name: "Centre over parent", req: placementRequest{Width: 400, Height: 300, Parent: &application.Rect{X: 1600, Y: 100, Width: 1000, Height: 800}},
+			want: r(1900, 350, 400, 300), placed: true,
+		},
+		{
+			name: "Parent near the edge", req: placementRequest{Width: 800, Height: 600, Parent: &application.Rect{X: 0, Y: 500, Width: 400, Height: 300}},
+			want: r(0, 260, 800, 600), placed: true,
+		},
+		{
+			name: "Remembered screen",
+			req:  placementRequest{Width: 800, Height: 600, Screen: laptop, Saved: &windowPlacement{Screen: "monitor", Bounds: r(3000, 900, 900, 700)}},
+			want: r(3000, 700, 900, 700), placed: true,
+		},
+		{
+			name: "Remembered screen unplugged",
+			req:  placementRequest{Width: 800, Height: 600, Screen: laptop, Saved: &windowPlacement{Screen: "projector", Bounds: r(5000, 0, 900, 700)}},
+			want: r(320, 130, 800, 600), placed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, placed := placeRect(screens, tt.req)
+			if placed != tt.placed || got != tt.want {
+				t.Errorf("placeRect() = %v, %v, want %v, %v", got, placed, tt.want, tt.placed)
+			}
+		})
+	}
+}
+
+func TestOffscreenBounds(t *testing.T) {
+	screens := testScreens()
+	tests := []struct {
+		name  string
+		rect  application.Rect
+		want  application.Rect
+		moved bool
+	}{
+		{name: "Visible", rect: application.Rect{X: 100, Y: 100, Width: 800, Height: 600}, want: application.Rect{X: 100, Y: 100, Width: 800, Height: 600}},
+		{name: "Partly off the bottom", rect: application.Rect{X: 1500, Y: 1200, Width: 800, Height: 600}, want: application.Rect{X: 1500, Y: 800, Width: 800, Height: 600}, moved: true},
+		{name: "On an unplugged monitor", rect: application.Rect{X: -1920, Y: 0, Width: 800, Height: 600}, want: application.Rect{X: 320, Y: 130, Width: 800, Height: 600}, moved: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, moved := offscreenBounds(screens, tt.rect)
+			if moved != tt.moved || got != tt.want {
+				t.Errorf("offscreenBounds() = %v, %v, want %v, %v", got, moved, tt.want, tt.moved)
+			}
+		})
+	}
+}
+
+func TestScreenFor(t *testing.T) {
+	screens := testScreens()
+	// Straddling both screens, mostly on the monitor.
+	if got := screenFor(screens, application.Rect{X: 1200, Y: 0, Width: 800, Height: 600}); got != screens[1] {
+		t.Errorf("screenFor() = %v, want the monitor", got)
+	}
+	if got := screenFor(screens, application.Rect{X: 0, Y: 2000, Width: 800, Height: 600}); got != nil {
+		t.Errorf("screenFor() off-screen = %v, want nil", got)
+	}
+}
+
+func TestPlacementsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "placements.json")
+	want := map[string]windowPlacement{
+		"inspector": {Screen: "monitor", Bounds: application.Rect{X: 1500, Y: 40, Width: 640, Height: 900}},
+	}
+	if err := writeStateFile(path, "window placements", want); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := NewWithOptions(Options{PlacementsPath: path})
+	if err := s.loadPlacements(); err != nil {
+		t.Fatalf("loadPlacements() error = %v", err)
+	}
+	if !reflect.DeepEqual(s.placements, want) {
+		t.Errorf("loadPlacements() = %+v, want %+v", s.placements, want)
+	}
+}
+
+func TestWithScreen(t *testing.T) {
+	config := buildWindowConfig(WithScreen("cursor"), WithPlacement(PlacementRemember))
+	if config.Screen != ScreenCursor || config.Placement != PlacementRemember {
+		t.Errorf("buildWindowConfig() = %+v", config)
+	}
+}
+
+func TestScreenLayout(t *testing.T) {
+	laptop := &application.Screen{ID: "1", Bounds: application.Rect{Width: 1440, Height: 900}, WorkArea: application.Rect{Y: 25, Width: 1440, Height: 875}}
+	before := screenLayout([]*application.Screen{laptop})
+	laptop.WorkArea.Height = 800
+	if slices.Equal(before, screenLayout([]*application.Screen{laptop})) {
+		t.Error("a moved dock did not change the screen layout")
+	}
+	if !slices.Equal(screenLayout(nil), screenLayout([]*application.Screen{})) {
+		t.Error("empty screen layouts differ")
+	}
+}
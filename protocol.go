@@ -0,0 +1,153 @@
+package display
+
+// OpenWindowRequest is the payload of the "window.open" action. It mirrors
+// the keys read by `handleOpenWindowAction`.
+type OpenWindowRequest struct {
+	Name         string             `json:"name,omitempty"`
+	Options      *OpenWindowOptions `json:"options,omitempty"`
+	Template     string             `json:"template,omitempty"`
+	Parent       string             `json:"parent,omitempty"`
+	Modal        bool               `json:"modal,omitempty"`
+	Screen       string             `json:"screen,omitempty"`
+	Placement    Placement          `json:"placement,omitempty"`
+	Navigation   *NavigationPolicy  `json:"navigation,omitempty"`
+	Capabilities []Capability       `json:"capabilities,omitempty"`
+}
+
+// OpenWindowOptions are the window options accepted in an
+// `OpenWindowRequest`. The keys keep the Wails field names.
+type OpenWindowOptions struct {
+	Title  string `json:"Title,omitempty"`
+	Width  int    `json:"Width,omitempty"`
+	Height int    `json:"Height,omitempty"`
+}
+
+// FileDialogRequest is the payload of the "dialog.openFile" and
+// "dialog.saveFile" actions.
+type FileDialogRequest struct {
+	Title    string             `json:"title,omitempty"`
+	Filename string             `json:"filename,omitempty"`
+	Multiple bool               `json:"multiple,omitempty"`
+	Filters  []FileDialogFilter `json:"filters,omitempty"`
+}
+
+// FileDialogFilter restricts a file dialog to matching files.
+type FileDialogFilter struct {
+	// Name is shown to the user, such as "Images (*.png, *.jpg)".
+	Name string `json:"name"`
+	// Pattern is a semicolon separated list of globs, such as "*.png;*.jpg".
+	Pattern string `json:"pattern"`
+}
+
+// TrayUpdateRequest is the payload of the "tray.update" action. Only the
+// fields that are set are changed.
+type TrayUpdateRequest struct {
+	Tooltip *string `json:"tooltip,omitempty"`
+	Label   *string `json:"label,omitempty"`
+}
+
+// ClipboardReadRequest is the payload of the "clipboard.read" action.
+type ClipboardReadRequest struct {
+	// Formats to read. All supported formats are read if it is empty.
+	Formats []ClipboardFormat `json:"formats,omitempty"`
+}
+
+// ClipboardWriteRequest is the payload of the "clipboard.write" action.
+type ClipboardWriteRequest struct {
+	ClipboardContent
+	// ClearAfterMs clears the content from the clipboard after this many
+	// milliseconds, unless something else has been copied since.
+	ClearAfterMs int64 `json:"clearAfterMs,omitempty"`
+}
+
+// NavigationRequest is the data of an `EventNavigationRequest`.
+type NavigationRequest struct {
+	URL string `json:"url"`
+}
+
+// ActionSpec describes an action for generated bindings and schemas.
+type ActionSpec struct {
+	// Name is the action name sent in an `ActionRequest`.
+	Name string
+	// Key names the action in generated code, such as "WindowOpen".
+	Key         string
+	Capability  Capability
+	Description string
+	// Payload and Result are zero values of the action's payload and result
+	// types. A nil Result means the action returns nothing.
+	Payload any
+	Result  any
+}
+
+// EventSpec describes an event for generated bindings and schemas.
+type EventSpec struct {
+	// Name is the event name on the action bus.
+	Name string
+	// Key names the event in generated code, such as "Ready".
+	Key         string
+	Description string
+	// Data is a zero value of the event's data type, or nil for events
+	// without data.
+	Data any
+}
+
+// ProtocolActions describes the built-in actions.
+func ProtocolActions() []ActionSpec {
+	return []ActionSpec{
+		{Name: ActionNameWindowOpen, Key: "WindowOpen", Capability: CapWindowOpen, Payload: OpenWindowRequest{},
+			Description: "Opens a window. Its capabilities are narrowed to those of the calling window."},
+		{Name: ActionNameDialogOpenFile, Key: "DialogOpenFile", Capability: CapDialogFile, Payload: FileDialogRequest{}, Result: []string{},
+			Description: "Shows a file open dialog and returns the chosen paths."},
+		{Name: ActionNameDialogSaveFile, Key: "DialogSaveFile", Capability: CapDialogFile, Payload: FileDialogRequest{}, Result: "",
+			Description: "Shows a file save dialog and returns the chosen path."},
+		{Name: ActionNameNotify, Key: "Notify", Capability: CapNotify, Payload: Notification{}, Result: uint32(0),
+			Description: "Shows a desktop notification and returns its ID."},
+		{Name: ActionNameTrayUpdate, Key: "TrayUpdate", Capability: CapTrayUpdate, Payload: TrayUpdateRequest{},
+			Description: "Changes the system tray tooltip and label."},
+		{Name: ActionNameClipboardRead, Key: "ClipboardRead", Capability: CapClipboardRead, Payload: ClipboardReadRequest{}, Result: ClipboardContent{},
+			Description: "Reads the clipboard as text, HTML and PNG. HTML and images are only supported on Linux."},
+		{Name: ActionNameClipboardWrite, Key: "ClipboardWrite", Capability: CapClipboardWrite, Payload: ClipboardWriteRequest{},
+			Description: "Copies text, HTML or a PNG image, optionally clearing it again after a delay. HTML and images are only supported on Linux."},
+		{Name: ActionNameClipboardClear, Key: "ClipboardClear", Capability: CapClipboardWrite,
+			Description: "Empties the clipboard."},
+		{Name: ActionNameContextMenu, Key: "ContextMenuOpen", Payload: ContextMenuRequest{},
+			Description: "Opens a context menu registered in Go at the cursor, evaluating its items against the context."},
+		{Name: ActionNameRecentList, Key: "RecentList", Capability: CapRecent, Result: []RecentItem{},
+			Description: "Returns the recently opened items, pinned items first."},
+		{Name: ActionNameRecentAdd, Key: "RecentAdd", Capability: CapRecent, Payload: RecentItem{},
+			Description: "Records that an item was opened, moving it to the top of the recent items."},
+		{Name: ActionNameRecentPin, Key: "RecentPin", Capability: CapRecent, Payload: RecentPinRequest{},
+			Description: "Pins or unpins a recent item. Pinned items are never dropped to make room."},
+		{Name: ActionNameRecentRemove, Key: "RecentRemove", Capability: CapRecent, Payload: RecentRemoveRequest{},
+			Description: "Removes a recent item."},
+		{Name: ActionNameRecentClear, Key: "RecentClear", Capability: CapRecent,
+			Description: "Removes every unpinned recent item."},
+	}
+}
+
+// ProtocolEvents describes the events on the action bus.
+func ProtocolEvents() []EventSpec {
+	return []EventSpec{
+		{Name: EventActionDenied, Key: "ActionDenied", Data: ActionDenied{}, Description: "An action was refused for lack of a capability. Sent to the window that asked for it."},
+		{Name: EventReady, Key: "Ready", Description: "The main window has finished loading. Emitted by frontends."},
+		{Name: EventNavigationRequest, Key: "NavigationRequest", Data: NavigationRequest{}, Description: "A window with a navigation policy asked to load another page. Emitted by the navigation guard."},
+		{Name: EventNavigationBlocked, Key: "NavigationBlocked", Data: ActionNavigationBlocked{}, Description: "A navigation was blocked by a window's policy."},
+		{Name: EventNotificationAction, Key: "NotificationAction", Data: ActionNotificationAction{}, Description: "A notification action button was clicked."},
+		{Name: EventShortcut, Key: "Shortcut", Data: ActionShortcut{}, Description: "A keyboard shortcut was triggered."},
+		{Name: EventShortcutsChanged, Key: "ShortcutsChanged", Data: []Shortcut{}, Description: "The keymap changed."},
+		{Name: EventSecondInstance, Key: "SecondInstance", Data: ActionSecondInstance{}, Description: "A second launch forwarded its arguments."},
+		{Name: EventClipboardChanged, Key: "ClipboardChanged", Data: ClipboardChange{}, Description: "The clipboard changed. Sent to windows granted \"clipboard:read\"."},
+		{Name: EventFileDrop, Key: "FileDrop", Data: FileDrop{}, Description: "Files were dropped onto the window. Sent to that window only."},
+		{Name: EventContextMenuClick, Key: "ContextMenuClick", Data: ContextMenuClick{}, Description: "A context menu item was clicked. Sent to the window that opened the menu."},
+		{Name: EventLocaleChanged, Key: "LocaleChanged", Data: ActionLocaleChanged{}, Description: "The display locale changed."},
+		{Name: EventRecentChanged, Key: "RecentChanged", Data: []RecentItem{}, Description: "The recent items changed. Sent to windows granted \"recent\"."},
+		{Name: EventRecentOpen, Key: "RecentOpen", Data: RecentItem{}, Description: "A recent item was chosen from a menu. Sent to windows granted \"recent\"."},
+		{Name: EventDeepLink, Key: "DeepLink", Data: DeepLink{}, Description: "A custom URL scheme link was handled."},
+	}
+}
+
+// protocolTypes are types described in generated bindings and schemas even
+// though no action or event carries them directly.
+func protocolTypes() []any {
+	return []any{WindowConfig{}, ActionRequest{}, ActionReply{}}
+}
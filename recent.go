@@ -0,0 +1,389 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ErrUnknownRecent is returned when a recent item ID is not in the list.
+var ErrUnknownRecent = errors.New("display: unknown recent item")
+
+// ErrInvalidRecent is returned when a recent item is added without an ID.
+var ErrInvalidRecent = errors.New("display: invalid recent item")
+
+// DefaultMaxRecent is the number of recent items kept when
+// `Options.MaxRecent` is not set.
+const DefaultMaxRecent = 10
+
+// MenuIDOpenRecent is the ID of the "Open Recent" submenu of the application
+// menu.
+const MenuIDOpenRecent = "recent"
+
+// RecentKind is what a recent item reopens.
+type RecentKind string
+
+// Recent item kinds.
+const (
+	RecentWorkspace RecentKind = "workspace"
+	RecentWindow    RecentKind = "window"
+	RecentDocument  RecentKind = "document"
+)
+
+// RecentItem is an entry of the recently opened list.
+//
+// example:
+//
+//	item := display.RecentItem{
+//		ID:   "/home/user/notes.md",
+//		Kind: display.RecentDocument,
+//		Path: "/home/user/notes.md",
+//	}
+type RecentItem struct {
+	// ID identifies the item. Adding an item with the ID of an existing one
+	// moves it to the top of the list.
+	ID   string     `json:"id"`
+	Kind RecentKind `json:"kind,omitempty"`
+	// Label is shown in menus as it is, without translation. It defaults to
+	// the base name of Path, or ID.
+	Label string `json:"label,omitempty"`
+	Path  string `json:"path,omitempty"`
+	// Pinned items are listed first and are never dropped to make room.
+	Pinned   bool      `json:"pinned,omitempty"`
+	OpenedAt time.Time `json:"openedAt,omitzero"`
+}
+
+// label returns the text shown for the item in menus.
+func (r RecentItem) label() string {
+	switch {
+	case r.Label != "":
+		return r.Label
+	case r.Path != "":
+		return filepath.Base(r.Path)
+	}
+	return r.ID
+}
+
+// RecentPinRequest is the payload of the "recent.pin" action.
+type RecentPinRequest struct {
+	ID     string `json:"id"`
+	Pinned bool   `json:"pinned"`
+}
+
+// RecentRemoveRequest is the payload of the "recent.remove" action.
+type RecentRemoveRequest struct {
+	ID string `json:"id"`
+}
+
+// Recent returns the recently opened items, pinned items first, each group
+// most recent first.
+func (s *Service) Recent() []RecentItem {
+	s.recentMu.Lock()
+	defer s.recentMu.Unlock()
+	return sortRecent(s.recent)
+}
+
+// AddRecent records that an item was opened, moving it to the top of the
+// list. An existing item keeps its pin. The oldest unpinned items are dropped
+// beyond `Options.MaxRecent`.
+//
+// example:
+//
+//	err := displayService.AddRecent(display.RecentItem{
+//		ID:    "workspace:acme",
+//		Kind:  display.RecentWorkspace,
+//		Label: "Acme",
+//	})
+func (s *Service) AddRecent(item RecentItem) error {
+	if item.ID == "" {
+		return fmt.Errorf("%w: missing ID", ErrInvalidRecent)
+	}
+	if item.OpenedAt.IsZero() {
+		item.OpenedAt = time.Now()
+	}
+	return s.changeRecent(func(recent []RecentItem) ([]RecentItem, error) {
+		if i := recentIndex(recent, item.ID); i >= 0 {
+			item.Pinned = item.Pinned || recent[i].Pinned
+			recent = slices.Delete(recent, i, i+1)
+		}
+		return s.trimRecent(slices.Insert(recent, 0, item)), nil
+	})
+}
+
+// PinRecent pins or unpins an item.
+func (s *Service) PinRecent(id string, pinned bool) error {
+	return s.changeRecent(func(recent []RecentItem) ([]RecentItem, error) {
+		i := recentIndex(recent, id)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRecent, id)
+		}
+		recent[i].Pinned = pinned
+		return s.trimRecent(recent), nil
+	})
+}
+
+// RemoveRecent removes an item, pinned or not.
+func (s *Service) RemoveRecent(id string) error {
+	return s.changeRecent(func(recent []RecentItem) ([]RecentItem, error) {
+		i := recentIndex(recent, id)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRecent, id)
+		}
+		return slices.Delete(recent, i, i+1), nil
+	})
+}
+
+// ClearRecent removes every unpinned item. It backs the "Clear Recent" menu
+// item.
+func (s *Service) ClearRecent() error {
+	return s.changeRecent(func(recent []RecentItem) ([]RecentItem, error) {
+		return slices.DeleteFunc(recent, func(r RecentItem) bool { return !r.Pinned }), nil
+	})
+}
+
+// changeRecent applies fn to a copy of the list, then saves the result,
+// updates the menus and tells frontends.
+func (s *Service) changeRecent(fn func([]RecentItem) ([]RecentItem, error)) error {
+	s.recentMu.Lock()
+	recent, err := fn(slices.Clone(s.recent))
+	if err != nil {
+		s.recentMu.Unlock()
+		return err
+	}
+	s.recent = recent
+	err = writeStateFile(s.recentPath(), "recent items", s.recent)
+	version, sorted := s.recentSnapshotLocked(true)
+	s.recentMu.Unlock()
+
+	s.renderRecent(version, sorted)
+	s.announceRecent(EventRecentChanged, sorted)
+	return err
+}
+
+// trimRecent drops the oldest unpinned items that do not fit in the maximum
+// size, which pinned items count towards.
+func (s *Service) trimRecent(recent []RecentItem) []RecentItem {
+	limit := s.config.MaxRecent
+	if limit <= 0 {
+		limit = DefaultMaxRecent
+	}
+	for _, r := range recent {
+		if r.Pinned {
+			limit--
+		}
+	}
+	return slices.DeleteFunc(recent, func(r RecentItem) bool {
+		if r.Pinned {
+			return false
+		}
+		limit--
+		return limit < 0
+	})
+}
+
+// recentIndex returns the index of the item with the given ID, or -1.
+func recentIndex(recent []RecentItem, id string) int {
+	return slices.IndexFunc(recent, func(r RecentItem) bool { return r.ID == id })
+}
+
+// sortRecent returns a copy of the list, most recent first with pinned items
+// ahead of the rest.
+func sortRecent(recent []RecentItem) []RecentItem {
+	sorted := slices.Clone(recent)
+	slices.SortStableFunc(sorted, func(a, b RecentItem) int {
+		if a.Pinned != b.Pinned {
+			if a.Pinned {
+				return -1
+			}
+			return 1
+		}
+		return b.OpenedAt.Compare(a.OpenedAt)
+	})
+	if sorted == nil {
+		sorted = []RecentItem{}
+	}
+	return sorted
+}
+
+// openRecent moves an item chosen from a menu to the top of the list, tells
+// frontends and calls `Options.OnOpenRecent`.
+func (s *Service) openRecent(item RecentItem) {
+	item.OpenedAt = time.Now()
+	if err := s.AddRecent(item); err != nil && s.app != nil {
+		s.app.Logger.Warn("Failed to save recent items", "error", err)
+	}
+	s.announceRecent(EventRecentOpen, item)
+	if s.config.OnOpenRecent != nil {
+		s.config.OnOpenRecent(item)
+	}
+}
+
+// recentMenuItems returns the content of an "Open Recent" submenu: the
+// items, a separator after the pinned ones, and "Clear Recent".
+func (s *Service) recentMenuItems(recent []RecentItem) []AppMenuItem {
+	var items []AppMenuItem
+	for i, r := range recent {
+		if i > 0 && recent[i-1].Pinned && !r.Pinned {
+			items = append(items, AppMenuItem{Separator: true})
+		}
+		items = append(items, AppMenuItem{
+			ID:           MenuIDOpenRecent + ":" + r.ID,
+			Label:        r.label(),
+			Untranslated: true,
+			OnClick:      func() { s.openRecent(r) },
+		})
+	}
+	if len(items) > 0 {
+		items = append(items, AppMenuItem{Separator: true})
+	}
+	return append(items, AppMenuItem{
+		ID:       MenuIDOpenRecent + ".clear",
+		Label:    "Clear Recent",
+		Disabled: !slices.ContainsFunc(recent, func(r RecentItem) bool { return !r.Pinned }),
+		OnClick: func() {
+			if err := s.ClearRecent(); err != nil && s.app != nil {
+				s.app.Logger.Warn("Failed to save recent items", "error", err)
+			}
+		},
+	})
+}
+
+// recentSnapshotLocked returns the sorted list for the menus with its
+// version, counting a change first if changed is set. The caller must hold
+// s.recentMu.
+func (s *Service) recentSnapshotLocked(changed bool) (uint64, []RecentItem) {
+	if changed {
+		s.recentVersion++
+	}
+	return s.recentVersion, sortRecent(s.recent)
+}
+
+// renderRecent rebuilds the "Open Recent" submenus of the application menu
+// and the system tray from a snapshot taken by `recentSnapshotLocked`. The
+// caller must not hold s.recentMu. A snapshot older than the one a menu
+// shows is dropped, so that concurrent changes cannot leave a stale list
+// behind.
+func (s *Service) renderRecent(version uint64, recent []RecentItem) {
+	items := s.recentMenuItems(recent)
+	_ = s.appMenu.Update(func(b *MenuBatch) error {
+		if version < s.recentMenuVersion {
+			return nil
+		}
+		s.recentMenuVersion = version
+		if err := b.Clear(MenuIDOpenRecent); err != nil {
+			// The app removed the submenu.
+			return nil
+		}
+		return b.Append(MenuIDOpenRecent, items...)
+	})
+	// Native menus may only be changed on the main thread.
+	s.onMainThread(func() {
+		s.recentMu.Lock()
+		tray := s.trayRecent
+		stale := version < s.recentTrayVersion
+		if !stale {
+			s.recentTrayVersion = version
+		}
+		s.recentMu.Unlock()
+		if tray == nil || stale {
+			return
+		}
+		var old []*application.MenuItem
+		for i := 0; tray.ItemAt(i) != nil; i++ {
+			old = append(old, tray.ItemAt(i))
+		}
+		s.forgetMenuItems(old)
+		tray.Destroy()
+		for _, item := range items {
+			if item.Separator {
+				tray.AddSeparator()
+				continue
+			}
+			onClick := item.OnClick
+			trayItem := tray.Add(item.Label)
+			if !item.Untranslated {
+				s.localise(trayItem, item.Label)
+			}
+			trayItem.SetEnabled(!item.Disabled).
+				OnClick(func(*application.Context) { onClick() })
+		}
+		if s.app != nil {
+			s.trayMenu.Update()
+		}
+	})
+}
+
+// announceRecent sends a recent items event to every window that may see
+// them.
+func (s *Service) announceRecent(name string, data any) {
+	if s.app == nil {
+		return
+	}
+	for _, window := range s.app.Window.GetAll() {
+		if s.hasCapability(window.ID(), CapRecent) {
+			window.DispatchWailsEvent(&application.CustomEvent{Name: name, Data: data})
+		}
+	}
+}
+
+// recentPath returns the recent items file configured in Options, falling
+// back to the user's config directory.
+func (s *Service) recentPath() string {
+	return statePath(s.config.RecentPath, "recent.json")
+}
+
+// loadRecent reads the recent items file. A missing file is not an error.
+func (s *Service) loadRecent() error {
+	var recent []RecentItem
+	if err := readStateFile(s.recentPath(), "recent items", &recent); err != nil {
+		return err
+	}
+	s.recentMu.Lock()
+	s.recent = s.trimRecent(recent)
+	version, sorted := s.recentSnapshotLocked(true)
+	s.recentMu.Unlock()
+	s.renderRecent(version, sorted)
+	return nil
+}
+
+// recentListAction returns the recent items.
+func (s *Service) recentListAction(context.Context, ActionCall) (any, error) {
+	return s.Recent(), nil
+}
+
+// recentAddAction records an item opened by a frontend.
+func (s *Service) recentAddAction(_ context.Context, call ActionCall) (any, error) {
+	var item RecentItem
+	if err := call.Decode(&item); err != nil {
+		return nil, err
+	}
+	return nil, s.AddRecent(item)
+}
+
+// recentPinAction pins or unpins an item.
+func (s *Service) recentPinAction(_ context.Context, call ActionCall) (any, error) {
+	var req RecentPinRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	return nil, s.PinRecent(req.ID, req.Pinned)
+}
+
+// recentRemoveAction removes an item.
+func (s *Service) recentRemoveAction(_ context.Context, call ActionCall) (any, error) {
+	var req RecentRemoveRequest
+	if err := call.Decode(&req); err != nil {
+		return nil, err
+	}
+	return nil, s.RemoveRecent(req.ID)
+}
+
+// recentClearAction removes every unpinned item.
+func (s *Service) recentClearAction(context.Context, ActionCall) (any, error) {
+	return nil, s.ClearRecent()
+}
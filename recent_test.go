@@ -0,0 +1,197 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func newTestRecent(t *testing.T, max int) (*Service, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recent.json")
+	s, _ := NewWithOptions(Options{RecentPath: path, MaxRecent: max})
+	return s, path
+}
+
+// recentIDs returns the IDs of the recent items in order.
+func recentIDs(items []RecentItem) []string {
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestRecentOrderAndPinning(t *testing.T) {
+	s, path := newTestRecent(t, 3)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c"} {
+		if err := s.AddRecent(RecentItem{ID: id, OpenedAt: base.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.PinRecent("a", true); err != nil {
+		t.Fatal(err)
+	}
+	s.AddRecent(RecentItem{ID: "d", OpenedAt: base.Add(time.Hour)})
+	s.AddRecent(RecentItem{ID: "e", OpenedAt: base.Add(2 * time.Hour)})
+	if got, want := recentIDs(s.Recent()), []string{"a", "e", "d"}; !slices.Equal(got, want) {
+		t.Errorf("Recent() = %q, want %q", got, want)
+	}
+
+	// Adding an item again moves it up and keeps its pin.
+	s.AddRecent(RecentItem{ID: "a", Label: "Acme", OpenedAt: base.Add(3 * time.Hour)})
+	if item := s.Recent()[0]; item.ID != "a" || !item.Pinned || item.Label != "Acme" {
+		t.Errorf("re-added item = %+v", item)
+	}
+
+	if err := s.ClearRecent(); err != nil {
+		t.Fatal(err)
+	}
+	if got := recentIDs(s.Recent()); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("Recent() after ClearRecent = %q, want the pinned item", got)
+	}
+	if err := s.PinRecent("missing", true); !errors.Is(err, ErrUnknownRecent) {
+		t.Errorf("PinRecent(missing) error = %v", err)
+	}
+	if err := s.AddRecent(RecentItem{Label: "no ID"}); !errors.Is(err, ErrInvalidRecent) {
+		t.Errorf("AddRecent without ID error = %v", err)
+	}
+
+	reloaded, _ := NewWithOptions(Options{RecentPath: path})
+	if err := reloaded.loadRecent(); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Recent(); len(got) != 1 || got[0].Label != "Acme" || !got[0].Pinned {
+		t.Errorf("reloaded Recent() = %+v", got)
+	}
+}
+
+func TestRecentMenus(t *testing.T) {
+	var opened []RecentItem
+	s, _ := NewWithOptions(Options{RecentPath: filepath.Join(t.TempDir(), "recent.json"),
+		OnOpenRecent: func(item RecentItem) { opened = append(opened, item) }})
+	menu := application.NewMenu()
+	s.Menu().attach(menu)
+	trayRecent := application.NewMenu()
+	s.trayRecent = trayRecent
+
+	recent := menu.FindByLabel("Open Recent").GetSubmenu()
+	if got := labels(recent); !slices.Equal(got, []string{"Clear Recent"}) || recent.ItemAt(0).Enabled() {
+		t.Fatalf("empty Open Recent = %q, want a disabled Clear Recent", got)
+	}
+
+	s.AddRecent(RecentItem{ID: "notes", Path: "/home/user/notes.md"})
+	s.AddRecent(RecentItem{ID: "workspace:acme", Kind: RecentWorkspace, Label: "Acme", Pinned: true})
+	recent = menu.FindByLabel("Open Recent").GetSubmenu()
+	for name, m := range map[string]*application.Menu{"app": recent, "tray": trayRecent} {
+		if got, want := labels(m), []string{"Acme", "", "notes.md", "", "Clear Recent"}; len(got) != len(want) || got[0] != want[0] || got[2] != want[2] || got[4] != want[4] {
+			t.Errorf("%s Open Recent = %q, want %q", name, got, want)
+		}
+	}
+
+	s.Menu().click(s.Menu().ids[MenuIDOpenRecent+":notes"])
+	if len(opened) != 1 || opened[0].ID != "notes" {
+		t.Errorf("OnOpenRecent got %+v", opened)
+	}
+	s.Menu().click(s.Menu().ids[MenuIDOpenRecent+".clear"])
+	if got := labels(trayRecent); len(got) != 3 || got[0] != "Acme" || trayRecent.ItemAt(2).Enabled() {
+		t.Errorf("tray Open Recent after Clear Recent = %q, want the pinned item and a disabled Clear Recent", got)
+	}
+}
+
+func TestRecentMenusOnMainThread(t *testing.T) {
+	s, _ := newTestRecent(t, 50)
+	menu := application.NewMenu()
+	s.Menu().attach(menu)
+	trayRecent := application.NewMenu()
+	s.trayRecent = trayRecent
+	s.mainThread = fakeMainThread(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.AddRecent(RecentItem{ID: fmt.Sprint(i), Label: fmt.Sprint(i), OpenedAt: time.Unix(int64(i), 0)})
+			}()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("changing recent items deadlocked")
+	}
+
+	// Whatever order the changes were shown in, the menus end on the last.
+	want := labels(trayRecent)
+	if len(want) != 22 || want[0] != "19" {
+		t.Errorf("tray Open Recent = %q, want all 20 items, newest first", want)
+	}
+	if got := labels(menu.FindByLabel("Open Recent").GetSubmenu()); !slices.Equal(got, want) {
+		t.Errorf("app Open Recent = %q, want %q", got, want)
+	}
+}
+
+func TestRecentActions(t *testing.T) {
+	s, _ := newTestRecent(t, 0)
+	s.grantCapabilities("main", 1, []Capability{CapRecent})
+	s.grantCapabilities("tray", 2, trayCapabilities)
+	s.grantCapabilities("viewer", 3, nil)
+	ctx := context.Background()
+
+	// Recent items are not among the default capabilities.
+	if reply := s.Call(ctx, "viewer", ActionRequest{Action: ActionNameRecentAdd, Payload: map[string]any{"id": "doc", "kind": "document"}}); reply.Error == nil || reply.Error.Code != ActionErrorPermissionDenied {
+		t.Errorf("recent.add from viewer = %+v, want permission denied", reply)
+	}
+	if reply := s.Call(ctx, "main", ActionRequest{Action: ActionNameRecentAdd, Payload: map[string]any{"id": "doc", "kind": "document"}}); reply.Error != nil {
+		t.Fatalf("recent.add = %+v", reply.Error)
+	}
+	if reply := s.Call(ctx, "main", ActionRequest{Action: ActionNameRecentPin, Payload: map[string]any{"id": "doc", "pinned": true}}); reply.Error != nil {
+		t.Fatalf("recent.pin = %+v", reply.Error)
+	}
+	reply := s.Call(ctx, "main", ActionRequest{Action: ActionNameRecentList})
+	if got, _ := reply.Result.([]RecentItem); len(got) != 1 || !got[0].Pinned || got[0].Kind != RecentDocument {
+		t.Errorf("recent.list = %+v", reply)
+	}
+	if reply := s.Call(ctx, "tray", ActionRequest{Action: ActionNameRecentList}); reply.Error == nil || reply.Error.Code != ActionErrorPermissionDenied {
+		t.Errorf("recent.list from the tray = %+v, want permission denied", reply)
+	}
+	if reply := s.Call(ctx, "main", ActionRequest{Action: ActionNameRecentRemove, Payload: map[string]any{"id": "doc"}}); reply.Error != nil || len(s.Recent()) != 0 {
+		t.Errorf("recent.remove = %+v, items %+v", reply.Error, s.Recent())
+	}
+}
+
+func TestRecentLabelsAreNotTranslated(t *testing.T) {
+	s, _ := newTestRecent(t, 0)
+	menu := application.NewMenu()
+	s.Menu().attach(menu)
+	trayRecent := application.NewMenu()
+	s.trayRecent = trayRecent
+	s.AddCatalogue("de", map[string]string{"Clear Recent": "Verlauf löschen", "Settings": "Einstellungen"})
+	s.SetLocale("de")
+
+	// A file named like a catalogue message keeps its name.
+	s.AddRecent(RecentItem{ID: "settings", Label: "Settings"})
+	for name, m := range map[string]*application.Menu{"app": menu.FindByLabel("Open Recent").GetSubmenu(), "tray": trayRecent} {
+		if got := labels(m); len(got) != 3 || got[0] != "Settings" || got[2] != "Verlauf löschen" {
+			t.Errorf("%s Open Recent = %q, want Settings untranslated", name, got)
+		}
+	}
+	s.SetLocale("en")
+	s.SetLocale("de")
+	if got := trayRecent.ItemAt(0).Label(); got != "Settings" {
+		t.Errorf("tray label after a locale change = %q, want Settings", got)
+	}
+}
@@ -0,0 +1,189 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// Kinds of `RecordedEvent`.
+const (
+	// RecordAction is an action invoked through the dispatcher.
+	RecordAction = "action"
+	// RecordWindow is a window event, such as a move or focus change.
+	RecordWindow = "window"
+)
+
+// RecordedEvent is one line of a recording made with `Options.RecordPath` or
+// `Service.StartRecording`.
+type RecordedEvent struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	// Window is the calling window of an action, or the window an event
+	// happened to.
+	Window string `json:"window"`
+
+	// ID, Action and Payload describe a recorded action.
+	ID      string         `json:"id,omitempty"`
+	Action  string         `json:"action,omitempty"`
+	Payload map[string]any `json:"payload,omitempty"`
+
+	// Event names a recorded window event: "open" for a window opened with
+	// `OpenWindow`, or one of the keys of `recordedWindowEvents`. Bounds are
+	// the window's bounds after it.
+	Event  string            `json:"event,omitempty"`
+	Bounds *application.Rect `json:"bounds,omitempty"`
+	// URL is the page an opened window loaded.
+	URL string `json:"url,omitempty"`
+}
+
+// recordOpenEvent is the `RecordedEvent.Event` of a window opened with
+// `OpenWindow`, including "main" at startup.
+const recordOpenEvent = "open"
+
+// recordedWindowEvents are the window events written to recordings, by the
+// name used in the file.
+var recordedWindowEvents = map[string]events.WindowEventType{
+	"focus":        events.Common.WindowFocus,
+	"move":         events.Common.WindowDidMove,
+	"resize":       events.Common.WindowDidResize,
+	"minimise":     events.Common.WindowMinimise,
+	"unminimise":   events.Common.WindowUnMinimise,
+	"maximise":     events.Common.WindowMaximise,
+	"unmaximise":   events.Common.WindowUnMaximise,
+	"fullscreen":   events.Common.WindowFullscreen,
+	"unfullscreen": events.Common.WindowUnFullscreen,
+	"show":         events.Common.WindowShow,
+	"hide":         events.Common.WindowHide,
+	"close":        events.Common.WindowClosing,
+}
+
+// redacted replaces values that must not be written to recordings or traces.
+const redacted = "[redacted]"
+
+// redactPayload returns payload with clipboard contents replaced by
+// `redacted`, so that copied secrets are not written to recordings or
+// traces.
+func redactPayload(action string, payload map[string]any) map[string]any {
+	if action != ActionNameClipboardWrite || payload == nil {
+		return payload
+	}
+	out := make(map[string]any, len(payload))
+	for k, v := range payload {
+		switch k {
+		case "text", "html", "png":
+			v = redacted
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactResult returns result, or `redacted` for what was read from the
+// clipboard.
+func redactResult(action string, result any) any {
+	if action == ActionNameClipboardRead && result != nil {
+		return redacted
+	}
+	return result
+}
+
+// recorder writes recorded events as JSON lines.
+type recorder struct {
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// StartRecording writes every action, opened window and window event to w as
+// JSON lines, one `RecordedEvent` per line, until `StopRecording` is called.
+// The file can be replayed with `Replay`. Recordings include action payloads,
+// so keep them private.
+//
+// example:
+//
+//	f, err := os.OpenFile("session.jsonl", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	displayService.StartRecording(f)
+//	defer displayService.StopRecording()
+func (s *Service) StartRecording(w io.Writer) {
+	s.recorderMu.Lock()
+	defer s.recorderMu.Unlock()
+	s.recorder = &recorder{w: w, enc: json.NewEncoder(w)}
+}
+
+// StopRecording stops recording, closing the writer if it is an io.Closer.
+func (s *Service) StopRecording() error {
+	s.recorderMu.Lock()
+	defer s.recorderMu.Unlock()
+	r := s.recorder
+	s.recorder = nil
+	if r == nil {
+		return nil
+	}
+	if closer, ok := r.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// startRecordingFile records to the file configured in `Options.RecordPath`.
+// Recordings hold action payloads, so the file is only readable by the user.
+func (s *Service) startRecordingFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("display: creating recording: %w", err)
+	}
+	s.StartRecording(f)
+	return nil
+}
+
+// record writes an event to the recording, if one is running.
+func (s *Service) record(event RecordedEvent) {
+	s.recorderMu.Lock()
+	defer s.recorderMu.Unlock()
+	if s.recorder == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if err := s.recorder.enc.Encode(event); err != nil && s.app != nil {
+		s.app.Logger.Warn("Failed to record event", "kind", event.Kind, "error", err)
+	}
+}
+
+// recording reports whether a recording is running.
+func (s *Service) recording() bool {
+	s.recorderMu.Lock()
+	defer s.recorderMu.Unlock()
+	return s.recorder != nil
+}
+
+// recordOpenWindow records a window opened with `OpenWindow`.
+func (s *Service) recordOpenWindow(window application.Window, config *WindowConfig) {
+	if !s.recording() {
+		return
+	}
+	bounds := window.Bounds()
+	s.record(RecordedEvent{Kind: RecordWindow, Window: window.Name(), Event: recordOpenEvent, Bounds: &bounds, URL: config.URL})
+}
+
+// recordWindowEvents records a window's events while a recording is running.
+func (s *Service) recordWindowEvents(window application.Window) {
+	for name, eventType := range recordedWindowEvents {
+		window.OnWindowEvent(eventType, func(*application.WindowEvent) {
+			if !s.recording() {
+				return
+			}
+			bounds := window.Bounds()
+			s.record(RecordedEvent{Kind: RecordWindow, Window: window.Name(), Event: name, Bounds: &bounds})
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package display
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ReplayTarget is what `Replay` drives: a `*Service` running in a real
+// application, or a headless `FakeService`.
+type ReplayTarget interface {
+	Call(ctx context.Context, caller string, req ActionRequest) ActionReply
+	ReplayWindowEvent(event RecordedEvent) error
+}
+
+// ReplayOptions controls `Replay`.
+type ReplayOptions struct {
+	// Speed scales the gaps between recorded events: 1 replays in real time
+	// and 2 twice as fast. Zero replays without waiting.
+	Speed float64
+
+	// OnEvent is called after each event is replayed, with the reply to a
+	// replayed action or the error from a window event.
+	OnEvent func(event RecordedEvent, reply *ActionReply, err error)
+}
+
+// Replay re-drives target from a recording. Actions are replayed one at a
+// time, in order, as if invoked by the window that originally invoked them.
+// Failures of individual events are reported to `ReplayOptions.OnEvent`;
+// Replay only stops early for a malformed recording or a cancelled context.
+//
+// example:
+//
+//	f, err := os.Open("session.jsonl")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	err = display.Replay(ctx, f, displayService, display.ReplayOptions{Speed: 2})
+func Replay(ctx context.Context, r io.Reader, target ReplayTarget, options ReplayOptions) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var last time.Time
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event RecordedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("display: recording line %d: %w", line, err)
+		}
+		if options.Speed > 0 && !last.IsZero() && event.Time.After(last) {
+			wait := time.Duration(float64(event.Time.Sub(last)) / options.Speed)
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if !event.Time.IsZero() {
+			last = event.Time
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var reply *ActionReply
+		var err error
+		switch event.Kind {
+		case RecordAction:
+			r := target.Call(ctx, event.Window, ActionRequest{ID: event.ID, Action: event.Action, Payload: event.Payload})
+			reply = &r
+		case RecordWindow:
+			err = target.ReplayWindowEvent(event)
+		default:
+			err = fmt.Errorf("display: unknown recorded event kind %q", event.Kind)
+		}
+		if options.OnEvent != nil {
+			options.OnEvent(event, reply, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// ReplayWindowEvent applies a recorded window event to an open window. An
+// "open" event opens the window with its recorded URL and bounds, unless it
+// is already open.
+func (s *Service) ReplayWindowEvent(event RecordedEvent) error {
+	window, ok := s.app.Window.GetByName(event.Window)
+	if !ok && event.Event == recordOpenEvent {
+		opts := []WindowOption{WithName(event.Window)}
+		if event.URL != "" {
+			opts = append(opts, WithURL(event.URL))
+		}
+		if event.Bounds != nil {
+			opts = append(opts, WithWidth(event.Bounds.Width), WithHeight(event.Bounds.Height))
+		}
+		if err := s.OpenWindow(opts...); err != nil {
+			return err
+		}
+		window, ok = s.app.Window.GetByName(event.Window)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownWindow, event.Window)
+	}
+	switch event.Event {
+	case "focus":
+		window.Focus()
+	case recordOpenEvent, "move", "resize":
+		if event.Bounds != nil {
+			window.SetBounds(*event.Bounds)
+		}
+	case "minimise":
+		window.Minimise()
+	case "unminimise":
+		window.UnMinimise()
+	case "maximise":
+		window.Maximise()
+	case "unmaximise":
+		window.UnMaximise()
+	case "fullscreen":
+		window.Fullscreen()
+	case "unfullscreen":
+		window.UnFullscreen()
+	case "show":
+		window.Show()
+	case "hide":
+		window.Hide()
+	case "close":
+		window.Close()
+	default:
+		return fmt.Errorf("display: unknown window event %q", event.Event)
+	}
+	return nil
+}
+
+// FakeWindow is the state of a window in a `FakeService`.
+type FakeWindow struct {
+	Bounds     application.Rect
+	Focused    bool
+	Minimised  bool
+	Maximised  bool
+	Fullscreen bool
+	Hidden     bool
+	Closed     bool
+}
+
+// FakeService is a headless `ReplayTarget` for tests and bug triage. It
+// records every call, answers them with an empty result, and tracks the state
+// of the windows it hears about.
+//
+// example:
+//
+//	fake := display.NewFakeService()
+//	err := display.Replay(ctx, f, fake, display.ReplayOptions{})
+//	main, _ := fake.Window("main")
+//	fmt.Println(main.Bounds)
+type FakeService struct {
+	mu      sync.Mutex
+	calls   []RecordedEvent
+	windows map[string]*FakeWindow
+}
+
+// NewFakeService creates an empty headless service.
+func NewFakeService() *FakeService {
+	return &FakeService{windows: map[string]*FakeWindow{}}
+}
+
+// Call records an action. Windows opened with `ActionNameWindowOpen` are
+// added to the fake's windows.
+func (f *FakeService) Call(_ context.Context, caller string, req ActionRequest) ActionReply {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, RecordedEvent{Time: time.Now(), Kind: RecordAction, Window: caller, ID: req.ID, Action: req.Action, Payload: req.Payload})
+	if req.Action == ActionNameWindowOpen {
+		name, _ := req.Payload["name"].(string)
+		if name == "" {
+			name = "main"
+		}
+		f.windowLocked(name)
+	}
+	return ActionReply{ID: req.ID, Action: req.Action}
+}
+
+// ReplayWindowEvent updates the state of the event's window.
+func (f *FakeService) ReplayWindowEvent(event RecordedEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := f.windowLocked(event.Window)
+	if event.Bounds != nil {
+		w.Bounds = *event.Bounds
+	}
+	switch event.Event {
+	case "focus":
+		for _, other := range f.windows {
+			other.Focused = false
+		}
+		w.Focused = true
+	case recordOpenEvent:
+		w.Closed = false
+	case "move", "resize":
+	case "minimise", "unminimise":
+		w.Minimised = event.Event == "minimise"
+	case "maximise", "unmaximise":
+		w.Maximised = event.Event == "maximise"
+	case "fullscreen", "unfullscreen":
+		w.Fullscreen = event.Event == "fullscreen"
+	case "show", "hide":
+		w.Hidden = event.Event == "hide"
+	case "close":
+		w.Closed = true
+	default:
+		return fmt.Errorf("display: unknown window event %q", event.Event)
+	}
+	return nil
+}
+
+// windowLocked returns a window's state, adding it if it is new.
+func (f *FakeService) windowLocked(name string) *FakeWindow {
+	w, ok := f.windows[name]
+	if !ok {
+		w = &FakeWindow{}
+		f.windows[name] = w
+	}
+	return w
+}
+
+// Calls returns the actions the fake has received, in order.
+func (f *FakeService) Calls() []RecordedEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]RecordedEvent{}, f.calls...)
+}
+
+// Window returns the state of a window.
+func (f *FakeService) Window(name string) (FakeWindow, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w, ok := f.windows[name]
+	if !ok {
+		return FakeWindow{}, false
+	}
+	return *w, true
+}
@@ -0,0 +1,109 @@
+package display
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	s, _ := New()
+	s.RegisterAction("test.noop", "", func(context.Context, ActionCall) (any, error) { return nil, nil })
+	var buf bytes.Buffer
+	s.StartRecording(&buf)
+	ctx := context.Background()
+	s.Call(ctx, "main", ActionRequest{ID: "1", Action: "test.noop", Payload: map[string]any{"n": 1.0}})
+	s.record(RecordedEvent{Kind: RecordWindow, Window: "main", Event: "resize", Bounds: &application.Rect{Width: 640, Height: 480}})
+	s.Call(ctx, "main", ActionRequest{Action: ActionNameWindowOpen, Payload: map[string]any{"name": "inspector"}})
+	s.record(RecordedEvent{Kind: RecordWindow, Window: "inspector", Event: "focus"})
+	if err := s.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	s.Call(ctx, "main", ActionRequest{Action: "test.noop"})
+	if lines := strings.Count(buf.String(), "\n"); lines != 4 {
+		t.Fatalf("recorded %d lines, want 4:\n%s", lines, buf.String())
+	}
+
+	fake := NewFakeService()
+	var replayed []string
+	err := Replay(ctx, &buf, fake, ReplayOptions{OnEvent: func(event RecordedEvent, _ *ActionReply, err error) {
+		if err != nil {
+			t.Errorf("replaying %+v: %v", event, err)
+		}
+		replayed = append(replayed, event.Kind)
+	}})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if len(replayed) != 4 {
+		t.Errorf("replayed %v", replayed)
+	}
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0].ID != "1" || calls[0].Payload["n"] != 1.0 {
+		t.Errorf("Calls() = %+v", calls)
+	}
+	if main, _ := fake.Window("main"); main.Bounds.Width != 640 || main.Focused {
+		t.Errorf("main = %+v", main)
+	}
+	if inspector, ok := fake.Window("inspector"); !ok || !inspector.Focused {
+		t.Errorf("inspector = %+v, %v", inspector, ok)
+	}
+}
+
+func TestReplaySpeed(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recording := `{"time":"` + start.Format(time.RFC3339Nano) + `","kind":"window","window":"main","event":"show"}
+{"time":"` + start.Add(time.Second).Format(time.RFC3339Nano) + `","kind":"window","window":"main","event":"hide"}
+`
+	began := time.Now()
+	if err := Replay(context.Background(), strings.NewReader(recording), NewFakeService(), ReplayOptions{Speed: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(began); elapsed < 15*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("Replay() at 50x took %s, want about 20ms", elapsed)
+	}
+}
+
+func TestReplayMalformed(t *testing.T) {
+	err := Replay(context.Background(), strings.NewReader("{\"kind\":\"window\"}\nnot json\n"), NewFakeService(), ReplayOptions{})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Replay() error = %v, want a line 2 error", err)
+	}
+}
+
+func TestStartRecordingFileIsPrivate(t *testing.T) {
+	s, _ := New()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := s.startRecordingFile(path); err != nil {
+		t.Fatal(err)
+	}
+	defer s.StopRecording()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("recording mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestReplayOpenEvent(t *testing.T) {
+	recording := `{"kind":"window","window":"main","event":"open","url":"/","bounds":{"X":0,"Y":0,"Width":1280,"Height":800}}
+{"kind":"window","window":"main","event":"close"}
+{"kind":"window","window":"main","event":"open","url":"/"}
+`
+	fake := NewFakeService()
+	if err := Replay(context.Background(), strings.NewReader(recording), fake, ReplayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if main, ok := fake.Window("main"); !ok || main.Closed || main.Bounds.Width != 1280 {
+		t.Errorf("main = %+v, %v, want an open 1280 wide window", main, ok)
+	}
+}
@@ -0,0 +1,257 @@
+package display
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// schemaID is the `$id` of the protocol schema.
+const schemaID = "https://github.com/Snider/display/schema/display.schema.json"
+
+// jsonSchema is the subset of JSON Schema used to describe the protocol.
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	ID          string                 `json:"$id,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Enum        []any                  `json:"enum,omitempty"`
+	EnumNames   []string               `json:"x-enumNames,omitempty"`
+	AnyOf       []*jsonSchema          `json:"anyOf,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	// AdditionalProperties is a *jsonSchema, or false for closed objects.
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+	Actions              map[string]*actionDoc  `json:"x-actions,omitempty"`
+	Events               map[string]*eventDoc   `json:"x-events,omitempty"`
+
+	// order lists Properties in Go field order for generated code.
+	order []string
+}
+
+// actionDoc describes an action in the protocol schema.
+type actionDoc struct {
+	Key         string      `json:"key"`
+	Description string      `json:"description,omitempty"`
+	Capability  Capability  `json:"capability,omitempty"`
+	Payload     *jsonSchema `json:"payload"`
+	Result      *jsonSchema `json:"result"`
+}
+
+// eventDoc describes an event in the protocol schema.
+type eventDoc struct {
+	Key         string      `json:"key"`
+	Description string      `json:"description,omitempty"`
+	Data        *jsonSchema `json:"data"`
+}
+
+// schemaEnum lists the values of an enumerated type.
+type schemaEnum struct {
+	names  []string
+	values []any
+	// open enums accept values other than the listed ones.
+	open bool
+}
+
+// schemaEnums are the enumerated types of the protocol.
+var schemaEnums = map[reflect.Type]schemaEnum{
+	reflect.TypeFor[application.ButtonState](): {
+		names:  []string{"Enabled", "Disabled", "Hidden"},
+		values: []any{application.ButtonEnabled, application.ButtonDisabled, application.ButtonHidden},
+	},
+	reflect.TypeFor[Placement](): {
+		names:  []string{"Centre", "Remember", "Clamp"},
+		values: []any{PlacementCentre, PlacementRemember, PlacementClamp},
+	},
+	reflect.TypeFor[NavigationAction](): {
+		names:  []string{"Allow", "External", "Block", "Prompt"},
+		values: []any{NavigationAllow, NavigationExternal, NavigationBlock, NavigationPrompt},
+	},
+	reflect.TypeFor[NotificationUrgency](): {
+		names:  []string{"Low", "Normal", "Critical"},
+		values: []any{UrgencyLow, UrgencyNormal, UrgencyCritical},
+	},
+	reflect.TypeFor[ClipboardFormat](): {
+		names:  []string{"Text", "HTML", "PNG"},
+		values: []any{ClipboardText, ClipboardHTML, ClipboardPNG},
+	},
+	reflect.TypeFor[Capability](): {
+		names:  []string{"WindowOpen", "DialogFile", "Notify", "TrayUpdate", "ClipboardRead", "ClipboardWrite", "Recent"},
+		values: []any{CapWindowOpen, CapDialogFile, CapNotify, CapTrayUpdate, CapClipboardRead, CapClipboardWrite, CapRecent},
+		open:   true,
+	},
+}
+
+// schemaBuilder converts Go types to JSON Schema, collecting named types in
+// defs.
+type schemaBuilder struct {
+	defs map[string]*jsonSchema
+}
+
+// ref returns the schema of a value's type, or nil for a nil value.
+func (b *schemaBuilder) ref(v any) *jsonSchema {
+	if v == nil {
+		return nil
+	}
+	return b.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of t, referring to named types by `$ref`.
+func (b *schemaBuilder) schema(t reflect.Type) *jsonSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeFor[time.Duration]():
+		return &jsonSchema{Type: "integer", Description: "Nanoseconds."}
+	case reflect.TypeFor[time.Time]():
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	}
+	if enum, ok := schemaEnums[t]; ok {
+		return b.define(t, func() *jsonSchema { return enumSchema(t, enum) })
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string", Format: "byte"}
+		}
+		return &jsonSchema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return b.define(t, func() *jsonSchema { return b.structSchema(t) })
+	}
+	return &jsonSchema{}
+}
+
+// define adds a named type to defs, once, and returns a reference to it.
+func (b *schemaBuilder) define(t reflect.Type, build func() *jsonSchema) *jsonSchema {
+	name := t.Name()
+	if _, ok := b.defs[name]; !ok {
+		// Reserve the name first so recursive types terminate.
+		b.defs[name] = &jsonSchema{}
+		*b.defs[name] = *build()
+	}
+	return &jsonSchema{Ref: "#/$defs/" + name}
+}
+
+// enumSchema describes an enumerated type.
+func enumSchema(t reflect.Type, enum schemaEnum) *jsonSchema {
+	s := &jsonSchema{Type: "string", Enum: enum.values, EnumNames: enum.names}
+	if t.Kind() != reflect.String {
+		s.Type = "integer"
+	}
+	if enum.open {
+		return &jsonSchema{AnyOf: []*jsonSchema{s, {Type: s.Type}}, EnumNames: enum.names}
+	}
+	return s
+}
+
+// structSchema describes a struct by its JSON encoding. Fields tagged without
+// omitempty are required; untagged fields are optional, as they are decoded
+// leniently by name.
+func (b *schemaBuilder) structSchema(t reflect.Type) *jsonSchema {
+	s := &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}, AdditionalProperties: false}
+	b.addFields(s, t)
+	return s
+}
+
+// addFields adds the JSON fields of t to s, flattening embedded structs.
+func (b *schemaBuilder) addFields(s *jsonSchema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			b.addFields(s, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, exists := s.Properties[name]; !exists {
+			s.order = append(s.order, name)
+		}
+		s.Properties[name] = b.schema(field.Type)
+		optional := strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero")
+		if tagged && !optional && field.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// protocolSchema describes every action, event and protocol type. It is
+// built once and must not be modified.
+var protocolSchema = sync.OnceValue(buildProtocolSchema)
+
+// buildProtocolSchema describes every action, event and protocol type.
+func buildProtocolSchema() *jsonSchema {
+	b := &schemaBuilder{defs: map[string]*jsonSchema{}}
+	doc := &jsonSchema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		ID:          schemaID,
+		Title:       "Core display protocol",
+		Description: "Actions and events exchanged between the display service and its frontends.",
+		Defs:        b.defs,
+		Actions:     map[string]*actionDoc{},
+		Events:      map[string]*eventDoc{},
+	}
+	for _, action := range ProtocolActions() {
+		doc.Actions[action.Name] = &actionDoc{
+			Key:         action.Key,
+			Description: action.Description,
+			Capability:  action.Capability,
+			Payload:     b.ref(action.Payload),
+			Result:      b.ref(action.Result),
+		}
+	}
+	for _, event := range ProtocolEvents() {
+		doc.Events[event.Name] = &eventDoc{Key: event.Key, Description: event.Description, Data: b.ref(event.Data)}
+	}
+	for _, v := range protocolTypes() {
+		b.ref(v)
+	}
+	return doc
+}
+
+// JSONSchema returns the JSON Schema of the display protocol: every built-in
+// action's payload and result, every event's data, and `WindowConfig`.
+//
+// example:
+//
+//	schema, err := display.JSONSchema()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	os.WriteFile("display.schema.json", schema, 0o644)
+func JSONSchema() ([]byte, error) {
+	data, err := json.MarshalIndent(protocolSchema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,87 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		payload any
+		wantErr string
+	}{
+		{name: "Valid window", action: ActionNameWindowOpen, payload: map[string]any{
+			"name": "inspector", "options": map[string]any{"Width": 640.0}, "placement": "centre",
+			"capabilities": []Capability{CapNotify, "wallet:sign"},
+		}},
+		{name: "Wrong type", action: ActionNameWindowOpen, payload: map[string]any{"name": 42.0}, wantErr: "name: expected string"},
+		{name: "Fractional size", action: ActionNameWindowOpen, payload: map[string]any{"options": map[string]any{"Width": 1.5}}, wantErr: "options.Width"},
+		{name: "Unknown placement", action: ActionNameWindowOpen, payload: map[string]any{"placement": "middle"}, wantErr: "placement"},
+		{name: "Unknown field", action: ActionNameDialogOpenFile, payload: map[string]any{"tilte": "Open"}, wantErr: "tilte: unknown field"},
+		{name: "Missing required", action: ActionNameDialogOpenFile, payload: map[string]any{"filters": []any{map[string]any{"name": "Images"}}}, wantErr: "filters[0].pattern: required"},
+		{name: "Button state enum", action: ActionNameNotify, payload: map[string]any{"Urgency": 3.0}, wantErr: "Urgency"},
+		{name: "Custom action", action: "wallet.sign", payload: map[string]any{"anything": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAction(tt.action, tt.payload)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateAction() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidPayload) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateAction() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDispatchValidatesPayload(t *testing.T) {
+	s, _ := New()
+	s.grantCapabilities("main", 1, nil)
+	reply := s.Call(t.Context(), "main", ActionRequest{ID: "1", Action: ActionNameTrayUpdate, Payload: map[string]any{"tooltip": 1.0}})
+	if reply.Error == nil || reply.Error.Code != ActionErrorInvalidPayload {
+		t.Errorf("Call() = %+v, want %q", reply, ActionErrorInvalidPayload)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	data, err := OpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "#/$defs/") {
+		t.Error("OpenAPI() still refers to $defs")
+	}
+	var doc struct {
+		OpenAPI    string                    `json:"openapi"`
+		Paths      map[string]any            `json:"paths"`
+		Components map[string]map[string]any `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" || doc.Paths[SchemaPath] == nil || doc.Components["schemas"]["WindowConfig"] == nil {
+		t.Errorf("OpenAPI() = %s", data)
+	}
+}
+
+func TestSchemaHandler(t *testing.T) {
+	for _, tt := range []struct{ query, contentType, want string }{
+		{"", "application/schema+json", `"$schema"`},
+		{"?format=openapi", "application/vnd.oai.openapi+json", `"openapi": "3.1.0"`},
+	} {
+		rec := httptest.NewRecorder()
+		SchemaHandler().ServeHTTP(rec, httptest.NewRequest("GET", SchemaPath+tt.query, nil))
+		if rec.Code != 200 || rec.Header().Get("Content-Type") != tt.contentType || !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("GET %s = %d %s", tt.query, rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,480 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+var (
+	// ErrShortcutConflict is returned when an accelerator is already bound to
+	// another action in an overlapping scope.
+	ErrShortcutConflict = errors.New("display: shortcut conflict")
+	// ErrInvalidAccelerator is returned when an accelerator cannot be parsed.
+	ErrInvalidAccelerator = errors.New("display: invalid accelerator")
+	// ErrUnknownShortcut is returned when rebinding an action that has not
+	// been registered.
+	ErrUnknownShortcut = errors.New("display: unknown shortcut")
+)
+
+// Shortcut binds a key combination to a named action. A shortcut with an
+// empty Window is app-wide; otherwise it only fires in the named window.
+type Shortcut struct {
+	Action      string `json:"action"`
+	Accelerator string `json:"accelerator"`
+	Default     string `json:"default"`
+	Window      string `json:"window,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// overlaps reports whether two shortcuts could both fire for the same key
+// press.
+func (sc Shortcut) overlaps(other Shortcut) bool {
+	return sc.Window == "" || other.Window == "" || sc.Window == other.Window
+}
+
+// ShortcutHandler is called when a shortcut fires. The window is the one that
+// had focus when the key combination was pressed.
+type ShortcutHandler func(window application.Window)
+
+type shortcutEntry struct {
+	Shortcut
+	handler ShortcutHandler
+}
+
+// ShortcutRegistry holds the keyboard shortcuts known to the display service.
+// User rebinds are persisted to a keymap file so that they survive restarts.
+type ShortcutRegistry struct {
+	mu         sync.RWMutex
+	entries    map[string]*shortcutEntry
+	overrides  map[string]string
+	keymapPath string
+	onChange   func(sc Shortcut, previous string)
+}
+
+// newShortcutRegistry creates an empty registry.
+func newShortcutRegistry() *ShortcutRegistry {
+	return &ShortcutRegistry{
+		entries:   map[string]*shortcutEntry{},
+		overrides: map[string]string{},
+	}
+}
+
+// Register adds a shortcut for an action. If the keymap file contains a
+// rebind for the action it takes precedence over sc.Accelerator, which is
+// kept as the default. An empty accelerator registers the action unbound.
+//
+// example:
+//
+//	err := displayService.Shortcuts().Register(display.Shortcut{
+//		Action:      "workspace.new",
+//		Accelerator: "CmdOrCtrl+Shift+N",
+//		Description: "Create a new workspace",
+//	}, func(window application.Window) {
+//		// ...
+//	})
+func (r *ShortcutRegistry) Register(sc Shortcut, handler ShortcutHandler) error {
+	if sc.Action == "" {
+		return errors.New("display: shortcut action must not be empty")
+	}
+	def, err := normaliseAccelerator(sc.Accelerator)
+	if err != nil {
+		return err
+	}
+	sc.Default = def
+	sc.Accelerator = def
+
+	r.mu.Lock()
+	if override, ok := r.overrides[sc.Action]; ok {
+		if accel, err := normaliseAccelerator(override); err == nil && r.conflictLocked(sc.Action, accel, sc.Window) == nil {
+			sc.Accelerator = accel
+		}
+	}
+	if err := r.conflictLocked(sc.Action, sc.Accelerator, sc.Window); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	previous := ""
+	if existing, ok := r.entries[sc.Action]; ok {
+		previous = existing.Accelerator
+	}
+	r.entries[sc.Action] = &shortcutEntry{Shortcut: sc, handler: handler}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil {
+		onChange(sc, previous)
+	}
+	return nil
+}
+
+// Rebind changes the accelerator of a registered action and saves the change
+// to the keymap file. An empty accelerator unbinds the action.
+//
+// example:
+//
+//	err := displayService.Shortcuts().Rebind("workspace.new", "Ctrl+Alt+N")
+func (r *ShortcutRegistry) Rebind(action, accelerator string) error {
+	return r.rebind(action, accelerator, true)
+}
+
+// rebind changes the accelerator of a registered action, saving the keymap
+// file if save is set.
+func (r *ShortcutRegistry) rebind(action, accelerator string, save bool) error {
+	accel, err := normaliseAccelerator(accelerator)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	entry, ok := r.entries[action]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownShortcut, action)
+	}
+	if err := r.conflictLocked(action, accel, entry.Window); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	previous := entry.Accelerator
+	entry.Accelerator = accel
+	if accel == entry.Default {
+		delete(r.overrides, action)
+	} else {
+		r.overrides[action] = accel
+	}
+	sc := entry.Shortcut
+	onChange := r.onChange
+	if save {
+		err = r.saveLocked()
+	}
+	r.mu.Unlock()
+
+	if onChange != nil && previous != accel {
+		onChange(sc, previous)
+	}
+	return err
+}
+
+// Reset restores the default accelerator of an action.
+func (r *ShortcutRegistry) Reset(action string) error {
+	r.mu.RLock()
+	entry, ok := r.entries[action]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownShortcut, action)
+	}
+	return r.Rebind(action, entry.Default)
+}
+
+// Keymap returns every registered shortcut sorted by action name. Frontends
+// use it to render cheat-sheets.
+func (r *ShortcutRegistry) Keymap() []Shortcut {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keymap := make([]Shortcut, 0, len(r.entries))
+	for _, entry := range r.entries {
+		keymap = append(keymap, entry.Shortcut)
+	}
+	sort.Slice(keymap, func(i, j int) bool {
+		return keymap[i].Action < keymap[j].Action
+	})
+	return keymap
+}
+
+// Lookup returns the shortcut bound to an accelerator in the given window.
+// Window-scoped shortcuts win over app-wide ones.
+func (r *ShortcutRegistry) Lookup(accelerator, window string) (Shortcut, bool) {
+	accel, err := normaliseAccelerator(accelerator)
+	if err != nil || accel == "" {
+		return Shortcut{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var appWide *shortcutEntry
+	for _, entry := range r.entries {
+		if entry.Accelerator != accel {
+			continue
+		}
+		if entry.Window == window {
+			return entry.Shortcut, true
+		}
+		if entry.Window == "" {
+			appWide = entry
+		}
+	}
+	if appWide != nil {
+		return appWide.Shortcut, true
+	}
+	return Shortcut{}, false
+}
+
+// accelerator returns the accelerator currently bound to an action.
+func (r *ShortcutRegistry) accelerator(action string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if entry, ok := r.entries[action]; ok {
+		return entry.Accelerator
+	}
+	return ""
+}
+
+// bound reports whether any action is bound to accel.
+func (r *ShortcutRegistry) bound(accel string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, entry := range r.entries {
+		if entry.Accelerator == accel {
+			return true
+		}
+	}
+	return false
+}
+
+// ensure registers an unbound action if it is not registered yet, so that
+// menu items can be rebound through the keymap without a Go handler.
+func (r *ShortcutRegistry) ensure(action string) {
+	r.mu.Lock()
+	if _, ok := r.entries[action]; ok {
+		r.mu.Unlock()
+		return
+	}
+	sc := Shortcut{Action: action}
+	if override, ok := r.overrides[action]; ok {
+		if accel, err := normaliseAccelerator(override); err == nil && r.conflictLocked(action, accel, "") == nil {
+			sc.Accelerator = accel
+		}
+	}
+	r.entries[action] = &shortcutEntry{Shortcut: sc}
+	r.mu.Unlock()
+}
+
+// handler returns the handler registered for an action.
+func (r *ShortcutRegistry) handler(action string) ShortcutHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if entry, ok := r.entries[action]; ok {
+		return entry.handler
+	}
+	return nil
+}
+
+// conflictLocked returns an error if accel is already bound to a different
+// action in a scope that overlaps window. The caller must hold r.mu.
+func (r *ShortcutRegistry) conflictLocked(action, accel, window string) error {
+	if accel == "" {
+		return nil
+	}
+	candidate := Shortcut{Action: action, Accelerator: accel, Window: window}
+	for _, entry := range r.entries {
+		if entry.Action == action || entry.Accelerator != accel {
+			continue
+		}
+		if candidate.overlaps(entry.Shortcut) {
+			return fmt.Errorf("%w: %s is already bound to %s", ErrShortcutConflict, accel, entry.Action)
+		}
+	}
+	return nil
+}
+
+// LoadKeymap reads user rebinds from a keymap file. A missing file is not an
+// error. Rebinds for actions that are not registered yet are applied when the
+// action is registered. Loading does not rewrite the file.
+func (r *ShortcutRegistry) LoadKeymap(path string) error {
+	r.mu.Lock()
+	r.keymapPath = path
+	r.mu.Unlock()
+
+	var keymap map[string]string
+	if err := readStateFile(path, "keymap", &keymap); err != nil {
+		return err
+	}
+
+	var errs []error
+	for action, accelerator := range keymap {
+		r.mu.Lock()
+		_, registered := r.entries[action]
+		if !registered {
+			r.overrides[action] = accelerator
+		}
+		r.mu.Unlock()
+		if registered {
+			if err := r.rebind(action, accelerator, false); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// saveLocked writes the current rebinds to the keymap file. The caller must
+// hold r.mu.
+func (r *ShortcutRegistry) saveLocked() error {
+	return writeStateFile(r.keymapPath, "keymap", r.overrides)
+}
+
+// acceleratorModifiers maps accepted modifier names to the labels Wails uses
+// when it reports key events on the current platform.
+var acceleratorModifiers = func() map[string]string {
+	cmdOrCtrl, option, super := "Ctrl", "Alt", "Super"
+	switch runtime.GOOS {
+	case "darwin":
+		cmdOrCtrl, option, super = "Cmd", "Option", "Cmd"
+	case "windows":
+		super = "Win"
+	}
+	return map[string]string{
+		"cmdorctrl":   cmdOrCtrl,
+		"cmd":         cmdOrCtrl,
+		"command":     cmdOrCtrl,
+		"ctrl":        "Ctrl",
+		"optionoralt": option,
+		"alt":         option,
+		"option":      option,
+		"shift":       "Shift",
+		"super":       super,
+	}
+}()
+
+// normaliseAccelerator converts an accelerator such as "cmdorctrl+shift+p"
+// into the canonical form Wails uses to look up key bindings, e.g.
+// "Ctrl+Shift+P" on Linux. Equivalent spellings therefore compare equal.
+func normaliseAccelerator(accelerator string) (string, error) {
+	accelerator = strings.TrimSpace(accelerator)
+	if accelerator == "" {
+		return "", nil
+	}
+	parts := strings.Split(accelerator, "+")
+	key := strings.ToLower(strings.TrimSpace(parts[len(parts)-1]))
+	if key == "" && len(parts) > 1 {
+		// "Ctrl++" binds the plus key.
+		parts = parts[:len(parts)-1]
+		key = "+"
+	}
+	if key == "plus" {
+		key = "+"
+	}
+	if len([]rune(key)) != 1 && !isNamedKey(key) {
+		return "", fmt.Errorf("%w: %q is not a valid key", ErrInvalidAccelerator, key)
+	}
+
+	var modifiers []string
+	for _, part := range parts[:len(parts)-1] {
+		label, ok := acceleratorModifiers[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return "", fmt.Errorf("%w: %q is not a valid modifier", ErrInvalidAccelerator, part)
+		}
+		if !slices.Contains(modifiers, label) {
+			modifiers = append(modifiers, label)
+		}
+	}
+	sort.Strings(modifiers)
+	return strings.Join(append(modifiers, strings.ToUpper(key)), "+"), nil
+}
+
+// isNamedKey reports whether key is one of the named keys Wails accepts.
+func isNamedKey(key string) bool {
+	switch key {
+	case "backspace", "tab", "return", "enter", "escape", "left", "right",
+		"up", "down", "space", "delete", "home", "end", "page up", "page down",
+		"numlock":
+		return true
+	}
+	if strings.HasPrefix(key, "f") {
+		var n int
+		if _, err := fmt.Sscanf(key, "f%d", &n); err == nil && n >= 1 && n <= 35 {
+			return fmt.Sprintf("f%d", n) == key
+		}
+	}
+	return false
+}
+
+// Shortcuts returns the service's shortcut registry.
+//
+// example:
+//
+//	err := displayService.Shortcuts().Rebind("workspace.new", "Ctrl+Alt+N")
+func (s *Service) Shortcuts() *ShortcutRegistry {
+	return s.shortcuts
+}
+
+// Keymap returns every registered shortcut so that frontends can render a
+// cheat-sheet.
+func (s *Service) Keymap() []Shortcut {
+	return s.shortcuts.Keymap()
+}
+
+// keymapPath returns the keymap file configured in Options, falling back to
+// the user's config directory.
+func (s *Service) keymapPath() string {
+	return statePath(s.config.KeymapPath, "keymap.json")
+}
+
+// installShortcuts binds every registered shortcut in the running
+// application. It is called once the menu has been built.
+func (s *Service) installShortcuts() {
+	for _, sc := range s.shortcuts.Keymap() {
+		s.syncShortcut(sc, "")
+	}
+}
+
+// syncShortcut updates the application key bindings and menu accelerators
+// after a shortcut has been registered or rebound.
+func (s *Service) syncShortcut(sc Shortcut, previous string) {
+	if s.app == nil {
+		return
+	}
+	if previous != "" && previous != sc.Accelerator {
+		if !s.shortcuts.bound(previous) {
+			s.app.KeyBinding.Remove(previous)
+		}
+	}
+	if sc.Accelerator != "" {
+		accel := sc.Accelerator
+		s.app.KeyBinding.Add(accel, func(window application.Window) {
+			s.triggerShortcut(accel, window)
+		})
+	}
+
+	s.menuItemsMu.Lock()
+	for _, item := range s.menuItems[sc.Action] {
+		if sc.Accelerator == "" {
+			item.RemoveAccelerator()
+		} else {
+			item.SetAccelerator(sc.Accelerator)
+		}
+	}
+	s.menuItemsMu.Unlock()
+
+	s.app.Event.Emit(EventShortcutsChanged, s.shortcuts.Keymap())
+}
+
+// triggerShortcut runs the action bound to accel in the given window.
+func (s *Service) triggerShortcut(accel string, window application.Window) {
+	name := ""
+	if window != nil {
+		name = window.Name()
+	}
+	if sc, ok := s.shortcuts.Lookup(accel, name); ok {
+		s.runShortcutAction(sc.Action, window)
+	}
+}
+
+// runShortcutAction calls the handler for an action and announces it on the
+// action bus so that frontends can react to it too.
+func (s *Service) runShortcutAction(action string, window application.Window) {
+	if handler := s.shortcuts.handler(action); handler != nil {
+		handler(window)
+	}
+	name := ""
+	if window != nil {
+		name = window.Name()
+	}
+	s.app.Event.Emit(EventShortcut, ActionShortcut{Action: action, Window: name})
+}
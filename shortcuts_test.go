@@ -0,0 +1,183 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNormaliseAccelerator(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "ctrl+shift+p", want: "Ctrl+Shift+P"},
+		{in: "Shift+Ctrl+P", want: "Ctrl+Shift+P"},
+		{in: "Ctrl+Ctrl+k", want: "Ctrl+K"},
+		{in: "Ctrl+plus", want: "Ctrl++"},
+		{in: "Ctrl++", want: "Ctrl++"},
+		{in: "Shift+F12", want: "Shift+F12"},
+		{in: "escape", want: "ESCAPE"},
+		{in: "Hyper+K", wantErr: true},
+		{in: "Ctrl+NotAKey", wantErr: true},
+		{in: "Ctrl+F99", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := normaliseAccelerator(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAccelerator) {
+					t.Fatalf("normaliseAccelerator(%q) error = %v, want ErrInvalidAccelerator", tt.in, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normaliseAccelerator(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("normaliseAccelerator(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortcutRegistry_Conflicts(t *testing.T) {
+	r := newShortcutRegistry()
+	if err := r.Register(Shortcut{Action: "palette", Accelerator: "Ctrl+Shift+P"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.Register(Shortcut{Action: "print", Accelerator: "shift+ctrl+p"}, nil)
+	if !errors.Is(err, ErrShortcutConflict) {
+		t.Errorf("app-wide duplicate: error = %v, want ErrShortcutConflict", err)
+	}
+	err = r.Register(Shortcut{Action: "editor.print", Accelerator: "Ctrl+Shift+P", Window: "editor"}, nil)
+	if !errors.Is(err, ErrShortcutConflict) {
+		t.Errorf("window-scoped overlapping app-wide: error = %v, want ErrShortcutConflict", err)
+	}
+
+	if err := r.Register(Shortcut{Action: "editor.save", Accelerator: "Ctrl+S", Window: "editor"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(Shortcut{Action: "settings.save", Accelerator: "Ctrl+S", Window: "settings"}, nil); err != nil {
+		t.Errorf("same accelerator in different windows: error = %v, want nil", err)
+	}
+
+	if sc, ok := r.Lookup("ctrl+s", "settings"); !ok || sc.Action != "settings.save" {
+		t.Errorf("Lookup(ctrl+s, settings) = %v, %v", sc, ok)
+	}
+	if _, ok := r.Lookup("ctrl+s", "main"); ok {
+		t.Error("Lookup(ctrl+s, main) found a window-scoped shortcut from another window")
+	}
+	if sc, ok := r.Lookup("Ctrl+Shift+P", "editor"); !ok || sc.Action != "palette" {
+		t.Errorf("Lookup(Ctrl+Shift+P, editor) = %v, %v", sc, ok)
+	}
+}
+
+func TestShortcutRegistry_RebindPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "display", "keymap.json")
+
+	r := newShortcutRegistry()
+	if err := r.LoadKeymap(path); err != nil {
+		t.Fatalf("LoadKeymap() on missing file error = %v", err)
+	}
+	if err := r.Register(Shortcut{Action: "workspace.new", Accelerator: "Ctrl+N"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(Shortcut{Action: "workspace.list", Accelerator: "Ctrl+L"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Rebind("workspace.new", "Ctrl+L"); !errors.Is(err, ErrShortcutConflict) {
+		t.Errorf("Rebind() onto a bound accelerator error = %v, want ErrShortcutConflict", err)
+	}
+	if err := r.Rebind("missing", "Ctrl+M"); !errors.Is(err, ErrUnknownShortcut) {
+		t.Errorf("Rebind() unknown action error = %v, want ErrUnknownShortcut", err)
+	}
+	if err := r.Rebind("workspace.new", "alt+shift+n"); err != nil {
+		t.Fatalf("Rebind() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("keymap file not written: %v", err)
+	}
+	var saved map[string]string
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"workspace.new": "Alt+Shift+N"}; !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved keymap = %v, want %v", saved, want)
+	}
+
+	// A fresh registry picks the rebind up when the action is registered.
+	r2 := newShortcutRegistry()
+	if err := r2.LoadKeymap(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := r2.Register(Shortcut{Action: "workspace.new", Accelerator: "Ctrl+N"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	keymap := r2.Keymap()
+	if len(keymap) != 1 || keymap[0].Accelerator != "Alt+Shift+N" || keymap[0].Default != "Ctrl+N" {
+		t.Errorf("Keymap() = %+v", keymap)
+	}
+
+	if err := r2.Reset("workspace.new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := r2.accelerator("workspace.new"); got != "Ctrl+N" {
+		t.Errorf("accelerator after Reset() = %q, want %q", got, "Ctrl+N")
+	}
+}
+
+func TestShortcutRegistry_LoadKeymapDoesNotSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keymap.json")
+	written := []byte(`{"workspace.new":"alt+shift+n"}`)
+	if err := os.WriteFile(path, written, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newShortcutRegistry()
+	if err := r.Register(Shortcut{Action: "workspace.new", Accelerator: "Ctrl+N"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadKeymap(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.accelerator("workspace.new"); got != "Alt+Shift+N" {
+		t.Errorf("accelerator after LoadKeymap() = %q, want %q", got, "Alt+Shift+N")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(written) {
+		t.Errorf("LoadKeymap() rewrote the keymap file:\n%s", data)
+	}
+}
+
+func TestShortcutRegistry_OnChange(t *testing.T) {
+	r := newShortcutRegistry()
+	var changes []string
+	r.onChange = func(sc Shortcut, previous string) {
+		changes = append(changes, previous+"->"+sc.Accelerator)
+	}
+	if err := r.Register(Shortcut{Action: "a", Accelerator: "Ctrl+A"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Rebind("a", "Ctrl+B"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Rebind("a", "Ctrl+B"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"->Ctrl+A", "Ctrl+A->Ctrl+B"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
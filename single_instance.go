@@ -0,0 +1,229 @@
+package display
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrAlreadyRunning is returned by Startup in single-instance mode when
+// another instance owns the lock. The launch has been forwarded to that
+// instance and the caller should exit.
+var ErrAlreadyRunning = errors.New("display: another instance is already running")
+
+// instanceDialTimeout bounds how long a second launch waits for the running
+// instance to accept its arguments.
+const instanceDialTimeout = 2 * time.Second
+
+// instanceLock owns the single-instance socket. While it is held, later
+// launches connect to the socket and forward their arguments instead of
+// starting up.
+type instanceLock struct {
+	file     *os.File
+	listener net.Listener
+	path     string
+	onLaunch func(ActionSecondInstance)
+	wg       sync.WaitGroup
+}
+
+// errInstanceLocked is returned by lockInstanceFile when another process
+// holds the lock.
+var errInstanceLocked = errors.New("display: instance lock is held")
+
+// acquireInstanceLock tries to become the single running instance by taking
+// an exclusive lock on a file next to the Unix domain socket at path and then
+// listening on the socket. If another instance holds the lock, launch is
+// forwarded to it and ErrAlreadyRunning is returned. Forwarded launches are
+// passed to onLaunch.
+//
+// The socket is only removed and recreated by the lock holder, so two
+// launches racing each other cannot both end up listening.
+func acquireInstanceLock(path string, launch ActionSecondInstance, onLaunch func(ActionSecondInstance)) (*instanceLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("display: creating instance lock directory: %w", err)
+	}
+	file, err := lockInstanceFile(path + ".lock")
+	if errors.Is(err, errInstanceLocked) {
+		if err := forwardToRunningInstance(path, launch); err != nil {
+			return nil, err
+		}
+		return nil, ErrAlreadyRunning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("display: acquiring instance lock: %w", err)
+	}
+
+	// We hold the lock, so any socket file left behind belongs to an
+	// instance that exited without cleaning up.
+	_ = os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("display: acquiring instance lock: %w", err)
+	}
+	l := &instanceLock{
+		file:     file,
+		listener: listener,
+		path:     path,
+		onLaunch: onLaunch,
+	}
+	l.wg.Add(1)
+	go l.serve()
+	return l, nil
+}
+
+// forwardToRunningInstance connects to the socket of the instance holding
+// the lock and forwards launch to it. The running instance may have taken
+// the lock but not started listening yet, so connecting is retried until
+// instanceDialTimeout.
+func forwardToRunningInstance(path string, launch ActionSecondInstance) error {
+	deadline := time.Now().Add(instanceDialTimeout)
+	for {
+		conn, err := net.DialTimeout("unix", path, instanceDialTimeout)
+		if err == nil {
+			defer conn.Close()
+			return forwardLaunch(conn, launch)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("display: connecting to running instance: %w", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// forwardLaunch sends a launch request to the running instance and waits for
+// it to be acknowledged.
+func forwardLaunch(conn net.Conn, launch ActionSecondInstance) error {
+	_ = conn.SetDeadline(time.Now().Add(instanceDialTimeout))
+	if err := json.NewEncoder(conn).Encode(launch); err != nil {
+		return fmt.Errorf("display: forwarding launch: %w", err)
+	}
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		return fmt.Errorf("display: waiting for running instance: %w", err)
+	}
+	return nil
+}
+
+// serve accepts forwarded launches until the lock is closed.
+func (l *instanceLock) serve() {
+	defer l.wg.Done()
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return
+		}
+		l.handle(conn)
+	}
+}
+
+// handle reads a single launch request and acknowledges it.
+func (l *instanceLock) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(instanceDialTimeout))
+	var launch ActionSecondInstance
+	if err := json.NewDecoder(conn).Decode(&launch); err != nil {
+		return
+	}
+	_, _ = conn.Write([]byte("ok\n"))
+	if l.onLaunch != nil {
+		go l.onLaunch(launch)
+	}
+}
+
+// Close removes the socket file and releases the lock.
+func (l *instanceLock) Close() error {
+	err := l.listener.Close()
+	l.wg.Wait()
+	_ = os.Remove(l.path)
+	_ = l.file.Close()
+	return err
+}
+
+var unsafeInstanceChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// instanceSocketPath returns the socket used for the single-instance lock of
+// the given instance ID. On Linux it lives in $XDG_RUNTIME_DIR so that it is
+// private to the user and cleared on logout.
+func instanceSocketPath(id string) string {
+	dir := os.TempDir()
+	if runtime.GOOS == "linux" {
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+			dir = runtimeDir
+		}
+	}
+	name := unsafeInstanceChars.ReplaceAllString(id, "-")
+	if name == "" {
+		name = "core"
+	}
+	return filepath.Join(dir, name+".instance.sock")
+}
+
+// instanceID returns `Options.InstanceID`, defaulting to the application
+// name.
+func (s *Service) instanceID() string {
+	if s.config.InstanceID != "" {
+		return s.config.InstanceID
+	}
+	return s.appName()
+}
+
+// currentLaunch describes how this process was started.
+func currentLaunch() ActionSecondInstance {
+	wd, _ := os.Getwd()
+	return ActionSecondInstance{
+		Args:       os.Args[1:],
+		WorkingDir: wd,
+	}
+}
+
+// startSingleInstance acquires the single-instance lock when
+// `Options.SingleInstance` is set.
+func (s *Service) startSingleInstance() error {
+	lock, err := acquireInstanceLock(instanceSocketPath(s.instanceID()), currentLaunch(), s.handleSecondInstance)
+	if err != nil {
+		return err
+	}
+	s.instanceLock = lock
+	s.app.OnShutdown(func() {
+		_ = lock.Close()
+	})
+	return nil
+}
+
+// handleSecondInstance is called when another launch forwards its arguments
+// to this instance. It announces the launch on the action bus, dispatches any
+// links in its arguments, and then either hands it to
+// `Options.OnSecondInstance` or brings the "main" window forward.
+func (s *Service) handleSecondInstance(launch ActionSecondInstance) {
+	s.app.Logger.Info("Second instance launched", "args", launch.Args, "workingDir", launch.WorkingDir)
+	s.app.Event.Emit(EventSecondInstance, launch)
+	handledLinks := s.handleDeepLinkArgs(launch.Args)
+	if s.config.OnSecondInstance != nil {
+		s.config.OnSecondInstance(launch)
+		return
+	}
+	if !handledLinks {
+		s.focusMainWindow()
+	}
+}
+
+// focusMainWindow shows, restores and focuses the "main" window.
+func (s *Service) focusMainWindow() {
+	window, ok := s.app.Window.GetByName("main")
+	if !ok {
+		return
+	}
+	window.Show()
+	if window.IsMinimised() {
+		window.Restore()
+	}
+	window.Focus()
+}
@@ -0,0 +1,105 @@
+package display
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstanceLock_ForwardsSecondLaunch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "core.instance.sock")
+
+	launches := make(chan ActionSecondInstance, 1)
+	first, err := acquireInstanceLock(path, ActionSecondInstance{}, func(launch ActionSecondInstance) {
+		launches <- launch
+	})
+	if err != nil {
+		t.Fatalf("first acquireInstanceLock() error = %v", err)
+	}
+	defer first.Close()
+
+	second := ActionSecondInstance{Args: []string{"--open", "core://workspace/foo"}, WorkingDir: "/home/user"}
+	lock, err := acquireInstanceLock(path, second, nil)
+	if !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("second acquireInstanceLock() error = %v, want ErrAlreadyRunning", err)
+	}
+	if lock != nil {
+		t.Error("second acquireInstanceLock() returned a lock")
+	}
+
+	select {
+	case got := <-launches:
+		if !reflect.DeepEqual(got, second) {
+			t.Errorf("forwarded launch = %+v, want %+v", got, second)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for forwarded launch")
+	}
+}
+
+func TestInstanceLock_ReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "core.instance.sock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := acquireInstanceLock(path, ActionSecondInstance{}, nil)
+	if err != nil {
+		t.Fatalf("acquireInstanceLock() with stale socket error = %v", err)
+	}
+	if err := lock.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("socket file still exists after Close(): %v", err)
+	}
+
+	// Once released, the lock can be acquired again.
+	lock, err = acquireInstanceLock(path, ActionSecondInstance{}, nil)
+	if err != nil {
+		t.Fatalf("acquireInstanceLock() after Close() error = %v", err)
+	}
+	lock.Close()
+}
+
+func TestInstanceLock_HolderOwnsSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "core.instance.sock")
+
+	// Another launch holds the lock but is not listening yet; a racing
+	// launch must not take the socket over.
+	file, err := lockInstanceFile(path + ".lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := acquireInstanceLock(path, ActionSecondInstance{}, nil)
+	if err == nil || errors.Is(err, ErrAlreadyRunning) || lock != nil {
+		t.Fatalf("acquireInstanceLock() while locked = %v, %v", lock, err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("socket file removed by a launch without the lock: %v", err)
+	}
+
+	file.Close()
+	lock, err = acquireInstanceLock(path, ActionSecondInstance{}, nil)
+	if err != nil {
+		t.Fatalf("acquireInstanceLock() after the lock was released error = %v", err)
+	}
+	lock.Close()
+}
+
+func TestInstanceSocketPath(t *testing.T) {
+	got := instanceSocketPath("Core Client/Hub")
+	if base := filepath.Base(got); base != "Core-Client-Hub.instance.sock" {
+		t.Errorf("instanceSocketPath() base = %q", base)
+	}
+	if !strings.HasSuffix(instanceSocketPath(""), "core.instance.sock") {
+		t.Errorf("instanceSocketPath(\"\") = %q", instanceSocketPath(""))
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package display
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockInstanceFile opens path and takes an exclusive flock on it without
+// blocking. The lock is released when the file is closed or the process
+// exits.
+func lockInstanceFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errInstanceLocked
+		}
+		return nil, err
+	}
+	return file, nil
+}
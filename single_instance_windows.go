@@ -0,0 +1,31 @@
+//go:build windows
+
+package display
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// errorSharingViolation is returned by CreateFile when another process has
+// the file open without sharing it.
+const errorSharingViolation syscall.Errno = 32
+
+// lockInstanceFile opens path without sharing it, so that no other process
+// can open it until the file is closed or the process exits.
+func lockInstanceFile(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+		syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if errors.Is(err, errorSharingViolation) {
+			return nil, errInstanceLocked
+		}
+		return nil, err
+	}
+	return os.NewFile(uintptr(handle), path), nil
+}
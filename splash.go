@@ -0,0 +1,235 @@
+package display
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ErrStartupTimeout is reported when the "main" window does not signal that
+// it is ready before the splash timeout.
+var ErrStartupTimeout = errors.New("display: main window did not become ready")
+
+const (
+	// splashWindow is the name of the splash window.
+	splashWindow = "splash"
+	// defaultSplashTimeout is used when `SplashOptions.Timeout` is not set.
+	defaultSplashTimeout = 30 * time.Second
+)
+
+// SplashOptions configures the splash window shown while "main" loads.
+//
+// example:
+//
+//	//go:embed assets/splash.png
+//	var splashImage []byte
+//
+//	displayService, err := display.NewWithOptions(display.Options{
+//		Splash: &display.SplashOptions{Image: splashImage, Timeout: time.Minute},
+//	})
+type SplashOptions struct {
+	// Image is a PNG, JPEG or SVG shown above the progress bar.
+	Image []byte
+
+	// HTML replaces the built-in splash page. To receive progress updates it
+	// should define `window.coreSplashProgress(percent, message)`.
+	HTML string
+
+	// Width and Height default to 480x320.
+	Width  int
+	Height int
+
+	// Timeout is how long "main" has to signal that it is ready before an
+	// error is shown. It defaults to 30 seconds.
+	Timeout time.Duration
+}
+
+// splashState tracks the splash window until "main" is ready.
+type splashState struct {
+	options SplashOptions
+
+	mu      sync.Mutex
+	window  application.Window
+	timer   *time.Timer
+	message string
+	done    bool
+}
+
+// newSplashState creates the state for the splash window, filling in
+// defaults.
+func newSplashState(options SplashOptions) *splashState {
+	if options.Width == 0 {
+		options.Width = 480
+	}
+	if options.Height == 0 {
+		options.Height = 320
+	}
+	if options.Timeout == 0 {
+		options.Timeout = defaultSplashTimeout
+	}
+	return &splashState{options: options}
+}
+
+// splashPage returns the HTML shown in the splash window.
+func splashPage(options SplashOptions) string {
+	if options.HTML != "" {
+		return options.HTML
+	}
+	image := ""
+	if len(options.Image) > 0 {
+		mime := http.DetectContentType(options.Image)
+		if bytes.Contains(options.Image[:min(len(options.Image), 512)], []byte("<svg")) {
+			mime = "image/svg+xml"
+		}
+		image = fmt.Sprintf(`<img src="data:%s;base64,%s" alt="">`, mime, base64.StdEncoding.EncodeToString(options.Image))
+	}
+	return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>
+	html, body { margin: 0; height: 100%; font: 13px system-ui, sans-serif; background: #1e1f22; color: #ddd; user-select: none; }
+	body { display: flex; flex-direction: column; align-items: center; justify-content: center; gap: 16px; padding: 24px; box-sizing: border-box; }
+	img { max-width: 100%; max-height: 60%; }
+	.bar { width: 80%; height: 4px; background: #3a3b3f; border-radius: 2px; overflow: hidden; }
+	.fill { width: 0; height: 100%; background: #4f8cff; transition: width .2s; }
+	.message { min-height: 1em; opacity: .8; }
+</style>
+</head>
+<body>
+` + image + `
+<div class="bar"><div class="fill" id="fill"></div></div>
+<div class="message" id="message"></div>
+<script>
+	window.coreSplashProgress = function (percent, message) {
+		document.getElementById("fill").style.width = percent + "%";
+		document.getElementById("message").textContent = message;
+	};
+	(function ready() {
+		if (window._wails && window._wails.invoke) {
+			window._wails.invoke("wails:runtime:ready");
+		} else {
+			setTimeout(ready, 20);
+		}
+	})();
+</script>
+</body>
+</html>`
+}
+
+// splashProgressScript returns the script that reports progress to the
+// splash page.
+func splashProgressScript(percent int, message string) string {
+	percent = max(0, min(percent, 100))
+	quoted, _ := json.Marshal(message)
+	return fmt.Sprintf("window.coreSplashProgress && window.coreSplashProgress(%d, %s);", percent, quoted)
+}
+
+// showSplash opens the splash window and starts the startup timeout. The
+// splash closes when "main" signals it is ready, either by emitting
+// `EventReady` or by calling `Bridge.Ready`.
+func (s *Service) showSplash() {
+	sp := s.splash
+	window := s.app.Window.NewWithOptions(application.WebviewWindowOptions{
+		Name:                splashWindow,
+		Title:               s.appName(),
+		Width:               sp.options.Width,
+		Height:              sp.options.Height,
+		HTML:                splashPage(sp.options),
+		Frameless:           true,
+		AlwaysOnTop:         true,
+		DisableResize:       true,
+		InitialPosition:     application.WindowCentered,
+		MinimiseButtonState: application.ButtonHidden,
+		MaximiseButtonState: application.ButtonHidden,
+	})
+	s.app.Event.On(EventReady, func(event *application.CustomEvent) {
+		if event.Sender == "" || event.Sender == "main" {
+			s.Ready()
+		}
+	})
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.window = window
+	sp.timer = time.AfterFunc(sp.options.Timeout, func() {
+		s.finishSplash(ErrStartupTimeout)
+	})
+}
+
+// SplashProgress updates the progress bar and status message on the splash
+// window. It does nothing once the splash has closed.
+//
+// example:
+//
+//	displayService.SplashProgress(40, "Connecting to peers…")
+func (s *Service) SplashProgress(percent int, message string) {
+	sp := s.splash
+	if sp == nil {
+		return
+	}
+	sp.mu.Lock()
+	if sp.done || sp.window == nil {
+		sp.mu.Unlock()
+		return
+	}
+	sp.message = message
+	window := sp.window
+	sp.mu.Unlock()
+	window.ExecJS(splashProgressScript(percent, message))
+}
+
+// Ready signals that the "main" window has finished loading. It closes the
+// splash window and shows "main". Frontends call `Bridge.Ready` or emit
+// `EventReady` instead.
+//
+// example:
+//
+//	displayService.Ready()
+func (s *Service) Ready() {
+	s.finishSplash(nil)
+}
+
+// finishSplash closes the splash window and shows "main". If startup failed,
+// an error dialog is shown as well.
+func (s *Service) finishSplash(err error) {
+	sp := s.splash
+	if sp == nil {
+		return
+	}
+	sp.mu.Lock()
+	if sp.done || sp.window == nil {
+		sp.mu.Unlock()
+		return
+	}
+	sp.done = true
+	sp.timer.Stop()
+	window, message := sp.window, sp.message
+	sp.mu.Unlock()
+
+	window.Close()
+	if main, ok := s.app.Window.GetByName("main"); ok {
+		main.Show()
+		main.Focus()
+	}
+	if err == nil {
+		return
+	}
+
+	s.app.Logger.Error("Startup failed", "error", err, "status", message)
+	details := s.T("%s did not finish loading within %s.", s.appName(), sp.options.Timeout)
+	if message != "" {
+		details += "\n\n" + s.T("Last status: %s", message)
+	}
+	dialog := s.app.Dialog.Error()
+	dialog.SetTitle(s.T("Startup failed"))
+	dialog.SetMessage(details)
+	dialog.Show()
+}
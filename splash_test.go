@@ -0,0 +1,50 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplashPage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	page := splashPage(SplashOptions{Image: png})
+	if !strings.Contains(page, `src="data:image/png;base64,`) {
+		t.Errorf("splash page does not embed the PNG:\n%s", page)
+	}
+	if !strings.Contains(page, "window.coreSplashProgress") {
+		t.Error("splash page does not define coreSplashProgress")
+	}
+
+	svg := splashPage(SplashOptions{Image: []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`)})
+	if !strings.Contains(svg, "data:image/svg+xml;base64,") {
+		t.Error("splash page does not detect SVG images")
+	}
+
+	if got := splashPage(SplashOptions{HTML: "<p>Loading</p>"}); got != "<p>Loading</p>" {
+		t.Errorf("splashPage() with custom HTML = %q", got)
+	}
+}
+
+func TestSplashProgressScript(t *testing.T) {
+	tests := map[string]string{
+		splashProgressScript(40, "Connecting"): `window.coreSplashProgress(40, "Connecting")`,
+		splashProgressScript(150, ""):          `window.coreSplashProgress(100, "")`,
+		splashProgressScript(-5, `"</script>`): `window.coreSplashProgress(0, "\"\u003c/script\u003e")`,
+	}
+	for got, want := range tests {
+		if !strings.Contains(got, want) {
+			t.Errorf("splashProgressScript() = %s, want it to contain %s", got, want)
+		}
+	}
+}
+
+func TestSplashDefaults(t *testing.T) {
+	s, _ := NewWithOptions(Options{Splash: &SplashOptions{}})
+	if o := s.splash.options; o.Width != 480 || o.Height != 320 || o.Timeout != 30*time.Second {
+		t.Errorf("splash defaults = %+v", o)
+	}
+	// Progress and readiness are no-ops until the splash window is shown.
+	s.SplashProgress(10, "Starting")
+	s.Ready()
+}
@@ -0,0 +1,60 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// statePath returns configured if it is set, otherwise the named file in the
+// display service's directory under the user's config directory.
+func statePath(configured, name string) string {
+	if configured != "" {
+		return configured
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "core", "display", name)
+}
+
+// readStateFile decodes the JSON file at path into v. A missing file, or an
+// empty path, leaves v unchanged and is not an error.
+func readStateFile(path, what string, v any) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("display: reading %s: %w", what, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("display: parsing %s %s: %w", what, path, err)
+	}
+	return nil
+}
+
+// writeStateFile encodes v as JSON to path, creating its directory. An empty
+// path is a no-op.
+func writeStateFile(path, what string, v any) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("display: saving %s: %w", what, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("display: saving %s: %w", what, err)
+	}
+	return nil
+}
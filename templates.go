@@ -0,0 +1,127 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// ErrUnknownTemplate is returned when a window is requested from a template
+// that has not been registered.
+var ErrUnknownTemplate = errors.New("display: unknown window template")
+
+// RegisterWindowTemplate registers a named window preset that can be applied
+// with `Service.FromTemplate`. Registering a name again replaces the preset.
+// If the preset has no Name, windows opened from it are named after the
+// template.
+//
+// example:
+//
+//	displayService.RegisterWindowTemplate("settings", display.WindowConfig{
+//		Title:     "Settings",
+//		Width:     640,
+//		Height:    480,
+//		Frameless: true,
+//	})
+func (s *Service) RegisterWindowTemplate(name string, config WindowConfig) {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+	s.templates[name] = config
+}
+
+// LookupWindowTemplate returns the preset registered under name.
+func (s *Service) LookupWindowTemplate(name string) (WindowConfig, bool) {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+	config, ok := s.templates[name]
+	return config, ok
+}
+
+// WindowTemplates returns the names of all registered templates in sorted
+// order.
+func (s *Service) WindowTemplates() []string {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+	names := make([]string, 0, len(s.templates))
+	for name := range s.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FromTemplate applies a window preset registered with this service. Options
+// given after it are layered on top, so the preset only supplies defaults. An
+// unknown template name leaves the configuration unchanged.
+//
+// example:
+//
+//	err := displayService.OpenWindow(
+//		displayService.FromTemplate("settings"),
+//		display.WithURL("/#/settings/network"),
+//	)
+func (s *Service) FromTemplate(name string) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		preset, ok := s.LookupWindowTemplate(name)
+		if !ok {
+			return
+		}
+		if preset.Name == "" {
+			preset.Name = name
+		}
+		mergeWindowConfig(c, preset)
+	})
+}
+
+// mergeWindowConfig copies every non-zero field of src onto dst.
+func mergeWindowConfig(dst *WindowConfig, src WindowConfig) {
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src)
+	for i := 0; i < sv.NumField(); i++ {
+		if !dv.Field(i).CanSet() {
+			continue
+		}
+		if field := sv.Field(i); !field.IsZero() {
+			dv.Field(i).Set(field)
+		}
+	}
+}
+
+// Config is the display configuration file, loaded from `Options.ConfigPath`
+// at startup.
+//
+// example:
+//
+//	{
+//		"templates": {
+//			"settings": {"Title": "Settings", "Width": 640, "Height": 480},
+//			"about":    {"Title": "About", "Width": 400, "Height": 300, "Frameless": true}
+//		}
+//	}
+type Config struct {
+	// Templates are window presets registered by name.
+	Templates map[string]WindowConfig `json:"templates,omitempty"`
+}
+
+// LoadConfig reads a display configuration file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("display: reading config: %w", err)
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("display: parsing config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// applyConfig registers everything declared in a configuration file.
+func (s *Service) applyConfig(config *Config) {
+	for name, template := range config.Templates {
+		s.RegisterWindowTemplate(name, template)
+	}
+}
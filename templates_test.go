@@ -0,0 +1,143 @@
+package display
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestFromTemplate(t *testing.T) {
+	s, _ := New()
+	s.RegisterWindowTemplate("test-settings", WindowConfig{
+		Title:            "Settings",
+		Width:            640,
+		Height:           480,
+		Frameless:        true,
+		CloseButtonState: application.ButtonHidden,
+	})
+	s.RegisterWindowTemplate("test-inspector", WindowConfig{
+		Name:        "inspector-window",
+		AlwaysOnTop: true,
+	})
+	if other, _ := New(); len(other.WindowTemplates()) != 0 {
+		t.Errorf("templates shared between services: %q", other.WindowTemplates())
+	}
+
+	tests := []struct {
+		name string
+		opts []WindowOption
+		want application.WebviewWindowOptions
+	}{
+		{
+			name: "Template fills in defaults",
+			opts: []WindowOption{s.FromTemplate("test-settings")},
+			want: application.WebviewWindowOptions{
+				Name:             "test-settings",
+				Title:            "Settings",
+				Width:            640,
+				Height:           480,
+				URL:              "/",
+				Frameless:        true,
+				CloseButtonState: application.ButtonHidden,
+			},
+		},
+		{
+			name: "Overrides are layered on top",
+			opts: []WindowOption{s.FromTemplate("test-settings"), WithURL("/#/settings/network"), WithWidth(800)},
+			want: application.WebviewWindowOptions{
+				Name:             "test-settings",
+				Title:            "Settings",
+				Width:            800,
+				Height:           480,
+				URL:              "/#/settings/network",
+				Frameless:        true,
+				CloseButtonState: application.ButtonHidden,
+			},
+		},
+		{
+			name: "Template name is kept",
+			opts: []WindowOption{s.FromTemplate("test-inspector")},
+			want: application.WebviewWindowOptions{
+				Name:        "inspector-window",
+				Title:       "Core",
+				Width:       1280,
+				Height:      800,
+				URL:         "/",
+				AlwaysOnTop: true,
+			},
+		},
+		{
+			name: "Unknown template is ignored",
+			opts: []WindowOption{s.FromTemplate("test-missing")},
+			want: application.WebviewWindowOptions{
+				Name:   "main",
+				Title:  "Core",
+				Width:  1280,
+				Height: 800,
+				URL:    "/",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildWailsWindowOptions(tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildWailsWindowOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithWailsOverrides(t *testing.T) {
+	s, _ := New()
+	s.RegisterWindowTemplate("test-about", WindowConfig{Title: "About", Width: 400, Height: 300})
+	parsed, err := parseWindowOptions(map[string]any{
+		"name":    "about-1",
+		"options": map[string]any{"Height": 350.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buildWailsWindowOptions(s.FromTemplate("test-about"), withWailsOverrides(parsed))
+	want := application.WebviewWindowOptions{
+		Name:   "about-1",
+		Title:  "About",
+		Width:  400,
+		Height: 350,
+		URL:    "/",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildWailsWindowOptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "display.json")
+	data := `{
+		"templates": {
+			"settings": {"Title": "Settings", "Width": 640, "Height": 480, "Frameless": true},
+			"about": {"Title": "About", "CloseButtonState": 1}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	want := map[string]WindowConfig{
+		"settings": {Title: "Settings", Width: 640, Height: 480, Frameless: true},
+		"about":    {Title: "About", CloseButtonState: application.ButtonDisabled},
+	}
+	if !reflect.DeepEqual(config.Templates, want) {
+		t.Errorf("LoadConfig() templates = %+v, want %+v", config.Templates, want)
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadConfig() on missing file error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
+// trayWindowName is the name of the hidden window attached to the system
+// tray icon.
+const trayWindowName = "system-tray"
+
 // systemTray configures and creates the system tray icon and menu. This
 // function is called during the startup of the display service.
 func (s *Service) systemTray() {
 
 	systray := s.app.SystemTray.New()
+	s.tray = systray
 	systray.SetTooltip("Core")
 	systray.SetLabel("Core")
 	//appTrayIcon, _ := d.assets.ReadFile("assets/apptray.png")
@@ -22,31 +27,39 @@ func (s *Service) systemTray() {
 	//	systray.SetDarkModeIcon(appTrayIcon)
 	//	systray.SetIcon(appTrayIcon)
 	//}
-	// Create a hidden window for the system tray menu to interact with
+	// Create a hidden window for the system tray menu to interact with. It
+	// only gets the minimal rights it needs, and no other window may take
+	// its name, which its rights are granted under.
+	s.windows.reserve(trayWindowName)
 	trayWindow := s.app.Window.NewWithOptions(application.WebviewWindowOptions{
-		Name:      "system-tray",
+		Name:      trayWindowName,
 		Title:     "System Tray Status",
 		URL:       "system-tray.html",
 		Width:     400,
 		Frameless: true,
 		Hidden:    true,
 	})
+	s.grantCapabilities(trayWindow.Name(), trayWindow.ID(), trayCapabilities)
 	systray.AttachWindow(trayWindow).WindowOffset(5)
 
 	// --- Build Tray Menu ---
 	trayMenu := s.app.Menu.New()
-	trayMenu.Add("Open Desktop").OnClick(func(ctx *application.Context) {
-		for _, window := range s.app.Window.GetAll() {
-			window.Show()
-		}
+	s.trayMenu = trayMenu
+	s.localise(trayMenu.Add("Open Desktop"), "Open Desktop").OnClick(func(ctx *application.Context) {
+		s.RestoreFromTray()
 	})
-	trayMenu.Add("Close Desktop").OnClick(func(ctx *application.Context) {
-		for _, window := range s.app.Window.GetAll() {
-			window.Hide()
-		}
+	s.localise(trayMenu.Add("Close Desktop"), "Close Desktop").OnClick(func(ctx *application.Context) {
+		s.HideToTray()
 	})
+	recent := trayMenu.AddSubmenu("Open Recent")
+	s.localise(trayMenu.FindByLabel("Open Recent"), "Open Recent")
+	s.recentMu.Lock()
+	s.trayRecent = recent
+	version, sorted := s.recentSnapshotLocked(false)
+	s.recentMu.Unlock()
+	s.renderRecent(version, sorted)
 
-	trayMenu.Add("Environment Info").OnClick(func(ctx *application.Context) {
+	s.localise(trayMenu.Add("Environment Info"), "Environment Info").OnClick(func(ctx *application.Context) {
 		s.ShowEnvironmentDialog()
 	})
 	// Add brand-specific menu items
@@ -65,7 +78,8 @@ func (s *Service) systemTray() {
 	//}
 
 	trayMenu.AddSeparator()
-	trayMenu.Add("Quit").OnClick(func(ctx *application.Context) {
+	s.addAutostartItem(trayMenu)
+	s.localise(trayMenu.Add("Quit"), "Quit").OnClick(func(ctx *application.Context) {
 		s.app.Quit()
 	})
 
@@ -0,0 +1,523 @@
+// Code generated by display-bindgen. DO NOT EDIT.
+// Regenerate with `go generate` in the display module.
+
+export interface ActionDenied {
+  window: string;
+  action: string;
+  capability: Capability;
+}
+
+export interface ActionError {
+  code: string;
+  message: string;
+}
+
+export interface ActionLocaleChanged {
+  locale: string;
+}
+
+export interface ActionNavigationBlocked {
+  window: string;
+  url: string;
+}
+
+export interface ActionNotificationAction {
+  id: number;
+  action: string;
+}
+
+export interface ActionReply {
+  id: string;
+  action: string;
+  result?: unknown;
+  error?: ActionError;
+}
+
+export interface ActionRequest {
+  id?: string;
+  action: string;
+  payload?: Record<string, unknown>;
+  timeoutMs?: number;
+}
+
+export interface ActionSecondInstance {
+  args: string[];
+  workingDir: string;
+}
+
+export interface ActionShortcut {
+  action: string;
+  window?: string;
+}
+
+export type ButtonState = 0 | 1 | 2;
+
+export const ButtonState = {
+  Enabled: 0,
+  Disabled: 1,
+  Hidden: 2,
+} as const;
+
+export type Capability = 'window:open' | 'dialog:file' | 'notify' | 'tray:update' | 'clipboard:read' | 'clipboard:write' | 'recent' | (string & {});
+
+export const Capability = {
+  WindowOpen: 'window:open',
+  DialogFile: 'dialog:file',
+  Notify: 'notify',
+  TrayUpdate: 'tray:update',
+  ClipboardRead: 'clipboard:read',
+  ClipboardWrite: 'clipboard:write',
+  Recent: 'recent',
+} as const;
+
+export interface ClipboardChange {
+  formats: ClipboardFormat[];
+}
+
+export interface ClipboardContent {
+  text?: string;
+  html?: string;
+  png?: string;
+}
+
+export type ClipboardFormat = 'text/plain' | 'text/html' | 'image/png';
+
+export const ClipboardFormat = {
+  Text: 'text/plain',
+  HTML: 'text/html',
+  PNG: 'image/png',
+} as const;
+
+export interface ClipboardReadRequest {
+  formats?: ClipboardFormat[];
+}
+
+export interface ClipboardWriteRequest {
+  text?: string;
+  html?: string;
+  png?: string;
+  clearAfterMs?: number;
+}
+
+export interface ContextMenuClick {
+  menu: string;
+  item: string;
+  context?: Record<string, unknown>;
+}
+
+export interface ContextMenuRequest {
+  id: string;
+  x: number;
+  y: number;
+  context?: Record<string, unknown>;
+}
+
+export interface DeepLink {
+  url: string;
+  route: string;
+  params?: Record<string, string>;
+  query?: Record<string, string>;
+}
+
+export interface DroppedFile {
+  path: string;
+  name: string;
+  mimeType: string;
+  size: number;
+}
+
+export interface FileDialogFilter {
+  name: string;
+  pattern: string;
+}
+
+export interface FileDialogRequest {
+  title?: string;
+  filename?: string;
+  multiple?: boolean;
+  filters?: FileDialogFilter[];
+}
+
+export interface FileDrop {
+  window: string;
+  files: DroppedFile[];
+  rejected?: RejectedFile[];
+  x: number;
+  y: number;
+  target?: string;
+}
+
+export interface FileDropFilter {
+  types?: string[];
+  maxSize?: number;
+  maxFiles?: number;
+}
+
+export type NavigationAction = 'allow' | 'external' | 'block' | 'prompt';
+
+export const NavigationAction = {
+  Allow: 'allow',
+  External: 'external',
+  Block: 'block',
+  Prompt: 'prompt',
+} as const;
+
+export interface NavigationPolicy {
+  allowed?: string[];
+  external?: string[];
+  default?: NavigationAction;
+}
+
+export interface NavigationRequest {
+  url: string;
+}
+
+export interface Notification {
+  ReplacesID?: number;
+  Title?: string;
+  Body?: string;
+  Icon?: string;
+  Urgency?: NotificationUrgency;
+  Actions?: NotificationAction[];
+  Timeout?: number;
+}
+
+export interface NotificationAction {
+  ID?: string;
+  Label?: string;
+}
+
+export type NotificationUrgency = 0 | 1 | 2;
+
+export const NotificationUrgency = {
+  Low: 0,
+  Normal: 1,
+  Critical: 2,
+} as const;
+
+export interface OpenWindowOptions {
+  Title?: string;
+  Width?: number;
+  Height?: number;
+}
+
+export interface OpenWindowRequest {
+  name?: string;
+  options?: OpenWindowOptions;
+  template?: string;
+  parent?: string;
+  modal?: boolean;
+  screen?: string;
+  placement?: Placement;
+  navigation?: NavigationPolicy;
+  capabilities?: Capability[];
+}
+
+export type Placement = 'centre' | 'remember' | 'clamp';
+
+export const Placement = {
+  Centre: 'centre',
+  Remember: 'remember',
+  Clamp: 'clamp',
+} as const;
+
+export interface RecentItem {
+  id: string;
+  kind?: string;
+  label?: string;
+  path?: string;
+  pinned?: boolean;
+  openedAt?: string;
+}
+
+export interface RecentPinRequest {
+  id: string;
+  pinned: boolean;
+}
+
+export interface RecentRemoveRequest {
+  id: string;
+}
+
+export interface RejectedFile {
+  path: string;
+  reason: string;
+}
+
+export interface Shortcut {
+  action: string;
+  accelerator: string;
+  default: string;
+  window?: string;
+  description?: string;
+}
+
+export interface TrayUpdateRequest {
+  tooltip?: string;
+  label?: string;
+}
+
+export interface WindowConfig {
+  Name?: string;
+  Title?: string;
+  Width?: number;
+  Height?: number;
+  URL?: string;
+  AlwaysOnTop?: boolean;
+  Hidden?: boolean;
+  MinimiseButtonState?: ButtonState;
+  MaximiseButtonState?: ButtonState;
+  CloseButtonState?: ButtonState;
+  Frameless?: boolean;
+  Parent?: string;
+  Modal?: boolean;
+  Screen?: string;
+  Placement?: Placement;
+  Navigation?: NavigationPolicy;
+  Capabilities?: Capability[];
+  FileDropFilter?: FileDropFilter;
+  DefaultContextMenuDisabled?: boolean;
+}
+
+/** Names of the built-in actions. */
+export const Actions = {
+  ClipboardClear: 'clipboard.clear',
+  ClipboardRead: 'clipboard.read',
+  ClipboardWrite: 'clipboard.write',
+  ContextMenuOpen: 'contextmenu.open',
+  DialogOpenFile: 'dialog.openFile',
+  DialogSaveFile: 'dialog.saveFile',
+  Notify: 'notify',
+  RecentAdd: 'recent.add',
+  RecentClear: 'recent.clear',
+  RecentList: 'recent.list',
+  RecentPin: 'recent.pin',
+  RecentRemove: 'recent.remove',
+  TrayUpdate: 'tray.update',
+  WindowOpen: 'window.open',
+} as const;
+
+/** Payloads of the built-in actions, by name. */
+export interface ActionPayloads {
+  /** Empties the clipboard. Requires 'clipboard:write'. */
+  'clipboard.clear': null;
+  /** Reads the clipboard as text, HTML and PNG. HTML and images are only supported on Linux. Requires 'clipboard:read'. */
+  'clipboard.read': ClipboardReadRequest;
+  /** Copies text, HTML or a PNG image, optionally clearing it again after a delay. HTML and images are only supported on Linux. Requires 'clipboard:write'. */
+  'clipboard.write': ClipboardWriteRequest;
+  /** Opens a context menu registered in Go at the cursor, evaluating its items against the context. */
+  'contextmenu.open': ContextMenuRequest;
+  /** Shows a file open dialog and returns the chosen paths. Requires 'dialog:file'. */
+  'dialog.openFile': FileDialogRequest;
+  /** Shows a file save dialog and returns the chosen path. Requires 'dialog:file'. */
+  'dialog.saveFile': FileDialogRequest;
+  /** Shows a desktop notification and returns its ID. Requires 'notify'. */
+  'notify': Notification;
+  /** Records that an item was opened, moving it to the top of the recent items. Requires 'recent'. */
+  'recent.add': RecentItem;
+  /** Removes every unpinned recent item. Requires 'recent'. */
+  'recent.clear': null;
+  /** Returns the recently opened items, pinned items first. Requires 'recent'. */
+  'recent.list': null;
+  /** Pins or unpins a recent item. Pinned items are never dropped to make room. Requires 'recent'. */
+  'recent.pin': RecentPinRequest;
+  /** Removes a recent item. Requires 'recent'. */
+  'recent.remove': RecentRemoveRequest;
+  /** Changes the system tray tooltip and label. Requires 'tray:update'. */
+  'tray.update': TrayUpdateRequest;
+  /** Opens a window. Its capabilities are narrowed to those of the calling window. Requires 'window:open'. */
+  'window.open': OpenWindowRequest;
+}
+
+/** Results of the built-in actions, by name. */
+export interface ActionResults {
+  'clipboard.clear': null;
+  'clipboard.read': ClipboardContent;
+  'clipboard.write': null;
+  'contextmenu.open': null;
+  'dialog.openFile': string[];
+  'dialog.saveFile': string;
+  'notify': number;
+  'recent.add': null;
+  'recent.clear': null;
+  'recent.list': RecentItem[];
+  'recent.pin': null;
+  'recent.remove': null;
+  'tray.update': null;
+  'window.open': null;
+}
+
+export type ActionName = keyof ActionPayloads;
+
+/** Names of the events on the action bus. */
+export const Events = {
+  ActionDenied: 'display:action:denied',
+  ClipboardChanged: 'display:clipboard:changed',
+  ContextMenuClick: 'display:contextmenu:click',
+  DeepLink: 'display:deeplink',
+  FileDrop: 'display:filedrop',
+  SecondInstance: 'display:instance:launched',
+  LocaleChanged: 'display:locale:changed',
+  NavigationBlocked: 'display:navigation:blocked',
+  NavigationRequest: 'display:navigation:request',
+  NotificationAction: 'display:notification:action',
+  Ready: 'display:ready',
+  RecentChanged: 'display:recent:changed',
+  RecentOpen: 'display:recent:open',
+  Shortcut: 'display:shortcut',
+  ShortcutsChanged: 'display:shortcuts:changed',
+} as const;
+
+/** Data carried by each event, by name. */
+export interface EventData {
+  /** An action was refused for lack of a capability. Sent to the window that asked for it. */
+  'display:action:denied': ActionDenied;
+  /** The clipboard changed. Sent to windows granted "clipboard:read". */
+  'display:clipboard:changed': ClipboardChange;
+  /** A context menu item was clicked. Sent to the window that opened the menu. */
+  'display:contextmenu:click': ContextMenuClick;
+  /** A custom URL scheme link was handled. */
+  'display:deeplink': DeepLink;
+  /** Files were dropped onto the window. Sent to that window only. */
+  'display:filedrop': FileDrop;
+  /** A second launch forwarded its arguments. */
+  'display:instance:launched': ActionSecondInstance;
+  /** The display locale changed. */
+  'display:locale:changed': ActionLocaleChanged;
+  /** A navigation was blocked by a window's policy. */
+  'display:navigation:blocked': ActionNavigationBlocked;
+  /** A window with a navigation policy asked to load another page. Emitted by the navigation guard. */
+  'display:navigation:request': NavigationRequest;
+  /** A notification action button was clicked. */
+  'display:notification:action': ActionNotificationAction;
+  /** The main window has finished loading. Emitted by frontends. */
+  'display:ready': null;
+  /** The recent items changed. Sent to windows granted "recent". */
+  'display:recent:changed': RecentItem[];
+  /** A recent item was chosen from a menu. Sent to windows granted "recent". */
+  'display:recent:open': RecentItem;
+  /** A keyboard shortcut was triggered. */
+  'display:shortcut': ActionShortcut;
+  /** The keymap changed. */
+  'display:shortcuts:changed': Shortcut[];
+}
+
+export type EventName = keyof EventData;
+
+/** Carries calls and events between the client and the display service. */
+export interface DisplayTransport {
+  invoke(request: ActionRequest): Promise<ActionReply>;
+  cancel(id: string): Promise<unknown>;
+  on(name: string, callback: (data: unknown) => void): () => void;
+}
+
+/** Uses the Wails runtime's bindings and events, from `window.wails`. */
+export function wailsTransport(): DisplayTransport {
+  const wails = (globalThis as any).wails;
+  if (!wails?.Call || !wails?.Events) {
+    throw new Error('display: the Wails runtime is not loaded');
+  }
+  return {
+    invoke: (request) => wails.Call.ByName('github.com/Snider/display.Bridge.Invoke', request),
+    cancel: (id) => wails.Call.ByName('github.com/Snider/display.Bridge.CancelInvoke', id),
+    on: (name, callback) => wails.Events.On(name, (event: { data: unknown }) => callback(event.data)),
+  };
+}
+
+/** An action that failed, with the error code from its `ActionReply`. */
+export class DisplayActionError extends Error {
+  constructor(
+    readonly action: string,
+    readonly code: string,
+    message: string,
+  ) {
+    super(message);
+    this.name = 'DisplayActionError';
+  }
+}
+
+export interface CallOptions {
+  /** Overrides the action's timeout on the service. */
+  timeoutMs?: number;
+  /** Cancels the action when aborted. */
+  signal?: AbortSignal;
+}
+
+/** A typed client for the display service's actions and events. */
+export class DisplayClient {
+  private readonly pending = new Map<
+    string,
+    { resolve: (result: any) => void; reject: (error: Error) => void }
+  >();
+  private nextID = 0;
+
+  constructor(private readonly transport: DisplayTransport = wailsTransport()) {}
+
+  /** Invokes an action and resolves with its result. */
+  call<A extends ActionName>(
+    action: A,
+    payload: ActionPayloads[A],
+    options: CallOptions = {},
+  ): Promise<ActionResults[A]> {
+    const id = String(++this.nextID);
+    return new Promise((resolve, reject) => {
+      if (options.signal?.aborted) {
+        reject(new DisplayActionError(action, 'cancelled', 'display: action cancelled'));
+        return;
+      }
+      this.pending.set(id, { resolve, reject });
+      options.signal?.addEventListener('abort', () => this.cancel(id), { once: true });
+      this.transport
+        .invoke({
+          id,
+          action,
+          payload: payload as unknown as Record<string, unknown>,
+          timeoutMs: options.timeoutMs,
+        } satisfies ActionRequest)
+        .then(
+          (reply) => this.settle(reply),
+          (error) => this.fail(id, error instanceof Error ? error : new Error(String(error))),
+        );
+    });
+  }
+
+  /** Listens for an event until the returned function is called. */
+  on<E extends EventName>(event: E, callback: (data: EventData[E]) => void): () => void {
+    return this.transport.on(event, (data) => callback(data as EventData[E]));
+  }
+
+  /** Cancels and rejects every pending call. */
+  close(): void {
+    for (const id of [...this.pending.keys()]) {
+      this.cancel(id);
+      this.fail(id, new Error('display: client closed'));
+    }
+  }
+
+  private cancel(id: string): void {
+    if (this.pending.has(id)) {
+      void this.transport.cancel(id).catch(() => {});
+    }
+  }
+
+  private fail(id: string, error: Error): void {
+    const call = this.pending.get(id);
+    if (call) {
+      this.pending.delete(id);
+      call.reject(error);
+    }
+  }
+
+  private settle(reply: ActionReply): void {
+    const call = this.pending.get(reply.id);
+    if (!call) {
+      return;
+    }
+    this.pending.delete(reply.id);
+    if (reply.error) {
+      call.reject(new DisplayActionError(reply.action, reply.error.code, reply.error.message));
+    } else {
+      call.resolve(reply.result ?? null);
+    }
+  }
+}
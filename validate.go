@@ -0,0 +1,168 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalidPayload is returned when an action payload does not match the
+// protocol schema.
+var ErrInvalidPayload = errors.New("display: invalid payload")
+
+// ValidateAction checks a payload against the schema of a built-in action, as
+// published by `JSONSchema`. Actions without a schema, such as those added
+// with `RegisterAction`, are not checked.
+//
+// example:
+//
+//	err := display.ValidateAction("window.open", map[string]any{"name": 42})
+//	// errors.Is(err, display.ErrInvalidPayload) == true
+func ValidateAction(action string, payload any) error {
+	doc := protocolSchema()
+	spec, ok := doc.Actions[action]
+	if !ok || spec.Payload == nil {
+		return nil
+	}
+	return validateValue(doc, spec.Payload, payload)
+}
+
+// validatePayload checks a value against a named type of the protocol
+// schema.
+func validatePayload(def string, v any) error {
+	doc := protocolSchema()
+	return validateValue(doc, &jsonSchema{Ref: "#/$defs/" + def}, v)
+}
+
+// validateValue checks v, in its JSON form, against a schema.
+func validateValue(doc, s *jsonSchema, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return validateSchema(doc, s, decoded, "")
+}
+
+// validateSchema checks a decoded JSON value against the subset of JSON
+// Schema produced by `schemaBuilder`. Nulls are accepted anywhere, as they
+// decode to zero values.
+func validateSchema(doc, s *jsonSchema, v any, path string) error {
+	if v == nil || s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		return validateSchema(doc, doc.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")], v, path)
+	}
+	if len(s.AnyOf) > 0 {
+		var first error
+		for _, option := range s.AnyOf {
+			err := validateSchema(doc, option, v, path)
+			if err == nil {
+				return nil
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+	if s.Type != "" && !jsonTypeMatches(s.Type, v) {
+		return invalidAt(path, "expected %s, got %s", s.Type, jsonTypeOf(v))
+	}
+	if len(s.Enum) > 0 && !enumContains(s.Enum, v) {
+		return invalidAt(path, "%v is not one of %v", v, s.Enum)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return invalidAt(joinPath(path, name), "required")
+			}
+		}
+		for name, value := range v {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if extra, ok := s.AdditionalProperties.(*jsonSchema); ok {
+					prop = extra
+				} else if s.AdditionalProperties == false {
+					return invalidAt(joinPath(path, name), "unknown field")
+				}
+			}
+			if err := validateSchema(doc, prop, value, joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if err := validateSchema(doc, s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// invalidAt returns an `ErrInvalidPayload` for the value at path.
+func invalidAt(path, format string, args ...any) error {
+	if path == "" {
+		path = "payload"
+	}
+	return fmt.Errorf("%w: %s: %s", ErrInvalidPayload, path, fmt.Sprintf(format, args...))
+}
+
+// joinPath appends a property name to a path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonTypeMatches reports whether a decoded JSON value has a schema type.
+func jsonTypeMatches(typ string, v any) bool {
+	switch typ {
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	}
+	return jsonTypeOf(v) == typ
+}
+
+// jsonTypeOf returns the schema type of a decoded JSON value.
+func jsonTypeOf(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "null"
+}
+
+// enumContains reports whether v is one of the enum values.
+func enumContains(enum []any, v any) bool {
+	want, _ := json.Marshal(v)
+	for _, e := range enum {
+		if got, _ := json.Marshal(e); bytes.Equal(got, want) {
+			return true
+		}
+	}
+	return false
+}
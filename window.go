@@ -16,6 +16,27 @@ type WindowConfig struct {
 	MaximiseButtonState application.ButtonState
 	CloseButtonState    application.ButtonState
 	Frameless           bool
+	// Parent is the name of the window this window belongs to. Child windows
+	// close with their parent and move with it.
+	Parent string
+	// Modal blocks input to the parent window while this window is open.
+	Modal bool
+	// Screen is the screen the window opens on, see `WithScreen`.
+	Screen string
+	// Placement is the policy used to position the window.
+	Placement Placement
+	// Navigation restricts the URLs the window may navigate to.
+	Navigation *NavigationPolicy
+	// Capabilities are the actions the window may invoke. Nil grants
+	// `DefaultCapabilities`.
+	Capabilities []Capability
+	// FileDrop handles files dropped onto the window, see `WithFileDrop`.
+	FileDrop FileDropHandler `json:"-"`
+	// FileDropFilter restricts the files accepted by FileDrop.
+	FileDropFilter *FileDropFilter
+	// DefaultContextMenuDisabled suppresses the webview's built-in context
+	// menu.
+	DefaultContextMenuDisabled bool
 }
 
 // WindowOption is an interface for applying configuration options to a
@@ -109,3 +130,19 @@ func WithFrameless(frameless bool) WindowOption {
 		c.Frameless = frameless
 	})
 }
+
+// WithParent attaches the window to a parent window. The window opens centred
+// over its parent, moves with it, and closes when the parent closes.
+func WithParent(name string) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.Parent = name
+	})
+}
+
+// WithModal makes a child window modal, blocking input to its parent until
+// it is closed. It has no effect without `WithParent`.
+func WithModal(modal bool) WindowOption {
+	return WindowOptionFunc(func(c *WindowConfig) {
+		c.Modal = modal
+	})
+}
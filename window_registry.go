@@ -0,0 +1,343 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+var (
+	// ErrUnknownParent is returned when a window is opened with a parent that
+	// is not open.
+	ErrUnknownParent = errors.New("display: unknown parent window")
+	// ErrUnknownWindow is returned when an operation names a window that is
+	// not open.
+	ErrUnknownWindow = errors.New("display: unknown window")
+	// ErrWindowExists is returned when a window is opened with the name of a
+	// window that is already open.
+	ErrWindowExists = errors.New("display: window already open")
+)
+
+// WindowNode describes a window opened by the display service and the child
+// windows attached to it.
+type WindowNode struct {
+	Name     string       `json:"name"`
+	Parent   string       `json:"parent,omitempty"`
+	Modal    bool         `json:"modal,omitempty"`
+	Children []WindowNode `json:"children,omitempty"`
+}
+
+type windowEntry struct {
+	name     string
+	parent   string
+	modal    bool
+	children []string
+}
+
+// windowRegistry tracks the windows opened by the display service and their
+// parent/child relationships.
+type windowRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]*windowEntry
+	order   []string
+	// reserved names belong to windows the service creates for itself, such
+	// as the hidden tray window, which are not listed with the others.
+	reserved map[string]bool
+}
+
+// newWindowRegistry creates an empty registry.
+func newWindowRegistry() *windowRegistry {
+	return &windowRegistry{entries: map[string]*windowEntry{}, reserved: map[string]bool{}}
+}
+
+// reserve keeps name from being used by an opened window.
+func (r *windowRegistry) reserve(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reserved[name] = true
+}
+
+// add records a window. A window with a parent is attached as its child.
+// Names are unique: adding a name that is already registered or reserved
+// fails.
+func (r *windowRegistry) add(name, parent string, modal bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.entries[name]; exists || r.reserved[name] {
+		return fmt.Errorf("%w: %s", ErrWindowExists, name)
+	}
+	if parent != "" {
+		p, ok := r.entries[parent]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownParent, parent)
+		}
+		p.children = append(p.children, name)
+	}
+	r.order = append(r.order, name)
+	r.entries[name] = &windowEntry{name: name, parent: parent, modal: modal}
+	return nil
+}
+
+// unusedName returns prefix followed by the lowest number that does not name
+// a registered window.
+func (r *windowRegistry) unusedName(prefix string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s-%d", prefix, i)
+		if _, exists := r.entries[name]; !exists && !r.reserved[name] {
+			return name
+		}
+	}
+}
+
+// remove forgets a window and detaches it from its parent. It returns the
+// window's descendants, deepest first, which are removed with it.
+func (r *windowRegistry) remove(name string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[name]
+	if !ok {
+		return nil
+	}
+	if p, ok := r.entries[entry.parent]; ok {
+		p.children = deleteString(p.children, name)
+	}
+	descendants := r.descendantsLocked(name)
+	for _, child := range descendants {
+		delete(r.entries, child)
+		r.order = deleteString(r.order, child)
+	}
+	delete(r.entries, name)
+	r.order = deleteString(r.order, name)
+	return descendants
+}
+
+// descendants returns the descendants of name, deepest first.
+func (r *windowRegistry) descendants(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.descendantsLocked(name)
+}
+
+// descendantsLocked returns the descendants of name, deepest first. The
+// caller must hold r.mu.
+func (r *windowRegistry) descendantsLocked(name string) []string {
+	entry, ok := r.entries[name]
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, child := range entry.children {
+		result = append(result, r.descendantsLocked(child)...)
+		result = append(result, child)
+	}
+	return result
+}
+
+// has reports whether a window is registered.
+func (r *windowRegistry) has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.entries[name]
+	return ok
+}
+
+// parent returns the parent of a window.
+func (r *windowRegistry) parent(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	if !ok || entry.parent == "" {
+		return "", false
+	}
+	return entry.parent, true
+}
+
+// children returns the direct children of a window.
+func (r *windowRegistry) children(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if entry, ok := r.entries[name]; ok {
+		return append([]string(nil), entry.children...)
+	}
+	return nil
+}
+
+// isModal reports whether a window was opened as a modal child.
+func (r *windowRegistry) isModal(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	return ok && entry.modal
+}
+
+// modalChild returns the most recently opened modal child of a window.
+func (r *windowRegistry) modalChild(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	if !ok {
+		return "", false
+	}
+	for i := len(entry.children) - 1; i >= 0; i-- {
+		if child, ok := r.entries[entry.children[i]]; ok && child.modal {
+			return child.name, true
+		}
+	}
+	return "", false
+}
+
+// names returns every registered window in the order it was opened.
+func (r *windowRegistry) names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
+
+// tree returns the registered windows as a forest of top-level windows.
+func (r *windowRegistry) tree() []WindowNode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var roots []WindowNode
+	for _, name := range r.order {
+		if entry := r.entries[name]; entry.parent == "" {
+			roots = append(roots, r.nodeLocked(entry))
+		}
+	}
+	return roots
+}
+
+// nodeLocked builds the WindowNode for an entry. The caller must hold r.mu.
+func (r *windowRegistry) nodeLocked(entry *windowEntry) WindowNode {
+	node := WindowNode{Name: entry.name, Parent: entry.parent, Modal: entry.modal}
+	for _, child := range entry.children {
+		if c, ok := r.entries[child]; ok {
+			node.Children = append(node.Children, r.nodeLocked(c))
+		}
+	}
+	return node
+}
+
+// deleteString removes the first occurrence of value from values.
+func deleteString(values []string, value string) []string {
+	for i, v := range values {
+		if v == value {
+			return append(values[:i:i], values[i+1:]...)
+		}
+	}
+	return values
+}
+
+// centreOver returns the position that centres a width x height window over
+// the parent bounds.
+func centreOver(parent application.Rect, width, height int) (int, int) {
+	return parent.X + (parent.Width-width)/2, parent.Y + (parent.Height-height)/2
+}
+
+// WindowTree returns the windows opened by the display service, with child
+// windows nested under their parents.
+//
+// example:
+//
+//	for _, node := range displayService.WindowTree() {
+//		log.Println(node.Name, len(node.Children))
+//	}
+func (s *Service) WindowTree() []WindowNode {
+	return s.windows.tree()
+}
+
+// trackWindow wires up a newly opened window, already recorded in s.windows,
+// and for child windows the behaviour that ties them to their parent: closing
+// with it, following it when it moves, and blocking its input while a modal
+// child is open.
+func (s *Service) trackWindow(window application.Window, config *WindowConfig) error {
+	window.OnWindowEvent(events.Common.WindowClosing, func(*application.WindowEvent) {
+		s.revokeCapabilities(config.Name, window.ID())
+		s.untrackWindow(config.Name)
+	})
+	s.recordWindowEvents(window)
+	if config.Parent == "" {
+		return nil
+	}
+
+	parent, ok := s.app.Window.GetByName(config.Parent)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownParent, config.Parent)
+	}
+	s.followParent(parent)
+	if config.Modal {
+		parent.SetEnabled(false)
+	}
+	return nil
+}
+
+// untrackWindow forgets a closing window, closes its descendants and
+// re-enables its parent if it was modal.
+func (s *Service) untrackWindow(name string) {
+	parentName, hasParent := s.windows.parent(name)
+	wasModal := s.windows.isModal(name)
+	s.forgetNavigationPolicy(name)
+	s.forgetFileDrop(name)
+	s.forgetContextMenu(name)
+	s.background.forget(name)
+	for _, child := range s.windows.remove(name) {
+		if window, ok := s.app.Window.GetByName(child); ok {
+			window.Close()
+		}
+	}
+	if !hasParent || !wasModal {
+		return
+	}
+	parent, ok := s.app.Window.GetByName(parentName)
+	if !ok {
+		return
+	}
+	if _, stillBlocked := s.windows.modalChild(parentName); !stillBlocked {
+		parent.SetEnabled(true)
+		parent.Focus()
+	}
+}
+
+// followParent makes a parent's children move with it and redirects focus to
+// a modal child. It is installed once per parent window.
+func (s *Service) followParent(parent application.Window) {
+	s.windowHooksMu.Lock()
+	defer s.windowHooksMu.Unlock()
+	name := parent.Name()
+	if s.windowHooks[name] {
+		return
+	}
+	s.windowHooks[name] = true
+
+	lastX, lastY := parent.Position()
+	parent.OnWindowEvent(events.Common.WindowDidMove, func(*application.WindowEvent) {
+		x, y := parent.Position()
+		dx, dy := x-lastX, y-lastY
+		lastX, lastY = x, y
+		if dx == 0 && dy == 0 {
+			return
+		}
+		for _, childName := range s.windows.children(name) {
+			if child, ok := s.app.Window.GetByName(childName); ok {
+				cx, cy := child.Position()
+				child.SetPosition(cx+dx, cy+dy)
+			}
+		}
+	})
+	parent.OnWindowEvent(events.Common.WindowFocus, func(*application.WindowEvent) {
+		if modal, ok := s.windows.modalChild(name); ok {
+			if child, ok := s.app.Window.GetByName(modal); ok {
+				child.Focus()
+			}
+		}
+	})
+	parent.OnWindowEvent(events.Common.WindowClosing, func(*application.WindowEvent) {
+		s.windowHooksMu.Lock()
+		delete(s.windowHooks, name)
+		s.windowHooksMu.Unlock()
+	})
+}
@@ -0,0 +1,147 @@
+package display
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestWindowRegistry(t *testing.T) {
+	r := newWindowRegistry()
+	for _, w := range []struct {
+		name, parent string
+		modal        bool
+	}{
+		{"main", "", false},
+		{"settings", "main", false},
+		{"confirm", "settings", true},
+		{"inspector", "main", false},
+		{"system-tray", "", false},
+	} {
+		if err := r.add(w.name, w.parent, w.modal); err != nil {
+			t.Fatalf("add(%q) error = %v", w.name, err)
+		}
+	}
+
+	if err := r.add("orphan", "missing", false); !errors.Is(err, ErrUnknownParent) {
+		t.Errorf("add() with unknown parent error = %v, want ErrUnknownParent", err)
+	}
+	if err := r.add("main", "main", false); err == nil {
+		t.Error("add() with itself as parent succeeded")
+	}
+	// Opening a name again must not detach its children or attach it to
+	// its parent twice.
+	if err := r.add("settings", "main", false); !errors.Is(err, ErrWindowExists) {
+		t.Errorf("add() of an open window error = %v, want ErrWindowExists", err)
+	}
+	if got := r.unusedName("window"); got != "window-1" {
+		t.Errorf("unusedName() = %q, want window-1", got)
+	}
+
+	want := []WindowNode{
+		{Name: "main", Children: []WindowNode{
+			{Name: "settings", Parent: "main", Children: []WindowNode{
+				{Name: "confirm", Parent: "settings", Modal: true},
+			}},
+			{Name: "inspector", Parent: "main"},
+		}},
+		{Name: "system-tray"},
+	}
+	if got := r.tree(); !reflect.DeepEqual(got, want) {
+		t.Errorf("tree() = %+v, want %+v", got, want)
+	}
+
+	if modal, ok := r.modalChild("settings"); !ok || modal != "confirm" {
+		t.Errorf("modalChild(settings) = %q, %v, want confirm", modal, ok)
+	}
+	if _, ok := r.modalChild("main"); ok {
+		t.Error("modalChild(main) reported a modal child")
+	}
+
+	if got, want := r.remove("main"), []string{"confirm", "settings", "inspector"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("remove(main) = %v, want %v", got, want)
+	}
+	if got, want := r.names(), []string{"system-tray"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names() after remove = %v, want %v", got, want)
+	}
+	if r.remove("main") != nil {
+		t.Error("remove() of a missing window returned descendants")
+	}
+}
+
+func TestWindowRegistryReserved(t *testing.T) {
+	r := newWindowRegistry()
+	r.reserve("window-1")
+	if err := r.add("window-1", "", false); !errors.Is(err, ErrWindowExists) {
+		t.Errorf("add() of a reserved name error = %v, want ErrWindowExists", err)
+	}
+	if got := r.unusedName("window"); got != "window-2" {
+		t.Errorf("unusedName() = %q, want window-2", got)
+	}
+	if got := r.names(); len(got) != 0 {
+		t.Errorf("names() = %v, want reserved names left out", got)
+	}
+
+	// The tray window's name cannot be taken once the tray is set up.
+	s, _ := New()
+	s.windows.reserve(trayWindowName)
+	if err := s.OpenWindow(WithName(trayWindowName)); !errors.Is(err, ErrWindowExists) {
+		t.Errorf("OpenWindow(%s) error = %v, want ErrWindowExists", trayWindowName, err)
+	}
+}
+
+func TestWindowRegistry_RemoveChild(t *testing.T) {
+	r := newWindowRegistry()
+	_ = r.add("main", "", false)
+	_ = r.add("dialog", "main", true)
+	if parent, ok := r.parent("dialog"); !ok || parent != "main" {
+		t.Errorf("parent(dialog) = %q, %v, want main", parent, ok)
+	}
+	r.remove("dialog")
+	if got := r.children("main"); len(got) != 0 {
+		t.Errorf("children(main) after removing the child = %v", got)
+	}
+	if _, ok := r.modalChild("main"); ok {
+		t.Error("modalChild(main) still reports the removed dialog")
+	}
+}
+
+func TestCentreOver(t *testing.T) {
+	parent := application.Rect{X: 100, Y: 50, Width: 1280, Height: 800}
+	if x, y := centreOver(parent, 640, 400); x != 420 || y != 250 {
+		t.Errorf("centreOver() = (%d, %d), want (420, 250)", x, y)
+	}
+}
+
+func TestWithParent(t *testing.T) {
+	config := buildWindowConfig(WithName("confirm"), WithParent("main"), WithModal(true))
+	if config.Parent != "main" || !config.Modal {
+		t.Errorf("buildWindowConfig() = %+v, want a modal child of main", config)
+	}
+	want := application.WebviewWindowOptions{
+		Name:   "confirm",
+		Title:  "Core",
+		Width:  1280,
+		Height: 800,
+		URL:    "/",
+	}
+	if got := config.wailsOptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("wailsOptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWindowRegistryDescendants(t *testing.T) {
+	r := newWindowRegistry()
+	r.add("main", "", false)
+	r.add("settings", "main", false)
+	r.add("confirm", "settings", true)
+	r.add("about", "", false)
+	if got, want := r.descendants("main"), []string{"confirm", "settings"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("descendants(main) = %q, want %q", got, want)
+	}
+	if got := r.descendants("about"); len(got) != 0 {
+		t.Errorf("descendants(about) = %q", got)
+	}
+}